@@ -152,6 +152,113 @@ const docTemplate = `{
                 }
             }
         },
+        "/admin/label/copies": {
+            "get": {
+                "description": "根据关系、确认状态、链接分页查询采集器发现的镜像与分叉仓库；镜像及经确认的分叉仓库按其规范仓库评分",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "label"
+                ],
+                "summary": "查询镜像与分叉仓库列表",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "关系（mirror, fork），为空则查询全部",
+                        "name": "relation",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "是否已确认，为空则查询全部",
+                        "name": "confirmed",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "链接过滤",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "跳过数量",
+                        "name": "skip",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "返回数量",
+                        "name": "take",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.PageDTO-model_GitLinkCopyDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/label/copies/confirm": {
+            "put": {
+                "description": "确认或取消确认仓库是其规范仓库的副本，经确认的分叉仓库按其规范仓库评分，规范仓库变化时确认失效",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "label"
+                ],
+                "summary": "确认分叉仓库",
+                "parameters": [
+                    {
+                        "description": "确认参数",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.ConfirmGitLinkCopyReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
         "/admin/label/distributions": {
             "get": {
                 "description": "根据发行版、链接、置信度等条件分页查询包列表",
@@ -335,6 +442,113 @@ const docTemplate = `{
                 }
             }
         },
+        "/admin/label/packages": {
+            "get": {
+                "description": "根据状态、生态、包名分页查询由清单文件索引的语言生态包仓库链接，及其与包注册中心的比对结果",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "label"
+                ],
+                "summary": "查询语言生态包链接列表",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "比对状态（agree, disagree, unregistered），为空则查询全部",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "生态名称",
+                        "name": "ecosystem",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "包名过滤",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "跳过数量",
+                        "name": "skip",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "返回数量",
+                        "name": "take",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.PageDTO-model_PackageLinkDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/label/packages/gitlink": {
+            "put": {
+                "description": "设置人工确认的语言生态包 Git 仓库链接，链接为空则清除标注。标注仅供审核，不参与包到仓库的解析",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "label"
+                ],
+                "summary": "标注语言生态包的 Git 链接",
+                "parameters": [
+                    {
+                        "description": "Git 链接参数",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.UpdatePackageGitLinkReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
         "/admin/session/github/callback": {
             "get": {
                 "description": "Handles the GitHub OAuth callback and returns JWT token if user is authorized",
@@ -877,6 +1091,35 @@ const docTemplate = `{
                 }
             }
         },
+        "/copies": {
+            "get": {
+                "description": "Get the canonical repository of a git link and its mirrors and forks\nNOTE: The mirrors, and the forks confirmed by a labeler, are scored as their canonical repository",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get mirrors and forks",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Git link",
+                        "name": "link",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.GitLinkCopiesDTO"
+                        }
+                    }
+                }
+            }
+        },
         "/histories": {
             "get": {
                 "description": "Get score histories by git link",
@@ -946,6 +1189,19 @@ const docTemplate = `{
                         "description": "Include details",
                         "name": "detail",
                         "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "public-domain",
+                            "permissive",
+                            "weak-copyleft",
+                            "copyleft",
+                            "other"
+                        ],
+                        "type": "string",
+                        "description": "Only projects with a license of the family",
+                        "name": "license_family",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -1027,6 +1283,35 @@ const docTemplate = `{
                     }
                 }
             }
+        },
+        "/results/{scoreid}/explain": {
+            "get": {
+                "description": "Get the normalized value, weight and contribution of every metric of a score",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Explain a score",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Score ID",
+                        "name": "scoreid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ResultExplainDTO"
+                        }
+                    }
+                }
+            }
         }
     },
     "definitions": {
@@ -1034,6 +1319,20 @@ const docTemplate = `{
             "type": "object",
             "additionalProperties": {}
         },
+        "model.ConfirmGitLinkCopyReq": {
+            "type": "object",
+            "required": [
+                "link"
+            ],
+            "properties": {
+                "confirmed": {
+                    "type": "boolean"
+                },
+                "link": {
+                    "type": "string"
+                }
+            }
+        },
         "model.DistributionPackageDTO": {
             "type": "object",
             "properties": {
@@ -1100,12 +1399,28 @@ const docTemplate = `{
         "model.GitFileStatisticsResultDTO": {
             "type": "object",
             "properties": {
+                "evicted": {
+                    "type": "integer"
+                },
                 "fail": {
                     "type": "integer"
                 },
+                "mirrors": {
+                    "type": "integer"
+                },
                 "neverSuccess": {
                     "type": "integer"
                 },
+                "partial": {
+                    "type": "integer"
+                },
+                "shallow": {
+                    "type": "integer"
+                },
+                "storage": {
+                    "description": "Storage is the size in bytes of all the mirrors",
+                    "type": "integer"
+                },
                 "success": {
                     "type": "integer"
                 },
@@ -1162,6 +1477,55 @@ const docTemplate = `{
                 }
             }
         },
+        "model.GitLinkCopiesDTO": {
+            "type": "object",
+            "properties": {
+                "canonical": {
+                    "description": "Canonical is the canonical repository of the link, the link itself if\nit is not a copy",
+                    "type": "string"
+                },
+                "copies": {
+                    "description": "Copies are the mirrors and forks of the canonical repository",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.GitLinkCopyDTO"
+                    }
+                },
+                "link": {
+                    "type": "string"
+                },
+                "relation": {
+                    "description": "Relation is the relation of the link to the canonical repository, null\nif it is not a copy",
+                    "type": "string"
+                },
+                "scored": {
+                    "description": "Scored is whether the link is scored as the canonical repository",
+                    "type": "boolean"
+                }
+            }
+        },
+        "model.GitLinkCopyDTO": {
+            "type": "object",
+            "properties": {
+                "canonical": {
+                    "type": "string"
+                },
+                "confirmed": {
+                    "description": "Confirmed is whether a labeler confirmed the copy, the mirrors and the\nconfirmed forks are scored as the canonical repository",
+                    "type": "boolean"
+                },
+                "link": {
+                    "type": "string"
+                },
+                "relation": {
+                    "description": "mirror: same history as the canonical repository, fork: most of it",
+                    "type": "string"
+                },
+                "updateTime": {
+                    "type": "string"
+                }
+            }
+        },
         "model.KillToolInstanceReq": {
             "type": "object",
             "required": [
@@ -1185,6 +1549,32 @@ const docTemplate = `{
                 }
             }
         },
+        "model.PackageLinkDTO": {
+            "type": "object",
+            "properties": {
+                "ecosystem": {
+                    "type": "string"
+                },
+                "labelLink": {
+                    "type": "string"
+                },
+                "manifestLinks": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "package": {
+                    "type": "string"
+                },
+                "registryLink": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
         "model.PageDTO-model_DistributionPackageDTO": {
             "type": "object",
             "properties": {
@@ -1225,6 +1615,46 @@ const docTemplate = `{
                 }
             }
         },
+        "model.PageDTO-model_GitLinkCopyDTO": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.GitLinkCopyDTO"
+                    }
+                },
+                "start": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.PageDTO-model_PackageLinkDTO": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.PackageLinkDTO"
+                    }
+                },
+                "start": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
         "model.PageDTO-model_RankingResultDTO": {
             "type": "object",
             "properties": {
@@ -1396,12 +1826,104 @@ const docTemplate = `{
                 }
             }
         },
+        "model.ResultExplainDTO": {
+            "type": "object",
+            "properties": {
+                "dimensions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ResultExplainDimensionDTO"
+                    }
+                },
+                "link": {
+                    "type": "string"
+                },
+                "profileHash": {
+                    "type": "string"
+                },
+                "profileName": {
+                    "type": "string"
+                },
+                "round": {
+                    "type": "integer"
+                },
+                "score": {
+                    "type": "number"
+                },
+                "scoreID": {
+                    "type": "integer"
+                },
+                "updateTime": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.ResultExplainDimensionDTO": {
+            "type": "object",
+            "properties": {
+                "contribution": {
+                    "type": "number"
+                },
+                "dimension": {
+                    "type": "string"
+                },
+                "metric": {
+                    "type": "string"
+                },
+                "metrics": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ResultExplainMetricDTO"
+                    }
+                },
+                "normalized": {
+                    "type": "number"
+                },
+                "value": {
+                    "type": "number"
+                },
+                "weight": {
+                    "type": "number"
+                }
+            }
+        },
+        "model.ResultExplainMetricDTO": {
+            "type": "object",
+            "properties": {
+                "contribution": {
+                    "type": "number"
+                },
+                "metric": {
+                    "type": "string"
+                },
+                "normalized": {
+                    "type": "number"
+                },
+                "value": {
+                    "type": "number"
+                },
+                "weight": {
+                    "type": "number"
+                }
+            }
+        },
         "model.ResultGitMetadataDTO": {
             "type": "object",
             "properties": {
+                "busFactor50": {
+                    "description": "minimum number of contributors authoring 50% and 80% of the commits\nof the last 12 months",
+                    "type": "integer"
+                },
+                "busFactor80": {
+                    "type": "integer"
+                },
                 "commitFrequency": {
                     "type": "number"
                 },
+                "commitGini": {
+                    "description": "gini coefficient of the commits of the contributors of the last 12\nmonths",
+                    "type": "number"
+                },
                 "contributorCount": {
                     "type": "integer"
                 },
@@ -1414,15 +1936,57 @@ const docTemplate = `{
                         "type": "string"
                     }
                 },
+                "lastRelease": {
+                    "description": "time of the last release, null if never released",
+                    "type": "string"
+                },
                 "license": {
                     "type": "array",
                     "items": {
                         "type": "string"
                     }
                 },
+                "licenseCoverage": {
+                    "description": "percentage of the source files under each license expression",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
+                    }
+                },
+                "licenseExpression": {
+                    "description": "spdx expression of the licenses of the files of the repository",
+                    "type": "string"
+                },
+                "licenseFamilies": {
+                    "description": "families of the licenses found, e.g. permissive or copyleft",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "medianReleaseInterval": {
+                    "description": "median number of days between two consecutive releases, null if\nreleased less than twice",
+                    "type": "number"
+                },
                 "orgCount": {
                     "type": "integer"
                 },
+                "releases365d": {
+                    "description": "number of releases of the last 365 days",
+                    "type": "integer"
+                },
+                "semverTags": {
+                    "description": "number of tags looking like a version, including pre-releases",
+                    "type": "integer"
+                },
+                "topMaintainersLastCommit": {
+                    "description": "last commit of any of the top 3 contributors of the whole history",
+                    "type": "string"
+                },
+                "topOrgShare": {
+                    "description": "share of the commits of the last 12 months authored by the top\norganization",
+                    "type": "number"
+                },
                 "updateTime": {
                     "type": "string"
                 },
@@ -1576,6 +2140,24 @@ const docTemplate = `{
                 }
             }
         },
+        "model.UpdatePackageGitLinkReq": {
+            "type": "object",
+            "required": [
+                "ecosystem",
+                "packageName"
+            ],
+            "properties": {
+                "ecosystem": {
+                    "type": "string"
+                },
+                "link": {
+                    "type": "string"
+                },
+                "packageName": {
+                    "type": "string"
+                }
+            }
+        },
         "model.UpdateWorkflowStatusReq": {
             "type": "object",
             "required": [
@@ -1601,6 +2183,32 @@ const docTemplate = `{
                 }
             }
         },
+        "rpc.HostStatusDTO": {
+            "type": "object",
+            "properties": {
+                "backoffUntil": {
+                    "type": "string"
+                },
+                "concurrency": {
+                    "type": "integer"
+                },
+                "host": {
+                    "type": "string"
+                },
+                "rate": {
+                    "type": "number"
+                },
+                "running": {
+                    "type": "integer"
+                },
+                "throttled": {
+                    "type": "integer"
+                },
+                "waiting": {
+                    "type": "integer"
+                }
+            }
+        },
         "rpc.RoundDTO": {
             "type": "object",
             "properties": {
@@ -1652,6 +2260,12 @@ const docTemplate = `{
                         "$ref": "#/definitions/rpc.RunningTaskDTO"
                     }
                 },
+                "hosts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/rpc.HostStatusDTO"
+                    }
+                },
                 "isRunning": {
                     "type": "boolean"
                 },
@@ -1660,6 +2274,29 @@ const docTemplate = `{
                     "items": {
                         "type": "string"
                     }
+                },
+                "storage": {
+                    "$ref": "#/definitions/rpc.StorageStatusDTO"
+                }
+            }
+        },
+        "rpc.StorageStatusDTO": {
+            "type": "object",
+            "properties": {
+                "budget": {
+                    "type": "integer"
+                },
+                "checkTime": {
+                    "type": "string"
+                },
+                "freed": {
+                    "type": "integer"
+                },
+                "prune": {
+                    "type": "string"
+                },
+                "used": {
+                    "type": "integer"
                 }
             }
         },
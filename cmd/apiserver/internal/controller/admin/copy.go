@@ -0,0 +1,96 @@
+package admin
+
+import (
+	"errors"
+	"slices"
+
+	"github.com/HUSTSecLab/OpenSift/cmd/apiserver/internal/model"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/samber/lo"
+)
+
+var allowedGitLinkCopyRelations = []repository.GitLinkCopyRelation{
+	repository.GitLinkMirror,
+	repository.GitLinkFork,
+}
+
+// getGitLinkCopies godoc
+// @Summary      查询镜像与分叉仓库列表
+// @Description  根据关系、确认状态、链接分页查询采集器发现的镜像与分叉仓库；镜像及经确认的分叉仓库按其规范仓库评分
+// @Tags         label
+// @Produce      json
+// @Param        relation   query     string  false  "关系（mirror, fork），为空则查询全部"
+// @Param        confirmed  query     bool    false  "是否已确认，为空则查询全部"
+// @Param        search     query     string  false  "链接过滤"
+// @Param        skip       query     int     false  "跳过数量"
+// @Param        take       query     int     false  "返回数量"
+// @Success      200  {object}  model.PageDTO[model.GitLinkCopyDTO]
+// @Failure      400  {object}  string
+// @Failure      500  {object}  string
+// @Router       /admin/label/copies [get]
+func getGitLinkCopies(c *gin.Context) {
+	type Q struct {
+		Skip      int    `form:"skip"`
+		Take      int    `form:"take"`
+		Relation  string `form:"relation"`
+		Confirmed *bool  `form:"confirmed"`
+		Search    string `form:"search"`
+	}
+	var q = Q{
+		Skip: 0,
+		Take: 100,
+	}
+	if err := c.ShouldBindQuery(&q); err != nil {
+		c.JSON(400, gin.H{"error": "Invalid query parameters: " + err.Error()})
+		return
+	}
+	relation := repository.GitLinkCopyRelation(q.Relation)
+	if relation != "" && !slices.Contains(allowedGitLinkCopyRelations, relation) {
+		c.JSON(400, gin.H{"error": "Invalid relation: " + q.Relation})
+		return
+	}
+
+	repo := repository.NewGitLinkCopyRepository(storage.GetDefaultAppDatabaseContext())
+	items, cnt, err := repo.QueryWithFilter(relation, q.Confirmed, q.Search, q.Skip, q.Take)
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to query copies: " + err.Error()})
+		return
+	}
+	copies := lo.Map(slices.Collect(items), func(i *repository.GitLinkCopy, _ int) *model.GitLinkCopyDTO {
+		return model.GitLinkCopyDOToDTO(i)
+	})
+
+	c.JSON(200, model.NewPageDTO(cnt, q.Skip, q.Take, copies))
+}
+
+// confirmGitLinkCopy godoc
+// @Summary      确认分叉仓库
+// @Description  确认或取消确认仓库是其规范仓库的副本，经确认的分叉仓库按其规范仓库评分，规范仓库变化时确认失效
+// @Tags         label
+// @Accept       json
+// @Produce      json
+// @Param        data  body      model.ConfirmGitLinkCopyReq  true  "确认参数"
+// @Success      204   {object}  nil
+// @Failure      400   {object}  string
+// @Failure      500   {object}  string
+// @Router       /admin/label/copies/confirm [put]
+func confirmGitLinkCopy(c *gin.Context) {
+	var req model.ConfirmGitLinkCopyReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	repo := repository.NewGitLinkCopyRepository(storage.GetDefaultAppDatabaseContext())
+	if err := repo.Confirm(req.Link, req.Confirmed); err != nil {
+		if errors.Is(err, repository.ErrInvalidInput) {
+			c.JSON(400, gin.H{"error": "Unknown copy: " + req.Link})
+			return
+		}
+		c.JSON(500, gin.H{"error": "Failed to confirm copy: " + err.Error()})
+		return
+	}
+	c.Status(204) // No Content
+}
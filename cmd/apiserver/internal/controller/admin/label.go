@@ -6,6 +6,7 @@ import (
 	"slices"
 
 	"github.com/HUSTSecLab/OpenSift/cmd/apiserver/internal/model"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/url"
 	"github.com/HUSTSecLab/OpenSift/pkg/llm"
 	"github.com/HUSTSecLab/OpenSift/pkg/storage"
 	"github.com/HUSTSecLab/OpenSift/pkg/storage/repository"
@@ -53,6 +54,15 @@ func updateDistributionGitLink(c *gin.Context) {
 		return
 	}
 
+	if req.Link != nil && *req.Link != "" {
+		link, err := url.Canonicalize(*req.Link)
+		if err != nil {
+			c.JSON(400, gin.H{"error": "Invalid git link: " + err.Error()})
+			return
+		}
+		req.Link = &link
+	}
+
 	repo := repository.NewDistPackageRepository(storage.GetDefaultAppDatabaseContext(), repository.DistPackageTablePrefix(req.Distribution))
 
 	if err := repo.UpdateGitLink(req.PackageName, req.Link, req.Confidence); err != nil {
@@ -210,4 +220,8 @@ func registLabel(g gin.IRoutes) {
 	g.PUT("/label/distributions/gitlink", updateDistributionGitLink)
 	g.GET("/label/distributions", getDistributionPackages)
 	g.POST("/label/distributions/ai-completion", getDistributionAICompletion)
+	g.GET("/label/packages", getPackageLinks)
+	g.PUT("/label/packages/gitlink", updatePackageGitLink)
+	g.GET("/label/copies", getGitLinkCopies)
+	g.PUT("/label/copies/confirm", confirmGitLinkCopy)
 }
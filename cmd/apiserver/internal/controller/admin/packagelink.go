@@ -0,0 +1,110 @@
+package admin
+
+import (
+	"errors"
+	"slices"
+
+	"github.com/HUSTSecLab/OpenSift/cmd/apiserver/internal/model"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/url"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/samber/lo"
+)
+
+var allowedPackageLinkStatuses = []repository.PackageLinkStatus{
+	repository.PackageLinkAgree,
+	repository.PackageLinkDisagree,
+	repository.PackageLinkUnregistered,
+}
+
+// getPackageLinks godoc
+// @Summary      查询语言生态包链接列表
+// @Description  根据状态、生态、包名分页查询由清单文件索引的语言生态包仓库链接，及其与包注册中心的比对结果
+// @Tags         label
+// @Produce      json
+// @Param        status     query     string  false  "比对状态（agree, disagree, unregistered），为空则查询全部"
+// @Param        ecosystem  query     string  false  "生态名称"
+// @Param        search     query     string  false  "包名过滤"
+// @Param        skip       query     int     false  "跳过数量"
+// @Param        take       query     int     false  "返回数量"
+// @Success      200  {object}  model.PageDTO[model.PackageLinkDTO]
+// @Failure      400  {object}  string
+// @Failure      500  {object}  string
+// @Router       /admin/label/packages [get]
+func getPackageLinks(c *gin.Context) {
+	type Q struct {
+		Skip      int    `form:"skip"`
+		Take      int    `form:"take"`
+		Status    string `form:"status"`
+		Ecosystem string `form:"ecosystem"`
+		Search    string `form:"search"`
+	}
+	var q = Q{
+		Skip: 0,
+		Take: 100,
+	}
+	if err := c.ShouldBindQuery(&q); err != nil {
+		c.JSON(400, gin.H{"error": "Invalid query parameters: " + err.Error()})
+		return
+	}
+	status := repository.PackageLinkStatus(q.Status)
+	if status != "" && !slices.Contains(allowedPackageLinkStatuses, status) {
+		c.JSON(400, gin.H{"error": "Invalid status: " + q.Status})
+		return
+	}
+
+	repo := repository.NewPackageLinkRepository(storage.GetDefaultAppDatabaseContext())
+	items, cnt, err := repo.QueryWithFilter(status, q.Ecosystem, q.Search, q.Skip, q.Take)
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to query package links: " + err.Error()})
+		return
+	}
+	links := lo.Map(slices.Collect(items), func(i *repository.PackageLink, _ int) *model.PackageLinkDTO {
+		return model.ToPackageLinkDTO(i)
+	})
+
+	c.JSON(200, model.NewPageDTO(cnt, q.Skip, q.Take, links))
+}
+
+// updatePackageGitLink godoc
+// @Summary      标注语言生态包的 Git 链接
+// @Description  设置人工确认的语言生态包 Git 仓库链接，链接为空则清除标注。标注仅供审核，不参与包到仓库的解析
+// @Tags         label
+// @Accept       json
+// @Produce      json
+// @Param        data  body      model.UpdatePackageGitLinkReq  true  "Git 链接参数"
+// @Success      204   {object}  nil
+// @Failure      400   {object}  string
+// @Failure      500   {object}  string
+// @Router       /admin/label/packages/gitlink [put]
+func updatePackageGitLink(c *gin.Context) {
+	var req model.UpdatePackageGitLinkReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	if req.Link != nil && *req.Link == "" {
+		req.Link = nil
+	}
+	if req.Link != nil {
+		link, err := url.Canonicalize(*req.Link)
+		if err != nil {
+			c.JSON(400, gin.H{"error": "Invalid git link: " + err.Error()})
+			return
+		}
+		req.Link = &link
+	}
+
+	repo := repository.NewPackageLinkRepository(storage.GetDefaultAppDatabaseContext())
+	if err := repo.UpdateLabel(req.Ecosystem, req.PackageName, req.Link); err != nil {
+		if errors.Is(err, repository.ErrInvalidInput) {
+			c.JSON(400, gin.H{"error": "Unknown package: " + req.Ecosystem + "/" + req.PackageName})
+			return
+		}
+		c.JSON(500, gin.H{"error": "Failed to update git link: " + err.Error()})
+		return
+	}
+	c.Status(204) // No Content
+}
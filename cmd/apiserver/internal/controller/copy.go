@@ -0,0 +1,67 @@
+package controller
+
+import (
+	"github.com/HUSTSecLab/OpenSift/cmd/apiserver/internal/model"
+	"github.com/HUSTSecLab/OpenSift/pkg/logger"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/repository"
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Get mirrors and forks
+// @Description Get the canonical repository of a git link and its mirrors and forks
+// @Description NOTE: The mirrors, and the forks confirmed by a labeler, are scored as their canonical repository
+// @Accept json
+// @Produce json
+// @Success 200 {object} model.GitLinkCopiesDTO
+// @Router /copies [get]
+// @Param link query string true "Git link"
+func copiesHandler(c *gin.Context) {
+	r := repository.NewGitLinkCopyRepository(storage.GetDefaultAppDatabaseContext())
+
+	type query struct {
+		Link string `form:"link"`
+	}
+
+	var q query
+
+	if err := c.ShouldBindQuery(&q); err != nil || q.Link == "" {
+		c.JSON(400, "Invalid query parameters")
+		return
+	}
+
+	ret := model.GitLinkCopiesDTO{
+		GitLink:   q.Link,
+		Canonical: q.Link,
+		Copies:    make([]model.GitLinkCopyDTO, 0),
+	}
+
+	linkCopy, err := r.QueryByLink(q.Link)
+	if err != nil {
+		logger.Error("Error occurred when querying copy", err)
+		c.JSON(500, "Error occurred when querying copy")
+		return
+	}
+	if linkCopy != nil {
+		ret.Canonical = *linkCopy.Canonical
+		relation := string(*linkCopy.Relation)
+		ret.Relation = &relation
+		ret.Scored = *linkCopy.Relation == repository.GitLinkMirror || *linkCopy.Confirmed
+	}
+
+	copies, err := r.QueryByCanonical(ret.Canonical)
+	if err != nil {
+		logger.Error("Error occurred when querying copies", err)
+		c.JSON(500, "Error occurred when querying copies")
+		return
+	}
+	for v := range copies {
+		ret.Copies = append(ret.Copies, *model.GitLinkCopyDOToDTO(v))
+	}
+
+	c.JSON(200, ret)
+}
+
+func registCopy(e gin.IRouter) {
+	e.GET("/copies", copiesHandler)
+}
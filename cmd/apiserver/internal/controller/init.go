@@ -7,5 +7,6 @@ import (
 
 func Regist(e gin.IRouter) {
 	registResult(e)
+	registCopy(e)
 	admin.Regist(e)
 }
@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"iter"
 	"slices"
 	"strconv"
 	"time"
@@ -182,6 +183,45 @@ func resultHandler(c *gin.Context) {
 	c.JSON(200, ret)
 }
 
+// @Summary Explain a score
+// @Description Get the normalized value, weight and contribution of every metric of a score
+// @Accept json
+// @Produce json
+// @Success 200 {object} model.ResultExplainDTO
+// @Router /results/{scoreid}/explain [get]
+// @Param scoreid path int true "Score ID"
+func resultExplainHandler(c *gin.Context) {
+	r := repository.NewResultRepository(storage.GetDefaultAppDatabaseContext())
+
+	scoreidStr := c.Param("scoreid")
+	scoreid, err := strconv.Atoi(scoreidStr)
+
+	if err != nil {
+		c.JSON(400, "Invalid query parameters")
+		return
+	}
+
+	result, err := r.GetByScoreID(scoreid)
+	if err != nil {
+		logger.Error("Error occurred when querying result", err)
+		c.JSON(500, "Error occurred when querying result")
+		return
+	}
+	if result == nil {
+		c.JSON(404, "Score not found")
+		return
+	}
+
+	breakdown, err := r.QueryBreakdownByScoreID(scoreid)
+	if err != nil {
+		logger.Error("Error occurred when querying score breakdown", err)
+		c.JSON(500, "Error occurred when querying score breakdown")
+		return
+	}
+
+	c.JSON(200, model.ResultExplainDOToDTO(result, slices.Collect(breakdown)))
+}
+
 // @Summary Get ranking results
 // @Description Get ranking results, optionally including all details
 // @Accept json
@@ -191,12 +231,14 @@ func resultHandler(c *gin.Context) {
 // @Param start query int false "Skip count"
 // @Param take query int false "Take count"
 // @Param detail query bool false "Include details"
+// @Param license_family query string false "Only projects with a license of the family" Enums(public-domain, permissive, weak-copyleft, copyleft, other)
 func rankingHandler(c *gin.Context) {
 	r := repository.NewResultRepository(storage.GetDefaultAppDatabaseContext())
 	type query struct {
-		Skip   int  `form:"start"`
-		Take   int  `form:"take"`
-		Detail bool `form:"detail"`
+		Skip          int    `form:"start"`
+		Take          int    `form:"take"`
+		Detail        bool   `form:"detail"`
+		LicenseFamily string `form:"license_family" binding:"omitempty,oneof=public-domain permissive weak-copyleft copyleft other"`
 	}
 
 	var q query = query{
@@ -214,7 +256,13 @@ func rankingHandler(c *gin.Context) {
 		q.Take = 1000
 	}
 
-	rankingCache, err := r.QueryRankingCache(q.Skip, q.Take)
+	var rankingCache iter.Seq[*repository.RankingResult]
+	var err error
+	if q.LicenseFamily != "" {
+		rankingCache, err = r.QueryRankingCacheByLicenseFamily(q.LicenseFamily, q.Skip, q.Take)
+	} else {
+		rankingCache, err = r.QueryRankingCache(q.Skip, q.Take)
+	}
 
 	if err != nil {
 		logger.Error("Error occurred when querying ranking cache", err)
@@ -288,6 +336,7 @@ func cacheRankingPeriodically() {
 func registResult(e gin.IRouter) {
 	e.GET("/results", resultsHandler)
 	e.GET("/results/:scoreid", resultHandler)
+	e.GET("/results/:scoreid/explain", resultExplainHandler)
 	e.GET("/histories", historiesHandler)
 	e.GET("/rankings", rankingHandler)
 
@@ -0,0 +1,42 @@
+package model
+
+import (
+	"time"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/repository"
+)
+
+type GitLinkCopyDTO struct {
+	GitLink   string `json:"link"`
+	Canonical string `json:"canonical"`
+	// mirror: same history as the canonical repository, fork: most of it
+	Relation string `json:"relation"`
+	// Confirmed is whether a labeler confirmed the copy, the mirrors and the
+	// confirmed forks are scored as the canonical repository
+	Confirmed  bool       `json:"confirmed"`
+	UpdateTime *time.Time `json:"updateTime"`
+}
+
+type GitLinkCopiesDTO struct {
+	GitLink string `json:"link"`
+	// Canonical is the canonical repository of the link, the link itself if
+	// it is not a copy
+	Canonical string `json:"canonical"`
+	// Relation is the relation of the link to the canonical repository, null
+	// if it is not a copy
+	Relation *string `json:"relation"`
+	// Scored is whether the link is scored as the canonical repository
+	Scored bool `json:"scored"`
+	// Copies are the mirrors and forks of the canonical repository
+	Copies []GitLinkCopyDTO `json:"copies"`
+}
+
+func GitLinkCopyDOToDTO(c *repository.GitLinkCopy) *GitLinkCopyDTO {
+	return &GitLinkCopyDTO{
+		GitLink:    *c.GitLink,
+		Canonical:  *c.Canonical,
+		Relation:   string(*c.Relation),
+		Confirmed:  *c.Confirmed,
+		UpdateTime: c.UpdateTime,
+	}
+}
@@ -47,6 +47,12 @@ type GitFileStatisticsResultDTO struct {
 	Success      *int `json:"success"`
 	Fail         *int `json:"fail"`
 	NeverSuccess *int `json:"neverSuccess"`
+	// Storage is the size in bytes of all the mirrors
+	Storage *int64 `json:"storage"`
+	Mirrors *int   `json:"mirrors"`
+	Shallow *int   `json:"shallow"`
+	Evicted *int   `json:"evicted"`
+	Partial *int   `json:"partial"`
 }
 
 func GitFileStatisticsResultDOToDTO(r *repository.GitFileStatisticsResult) *GitFileStatisticsResultDTO {
@@ -55,6 +61,11 @@ func GitFileStatisticsResultDOToDTO(r *repository.GitFileStatisticsResult) *GitF
 		r.Success,
 		r.Fail,
 		r.NeverSuccess,
+		r.Storage,
+		r.Mirrors,
+		r.Shallow,
+		r.Evicted,
+		r.Partial,
 	}
 
 }
@@ -38,3 +38,42 @@ func ToDistributionPackageDTO(pkg *repository.DistPackage) *DistributionPackageD
 		LinkConfidence: *pkg.LinkConfidence,
 	}
 }
+
+type UpdatePackageGitLinkReq struct {
+	Ecosystem   string  `json:"ecosystem" binding:"required"`
+	PackageName string  `json:"packageName" binding:"required"`
+	Link        *string `json:"link"`
+}
+
+type PackageLinkDTO struct {
+	Ecosystem     string   `json:"ecosystem"`
+	Package       string   `json:"package"`
+	ManifestLinks []string `json:"manifestLinks"`
+	RegistryLink  *string  `json:"registryLink"`
+	Status        string   `json:"status"`
+	LabelLink     *string  `json:"labelLink"`
+}
+
+func ToPackageLinkDTO(link *repository.PackageLink) *PackageLinkDTO {
+	if link == nil {
+		return nil
+	}
+	dto := &PackageLinkDTO{
+		Ecosystem:     *link.Ecosystem,
+		Package:       *link.Name,
+		ManifestLinks: []string(*link.ManifestLinks),
+		Status:        string(*link.Status),
+	}
+	if link.RegistryLink != nil {
+		dto.RegistryLink = *link.RegistryLink
+	}
+	if link.LabelLink != nil {
+		dto.LabelLink = *link.LabelLink
+	}
+	return dto
+}
+
+type ConfirmGitLinkCopyReq struct {
+	Link      string `json:"link" binding:"required"`
+	Confirmed bool   `json:"confirmed"`
+}
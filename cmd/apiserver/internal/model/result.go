@@ -15,6 +15,33 @@ type ResultGitMetadataDTO struct {
 	OrgCount         *int       `json:"orgCount"`
 	CommitFrequency  *float64   `json:"commitFrequency"`
 	UpdateTime       *time.Time `json:"updateTime"`
+	// minimum number of contributors authoring 50% and 80% of the commits
+	// of the last 12 months
+	BusFactor50 *int `json:"busFactor50"`
+	BusFactor80 *int `json:"busFactor80"`
+	// gini coefficient of the commits of the contributors of the last 12
+	// months
+	CommitGini *float64 `json:"commitGini"`
+	// share of the commits of the last 12 months authored by the top
+	// organization
+	TopOrgShare *float64 `json:"topOrgShare"`
+	// last commit of any of the top 3 contributors of the whole history
+	TopMaintainersLastCommit *time.Time `json:"topMaintainersLastCommit"`
+	// number of tags looking like a version, including pre-releases
+	SemverTags *int `json:"semverTags"`
+	// number of releases of the last 365 days
+	Releases365d *int `json:"releases365d"`
+	// time of the last release, null if never released
+	LastRelease *time.Time `json:"lastRelease"`
+	// median number of days between two consecutive releases, null if
+	// released less than twice
+	MedianReleaseInterval *float64 `json:"medianReleaseInterval"`
+	// spdx expression of the licenses of the files of the repository
+	LicenseExpression *string `json:"licenseExpression"`
+	// percentage of the source files under each license expression
+	LicenseCoverage *map[string]float64 `json:"licenseCoverage"`
+	// families of the licenses found, e.g. permissive or copyleft
+	LicenseFamilies *[]string `json:"licenseFamilies"`
 }
 
 type ResultLangDetailDTO struct {
@@ -50,6 +77,31 @@ type RankingResultDTO struct {
 	Ranking int `json:"ranking"`
 }
 
+type ResultExplainMetricDTO struct {
+	Metric       string  `json:"metric"`
+	Value        float64 `json:"value"`
+	Normalized   float64 `json:"normalized"`
+	Weight       float64 `json:"weight"`
+	Contribution float64 `json:"contribution"`
+}
+
+type ResultExplainDimensionDTO struct {
+	ResultExplainMetricDTO
+	Dimension string                   `json:"dimension"`
+	Metrics   []ResultExplainMetricDTO `json:"metrics"`
+}
+
+type ResultExplainDTO struct {
+	ScoreID     *int                        `json:"scoreID"`
+	GitLink     string                      `json:"link"`
+	Score       *float64                    `json:"score"`
+	Round       *int                        `json:"round"`
+	ProfileName *string                     `json:"profileName"`
+	ProfileHash *string                     `json:"profileHash"`
+	Dimensions  []ResultExplainDimensionDTO `json:"dimensions"`
+	UpdateTime  *time.Time                  `json:"updateTime"`
+}
+
 func ResultDOToDTO(r *repository.Result) *ResultDTO {
 	return &ResultDTO{
 		ScoreID:     *r.ScoreID,
@@ -72,6 +124,19 @@ func ResultGitDetailDOToDTO(r *repository.ResultGitDetail) *ResultGitMetadataDTO
 		OrgCount:         *r.OrgCount,
 		CommitFrequency:  *r.CommitFrequency,
 		UpdateTime:       *r.UpdateTime,
+
+		BusFactor50:              *r.BusFactor50,
+		BusFactor80:              *r.BusFactor80,
+		CommitGini:               *r.CommitGini,
+		TopOrgShare:              *r.TopOrgShare,
+		TopMaintainersLastCommit: *r.TopMaintainersLastCommit,
+		SemverTags:               *r.SemverTags,
+		Releases365d:             *r.Releases365d,
+		LastRelease:              *r.LastRelease,
+		MedianReleaseInterval:    *r.MedianReleaseInterval,
+		LicenseExpression:        *r.LicenseExpression,
+		LicenseCoverage:          (*map[string]float64)(*r.LicenseCoverage),
+		LicenseFamilies:          (*[]string)(*r.LicenseFamilies),
 	}
 }
 
@@ -108,3 +173,60 @@ func RankingDOToDTO(r *repository.RankingResult) *RankingResultDTO {
 		Ranking: *r.Ranking,
 	}
 }
+
+func ScoreBreakdownDOToDTO(r *repository.ScoreBreakdown) *ResultExplainMetricDTO {
+	return &ResultExplainMetricDTO{
+		Metric:       *r.Metric,
+		Value:        *r.Value,
+		Normalized:   *r.Normalized,
+		Weight:       *r.Weight,
+		Contribution: *r.Contribution,
+	}
+}
+
+// ResultExplainDOToDTO groups the metric breakdown of a score by dimension.
+// The row whose metric equals its dimension is the contribution of the
+// dimension to the final score.
+func ResultExplainDOToDTO(r *repository.Result, breakdown []*repository.ScoreBreakdown) *ResultExplainDTO {
+	ret := &ResultExplainDTO{
+		ScoreID:    *r.ScoreID,
+		GitLink:    *r.GitLink,
+		Score:      *r.Score,
+		UpdateTime: *r.UpdateTime,
+		Dimensions: make([]ResultExplainDimensionDTO, 0),
+	}
+	if r.Round != nil {
+		ret.Round = *r.Round
+	}
+	if r.ProfileName != nil {
+		ret.ProfileName = *r.ProfileName
+	}
+	if r.ProfileHash != nil {
+		ret.ProfileHash = *r.ProfileHash
+	}
+
+	dimensionIdx := make(map[string]int)
+	getDimension := func(name string) *ResultExplainDimensionDTO {
+		idx, ok := dimensionIdx[name]
+		if !ok {
+			idx = len(ret.Dimensions)
+			dimensionIdx[name] = idx
+			ret.Dimensions = append(ret.Dimensions, ResultExplainDimensionDTO{
+				Dimension: name,
+				Metrics:   make([]ResultExplainMetricDTO, 0),
+			})
+		}
+		return &ret.Dimensions[idx]
+	}
+
+	for _, b := range breakdown {
+		d := getDimension(*b.Dimension)
+		m := ScoreBreakdownDOToDTO(b)
+		if *b.Metric == *b.Dimension {
+			d.ResultExplainMetricDTO = *m
+		} else {
+			d.Metrics = append(d.Metrics, *m)
+		}
+	}
+	return ret
+}
@@ -29,6 +29,8 @@ func (r *RpcServiceServer) QueryCurrent(req struct{}, resp *rpcproto.StatusResp)
 		CurrentTasks: task.GetRunningTasks(),
 		PendingTasks: schedule.GetPendingTasks(),
 		IsRunning:    schedule.IsScheduleRunning(),
+		Hosts:        task.GetHostStatus(),
+		Storage:      task.GetStorageStatus(),
 	}
 	return nil
 }
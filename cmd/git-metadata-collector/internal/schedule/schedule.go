@@ -2,18 +2,33 @@ package schedule
 
 import (
 	"fmt"
-	"slices"
+	"os"
 	"sync"
 	"time"
 
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/url"
 	"github.com/HUSTSecLab/OpenSift/pkg/logger"
 	"github.com/HUSTSecLab/OpenSift/pkg/storage"
 	"github.com/HUSTSecLab/OpenSift/pkg/storage/repository"
 )
 
+// The tasks are queued in the database and leased by the collectors of every
+// host, see repository.GitTaskRepository. A collector renews the leases of
+// its running tasks, so the tasks of a crashed collector are leased again
+// once their lease expires. Nothing is lost on restart.
+
 var FetchSize = 200
 var FetchThreshold = 30
 
+// a task not renewed for LeaseTimeout is leased again by another collector
+var LeaseTimeout = 10 * time.Minute
+
+// tasks leased MaxAttempts times without being finished, e.g. because they
+// crash the collector, are parked for ParkDelay
+var MaxAttempts = 3
+
+const ParkDelay = 24 * time.Hour
+
 const IdleInterval = 30 * time.Second
 
 func SetFetchOptions(fetchSize, fetchThreshold int) {
@@ -21,78 +36,86 @@ func SetFetchOptions(fetchSize, fetchThreshold int) {
 	FetchThreshold = fetchThreshold
 }
 
-var tasks []string = make([]string, 0, FetchSize)
-var tasksSet map[string]struct{} = make(map[string]struct{})
-
-var manualTasks []string = make([]string, 0)
-var muTasks sync.Mutex
-var fetchInProgress bool
-var taskCond = sync.NewCond(&muTasks)
-
-// When such thing occur: <1> is thread id
-// 1. <1> running a task
-// 2. <2> fetching database
-// 3. <1> update database and delete task from tasksSet
-// 4. <2> append to tasks and tasksSet
-// Because things will change when fetching database,
-// so we delay real delete after step 4
-//
-// But another problem appears:
-// Until next fetch database, task cannot be start manually.
-var (
-	toDeleteTasks []string
-	muToDelete    sync.Mutex
-)
+func SetLeaseOptions(leaseTimeout time.Duration, maxAttempts int) {
+	LeaseTimeout = leaseTimeout
+	MaxAttempts = maxAttempts
+}
+
+// owner identifies the leases of this process
+var owner = leaseOwner()
+
+func leaseOwner() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	return fmt.Sprintf("%s-%d", host, os.Getpid())
+}
+
+// the queue is refilled by one goroutine at a time
+var muFetch sync.Mutex
+var maintainOnce sync.Once
 
 var isStop = false
 var muStop sync.Mutex
 var stopCond = sync.NewCond(&muStop)
 
-func fetchTasksFromDatabase() error {
-	ctx := storage.GetDefaultAppDatabaseContext()
-	r := repository.NewRankedGitTaskRepository(ctx)
+func newTaskRepository() repository.GitTaskRepository {
+	return repository.NewGitTaskRepository(storage.GetDefaultAppDatabaseContext())
+}
 
-	for {
-		ok := false
+// fetchTasksFromDatabase parks the tasks leased too many times, then queues
+// the links due for collection if less than FetchThreshold tasks are pending.
+func fetchTasksFromDatabase(r repository.GitTaskRepository) error {
+	muFetch.Lock()
+	defer muFetch.Unlock()
 
-		result, err := r.Query(FetchSize)
-		if err != nil {
-			return err
-		}
+	parked, err := r.Park(MaxAttempts, ParkDelay)
+	if err != nil {
+		return err
+	}
+	if parked > 0 {
+		logger.Warnf("%d tasks leased %d times are parked for %v", parked, MaxAttempts, ParkDelay)
+	}
 
-		muTasks.Lock()
-		for r := range result {
-			ok = true
-			if _, exist := tasksSet[*r.GitLink]; !exist {
-				tasks = append(tasks, *r.GitLink)
-				tasksSet[*r.GitLink] = struct{}{}
-			}
-		}
+	pending, err := r.CountPending(MaxAttempts)
+	if err != nil {
+		return err
+	}
+	if pending >= FetchThreshold {
+		return nil
+	}
+	logger.Infof("Pending tasks less than %d, fetching tasks from database", FetchThreshold)
+	added, err := r.Enqueue(FetchSize)
+	if err != nil {
+		return err
+	}
+	logger.Infof("%d tasks queued", added)
+	return nil
+}
 
-		muToDelete.Lock()
-		// real delete tasksSet
-		for _, t := range toDeleteTasks {
-			delete(tasksSet, t)
+// maintain renews the leases of the running tasks and refills the queue
+// until the process exits.
+func maintain() {
+	r := newTaskRepository()
+	ticker := time.NewTicker(LeaseTimeout / 3)
+	defer ticker.Stop()
+	for range ticker.C {
+		if _, err := r.Renew(owner, LeaseTimeout); err != nil {
+			logger.Errorf("Error renewing leases: %v", err)
 		}
-		toDeleteTasks = make([]string, 0)
-		muToDelete.Unlock()
-
-		muTasks.Unlock()
-		if !ok {
-			time.Sleep(IdleInterval)
-		} else {
-			break
+		if err := fetchTasksFromDatabase(r); err != nil {
+			logger.Errorf("Error fetching tasks: %v", err)
 		}
 	}
-	return nil
 }
 
 func AddManualTask(task string) {
-	muTasks.Lock()
-	defer muTasks.Unlock()
-	if _, ok := tasksSet[task]; !ok {
-		manualTasks = append(manualTasks, task)
-		tasksSet[task] = struct{}{}
+	if canonical, err := url.Canonicalize(task); err == nil {
+		task = canonical
+	}
+	if err := newTaskRepository().EnqueueManual(task); err != nil {
+		logger.Errorf("Error adding manual task %s: %v", task, err)
 	}
 }
 
@@ -104,6 +127,7 @@ func StartScheduler() {
 	muStop.Unlock()
 }
 
+// StopScheduler stops leasing tasks, the running ones are still finished.
 func StopScheduler() {
 	logger.Info("Scheduler is stopping...")
 	muStop.Lock()
@@ -112,75 +136,88 @@ func StopScheduler() {
 	muStop.Unlock()
 }
 
+// GetTask leases the next task, waiting until one is available. The task
+// must be finished with FinishTask.
 func GetTask() (string, error) {
-	muStop.Lock()
-	for isStop {
-		stopCond.Wait()
-	}
-	muStop.Unlock()
+	maintainOnce.Do(func() {
+		go maintain()
+	})
+	r := newTaskRepository()
 
-	muTasks.Lock()
-	defer muTasks.Unlock()
-
-	if len(manualTasks) > 0 {
-		task := manualTasks[0]
-		manualTasks = manualTasks[1:]
-		return task, nil
-	}
-
-	triggerFetch := func() {
-		if !fetchInProgress {
-			fetchInProgress = true
-			go func() {
-				logger.Infof("Pending tasks less than %d, fetching tasks from database", FetchThreshold)
-				err := fetchTasksFromDatabase()
-				muTasks.Lock()
-				fetchInProgress = false
-				muTasks.Unlock()
-				taskCond.Broadcast() // Notify waiting processes
-				if err != nil {
-					logger.Errorf("Error fetching tasks: %v\n", err)
-				}
-			}()
+	for {
+		muStop.Lock()
+		for isStop {
+			stopCond.Wait()
 		}
+		muStop.Unlock()
 
+		task, err := r.Lease(owner, LeaseTimeout, MaxAttempts)
+		if err == nil && task == nil {
+			if err = fetchTasksFromDatabase(r); err == nil {
+				task, err = r.Lease(owner, LeaseTimeout, MaxAttempts)
+			}
+		}
+		if err != nil {
+			logger.Errorf("Error leasing task: %v", err)
+		}
+		if task != nil {
+			return *task.GitLink, nil
+		}
+		time.Sleep(IdleInterval)
 	}
+}
 
-	// Trigger fetch if tasks are below FetchThreshold
-	if len(tasks) < FetchThreshold {
-		triggerFetch()
-	}
-
-	// Wait if tasks are empty and fetch is in progress
-	for len(tasks) == 0 {
-		triggerFetch()
-		taskCond.Wait()
+// FinishTask removes a task leased by GetTask from the queue, whether its
+// collection succeeded or not: failures are retried from git_files.
+func FinishTask(t string) {
+	if err := newTaskRepository().Complete(owner, t); err != nil {
+		logger.Errorf("Error finishing task %s: %v", t, err)
 	}
+}
 
-	if len(tasks) > 0 {
-		task := tasks[0]
-		tasks = tasks[1:]
-		return task, nil
+// DeferTask releases a task leased by GetTask without finishing it, it is
+// leased again after until.
+func DeferTask(t string, until time.Time, reason string) {
+	if err := newTaskRepository().Release(owner, t, until, reason); err != nil {
+		logger.Errorf("Error deferring task %s: %v", t, err)
 	}
-
-	return "", fmt.Errorf("no task available")
 }
 
-// In order to unique incoming tasks (when get data from database, there exists
-// tasks which is same with executing one), so executing tasks are store in a set,
-// when task is finished, this method is supposed to be called.
-func FinishTask(t string) {
-	muToDelete.Lock()
-	defer muToDelete.Unlock()
-	toDeleteTasks = append(toDeleteTasks, t)
+// HoldTask leases the task of t, queued or not, so that no collector
+// collects it until the returned function is called. It returns false if the
+// task is leased already.
+func HoldTask(t string) (func(), bool) {
+	r := newTaskRepository()
+	held, queued, err := r.Hold(owner, t, LeaseTimeout)
+	if err != nil {
+		logger.Errorf("Error holding task %s: %v", t, err)
+		return nil, false
+	}
+	if !held {
+		return nil, false
+	}
+	return func() {
+		if queued {
+			err = r.Complete(owner, t)
+		} else {
+			err = r.Unhold(owner, t)
+		}
+		if err != nil {
+			logger.Errorf("Error releasing task %s: %v", t, err)
+		}
+	}, true
 }
 
 func GetPendingTasks() []string {
-	muTasks.Lock()
-	defer muTasks.Unlock()
-
-	t := slices.Clone(manualTasks)
-	t = append(t, tasks...)
+	pending, err := newTaskRepository().QueryPending(FetchSize, MaxAttempts)
+	if err != nil {
+		logger.Errorf("Error querying pending tasks: %v", err)
+		return nil
+	}
+	t := make([]string, 0)
+	for task := range pending {
+		t = append(t, *task.GitLink)
+	}
 	return t
 }
 
@@ -0,0 +1,155 @@
+package task
+
+import (
+	"strings"
+	"time"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/git"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/url"
+	"github.com/HUSTSecLab/OpenSift/pkg/logger"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/repository"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/sqlutil"
+	"github.com/lib/pq"
+)
+
+// A repository whose history is a copy of the history of another one, see
+// git.History.Relate, is a mirror or a fork. Of the copies, one is kept as
+// canonical; the mirrors, and the forks a labeler confirms, are scored as it.
+
+// copyCandidate is a link, its history and the time of its oldest commit,
+// the canonical copy is the least one, see less.
+type copyCandidate struct {
+	link    string
+	history *git.History
+	since   time.Time
+}
+
+// mirrorNamed reports whether the link names a mirror, such as
+// github.com/gcc-mirror/gcc.
+func (c *copyCandidate) mirrorNamed() bool {
+	return strings.Contains(strings.ToLower(c.link), "mirror")
+}
+
+// less reports whether c is preferred over o as the canonical copy: a link
+// not named a mirror, hosted upstream rather than on a forge, then the
+// oldest one, then the most active one, with the most commits. The age of a
+// copy is the time of its oldest commit, which tells copies sharing their
+// root commits apart if their history was rewritten.
+func (c *copyCandidate) less(o *copyCandidate) bool {
+	if c.mirrorNamed() != o.mirrorNamed() {
+		return !c.mirrorNamed()
+	}
+	if forge := url.IsForge(c.link); forge != url.IsForge(o.link) {
+		return !forge
+	}
+	if !c.since.IsZero() && !o.since.IsZero() && !c.since.Equal(o.since) {
+		return c.since.Before(o.since)
+	}
+	if c.history.Commits != o.history.Commits {
+		return c.history.Commits > o.history.Commits
+	}
+	return c.link < o.link
+}
+
+func toCandidate(h *repository.GitHistory) *copyCandidate {
+	c := &copyCandidate{
+		link: *h.GitLink,
+		history: &git.History{
+			Roots:   git.ParseHashes(*h.Roots),
+			Sketch:  git.ParseHashes(*h.Sketch),
+			Commits: *h.Commits,
+		},
+	}
+	if h.CreatedSince != nil && *h.CreatedSince != nil {
+		c.since = **h.CreatedSince
+	}
+	return c
+}
+
+// recordHistory stores the history of gitLink, whose oldest commit was
+// committed at since, and marks it, or the copies found with it, as copies
+// of the canonical one.
+func recordHistory(gitLink string, history *git.History, since time.Time) {
+	ac := storage.GetDefaultAppDatabaseContext()
+	hr := repository.NewGitHistoryRepository(ac)
+	cr := repository.NewGitLinkCopyRepository(ac)
+	log := logger.WithFields(map[string]any{
+		"gitlink": gitLink,
+	})
+
+	roots := pq.StringArray(git.HashStrings(history.Roots))
+	data := &repository.GitHistory{
+		GitLink:      sqlutil.ToData(gitLink),
+		Roots:        sqlutil.ToData(roots),
+		Sketch:       sqlutil.ToData(pq.StringArray(git.HashStrings(history.Sketch))),
+		Commits:      sqlutil.ToData(history.Commits),
+		CreatedSince: sqlutil.ToData[*time.Time](nil),
+	}
+	if !since.IsZero() {
+		data.CreatedSince = sqlutil.ToNullable(since)
+	}
+	err := hr.InsertOrUpdate(data)
+	if err != nil {
+		log.Errorf("Inserting history failed: %v", err)
+		return
+	}
+	if len(roots) == 0 {
+		return
+	}
+	others, err := hr.QuerySharingRoots(gitLink, roots)
+	if err != nil {
+		log.Errorf("Querying histories failed: %v", err)
+		return
+	}
+
+	self := &copyCandidate{link: gitLink, history: history, since: since}
+	best := self
+	copies := []*copyCandidate{}
+	for other := range others {
+		c := toCandidate(other)
+		if ok, _ := history.Relate(c.history); !ok {
+			continue
+		}
+		copies = append(copies, c)
+		if c.less(best) {
+			best = c
+		}
+	}
+
+	if best != self {
+		_, mirrors := history.Relate(best.history)
+		insertCopy(cr, gitLink, best.link, mirrors)
+		return
+	}
+	// gitLink may have been a copy of a repository it has diverged from
+	if c, err := cr.QueryByLink(gitLink); err != nil {
+		log.Errorf("Querying copy failed: %v", err)
+	} else if c != nil {
+		log.Infof("No longer a copy of %s", *c.Canonical)
+		if err := cr.Delete(gitLink); err != nil {
+			log.Errorf("Deleting copy failed: %v", err)
+		}
+	}
+	for _, c := range copies {
+		_, mirrors := c.history.Relate(history)
+		insertCopy(cr, c.link, gitLink, mirrors)
+	}
+}
+
+func insertCopy(cr repository.GitLinkCopyRepository, link string, canonical string, mirrors bool) {
+	relation := repository.GitLinkFork
+	if mirrors {
+		relation = repository.GitLinkMirror
+	}
+	log := logger.WithFields(map[string]any{
+		"gitlink": link,
+	})
+	if c, err := cr.QueryByLink(link); err == nil && c != nil && *c.Canonical == canonical && *c.Relation == relation {
+		return
+	}
+	log.Infof("Copy (%s) of %s", relation, canonical)
+	if err := cr.Insert(link, canonical, relation); err != nil {
+		log.Errorf("Inserting copy failed: %v", err)
+	}
+}
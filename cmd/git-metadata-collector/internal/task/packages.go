@@ -0,0 +1,47 @@
+package task
+
+import (
+	"strings"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/git"
+	"github.com/HUSTSecLab/OpenSift/pkg/logger"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/repository"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/sqlutil"
+)
+
+// recordPackages replaces the packages declared and depended on at the head
+// of gitLink, from which the dependents of the ecosystems not covered by
+// deps.dev are counted.
+func recordPackages(gitLink string, repo *git.Repo) {
+	pr := repository.NewGitPackageRepository(storage.GetDefaultAppDatabaseContext())
+	packages, dependencies := repo.Packages()
+
+	gitPackages := make([]*repository.GitPackage, 0, len(packages))
+	for _, pkg := range packages {
+		var version *string
+		if v := strings.TrimSpace(pkg.Version); v != "" {
+			version = &v
+		}
+		gitPackages = append(gitPackages, &repository.GitPackage{
+			GitLink:   sqlutil.ToData(gitLink),
+			Ecosystem: sqlutil.ToData(pkg.Eco),
+			Name:      sqlutil.ToData(pkg.Name),
+			Version:   sqlutil.ToData(version),
+		})
+	}
+	gitDependencies := make([]*repository.GitPackageDependency, 0, len(dependencies))
+	for _, dep := range dependencies {
+		gitDependencies = append(gitDependencies, &repository.GitPackageDependency{
+			GitLink:   sqlutil.ToData(gitLink),
+			Ecosystem: sqlutil.ToData(dep.Eco),
+			Name:      sqlutil.ToData(dep.Name),
+		})
+	}
+
+	if err := pr.Replace(gitLink, gitPackages, gitDependencies); err != nil {
+		logger.WithFields(map[string]any{
+			"gitlink": gitLink,
+		}).Errorf("Inserting packages failed: %v", err)
+	}
+}
@@ -0,0 +1,226 @@
+package task
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/HUSTSecLab/OpenSift/cmd/git-metadata-collector/internal/schedule"
+	"github.com/HUSTSecLab/OpenSift/cmd/git-metadata-collector/rpc"
+	"github.com/HUSTSecLab/OpenSift/pkg/config"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/collector"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/url"
+	"github.com/HUSTSecLab/OpenSift/pkg/logger"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/repository"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/sqlutil"
+)
+
+const (
+	// PruneEvict removes the mirrors over the budget, they are cloned again
+	// on their next collection
+	PruneEvict = "evict"
+	// PruneShallow drops the history of the mirrors over the budget which
+	// have a checkpoint, the others are removed
+	PruneShallow = "shallow"
+)
+
+// once over the budget, the mirrors are freed down to storageLowWatermark of
+// it so that they are not freed one at a time
+const storageLowWatermark = 0.9
+
+const evictBatch = 64
+
+var StorageCheckInterval = 10 * time.Minute
+
+var storageBudget int64
+var storagePrune = PruneEvict
+
+var storageStatus rpc.StorageStatusDTO
+var muStorage sync.Mutex
+
+// SetStorageBudget sets the size in bytes of the mirrors of every collector
+// sharing the storage, 0 for no budget, and how the mirrors over it are
+// freed: PruneEvict or PruneShallow.
+func SetStorageBudget(budget int64, prune string) error {
+	if budget < 0 {
+		return fmt.Errorf("invalid storage budget %d", budget)
+	}
+	if prune != PruneEvict && prune != PruneShallow {
+		return fmt.Errorf("invalid storage prune mode %q", prune)
+	}
+	storageBudget = budget
+	storagePrune = prune
+	return nil
+}
+
+// GetStorageStatus returns the usage of the storage at its last check, nil
+// if there is no budget.
+func GetStorageStatus() *rpc.StorageStatusDTO {
+	if storageBudget == 0 {
+		return nil
+	}
+	muStorage.Lock()
+	defer muStorage.Unlock()
+	status := storageStatus
+	status.Budget = storageBudget
+	status.Prune = storagePrune
+	return &status
+}
+
+// MaintainStorage enforces the storage budget every StorageCheckInterval
+// until the process exits.
+func MaintainStorage() {
+	for {
+		if err := EnforceStorageBudget(); err != nil {
+			logger.Errorf("Error enforcing the storage budget: %v", err)
+		}
+		time.Sleep(StorageCheckInterval)
+	}
+}
+
+// EnforceStorageBudget frees mirrors until their total size is below the
+// budget, from the least critical and least recently collected one. The task
+// of a mirror is held while it is freed, so that it is not collected
+// meanwhile; the mirrors whose task is leased are skipped.
+func EnforceStorageBudget() error {
+	if storageBudget == 0 {
+		return nil
+	}
+	gmr := repository.NewGitMetricsRepository(storage.GetDefaultAppDatabaseContext())
+	stats, err := gmr.GetGitFilesStatistics()
+	if err != nil {
+		return err
+	}
+	used := *stats.Storage
+	defer func() {
+		muStorage.Lock()
+		storageStatus.Used = used
+		storageStatus.CheckTime = time.Now()
+		muStorage.Unlock()
+	}()
+	if used <= storageBudget {
+		return nil
+	}
+
+	target := int64(float64(storageBudget) * storageLowWatermark)
+	logger.Warnf("Mirrors take %s over the budget of %s, freeing down to %s",
+		collector.FormatSize(used), collector.FormatSize(storageBudget), collector.FormatSize(target))
+
+	for used > target {
+		candidates, err := gmr.QueryEvictionCandidates(evictBatch, storagePrune == PruneShallow)
+		if err != nil {
+			return err
+		}
+		freed := false
+		for _, gf := range slices.Collect(candidates) {
+			if used <= target {
+				break
+			}
+			if isRunning(*gf.GitLink) {
+				continue
+			}
+			// a collector may have leased the task since the candidates
+			// were queried, no collector leases it while it is held
+			release, ok := schedule.HoldTask(*gf.GitLink)
+			if !ok {
+				continue
+			}
+			n, err := free(gmr, gf)
+			release()
+			if err != nil {
+				logger.WithFields(map[string]any{
+					"gitlink": *gf.GitLink,
+				}).Errorf("Freeing mirror failed: %v", err)
+				continue
+			}
+			used -= n
+			freed = true
+
+			muStorage.Lock()
+			storageStatus.Freed += n
+			muStorage.Unlock()
+		}
+		if !freed {
+			logger.Warnf("No mirror left to free, %s still used", collector.FormatSize(used))
+			break
+		}
+	}
+	return nil
+}
+
+func isRunning(gitLink string) bool {
+	muRunningTasks.Lock()
+	defer muRunningTasks.Unlock()
+	_, ok := runningTasks[gitLink]
+	return ok
+}
+
+// free prunes or removes the mirror of gf, it returns the bytes freed.
+func free(gmr repository.GitMetricsRepository, gf *repository.GitFile) (int64, error) {
+	gitLink := *gf.GitLink
+	path := filepath.Join(config.GetGitStoragePath(), *gf.FilePath)
+	size := **gf.TakeStorage
+
+	// partial mirrors have no blob of the history to drop
+	partial := !sqlutil.IsNull(gf.CloneFilter)
+	if storagePrune == PruneShallow && !*gf.Shallow && !partial && hasCheckpoint(gitLink) {
+		pruned, err := prune(gitLink, path)
+		if err == nil && pruned < size {
+			logger.Infof("Pruned mirror of %s from %s to %s", gitLink, collector.FormatSize(size), collector.FormatSize(pruned))
+			return size - pruned, gmr.UpdateGitFileStorage(gitLink, pruned, true)
+		}
+		if err != nil {
+			logger.WithFields(map[string]any{
+				"gitlink": gitLink,
+			}).Warnf("Pruning mirror failed, evicting it: %v", err)
+		}
+	}
+
+	if err := os.RemoveAll(path); err != nil {
+		return 0, err
+	}
+	logger.Infof("Evicted mirror of %s, %s freed", gitLink, collector.FormatSize(size))
+	return size, gmr.UpdateGitFileStorage(gitLink, 0, false)
+}
+
+// evictShallow removes the shallow mirror of gitLink at path.
+func evictShallow(gmr repository.GitMetricsRepository, gitLink, path string) {
+	err := os.RemoveAll(path)
+	if err == nil {
+		err = gmr.UpdateGitFileStorage(gitLink, 0, false)
+	}
+	if err != nil {
+		logger.WithFields(map[string]any{
+			"gitlink": gitLink,
+		}).Errorf("Evicting shallow mirror failed: %v", err)
+	}
+}
+
+// prune makes the mirror at path shallow, it returns its new size.
+func prune(gitLink, path string) (int64, error) {
+	u, err := url.ParseURL(gitLink)
+	if err != nil {
+		return 0, err
+	}
+	var progress bytes.Buffer
+	release := limiter.Acquire(u.Resource)
+	err = collector.ShallowPrune(&u, path, &progress)
+	release(err, progress.String())
+	if err != nil {
+		return 0, fmt.Errorf("%w\nOutput:\n%s", err, progress.String())
+	}
+	return collector.DiskUsage(path)
+}
+
+// hasCheckpoint returns whether the commits of gitLink are counted from a
+// checkpoint, without which a shallow mirror cannot be collected.
+func hasCheckpoint(gitLink string) bool {
+	gcr := repository.NewGitCheckpointRepository(storage.GetDefaultAppDatabaseContext())
+	cp, err := gcr.QueryByLink(gitLink)
+	return err == nil && cp != nil && cp.Data != nil
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/HUSTSecLab/OpenSift/cmd/git-metadata-collector/rpc"
 	"github.com/HUSTSecLab/OpenSift/pkg/config"
 	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/collector"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/collector/hostlimit"
 	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/git"
 	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/url"
 	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/util"
@@ -28,6 +29,71 @@ type RunningTask struct {
 var runningTasks = make(map[string]*RunningTask, 0)
 var muRunningTasks sync.Mutex
 
+var fullWalk = false
+
+// limiter limits the clones and fetches per host
+var limiter = hostlimit.NewLimiter(nil)
+
+// SetHostLimits replaces the limits of the clones and fetches per host, the
+// hosts without one keep their default limit.
+func SetHostLimits(limits []hostlimit.Limit) {
+	limiter = hostlimit.NewLimiter(limits)
+}
+
+func GetHostStatus() []rpc.HostStatusDTO {
+	status := limiter.Status()
+	hosts := make([]rpc.HostStatusDTO, 0, len(status))
+	for _, h := range status {
+		hosts = append(hosts, rpc.HostStatusDTO{
+			Host:         h.Host,
+			Concurrency:  h.Concurrency,
+			Rate:         h.Rate,
+			Running:      h.Running,
+			Waiting:      h.Waiting,
+			Throttled:    h.Throttled,
+			BackoffUntil: h.BackoffUntil,
+		})
+	}
+	return hosts
+}
+
+// mirrors of at least bloblessAbove bytes are cloned without blobs, none if
+// negative
+var bloblessAbove int64 = -1
+
+// SetBloblessAbove sets the size in bytes of the mirrors from which they are
+// cloned again without blobs, 0 for every mirror and negative for none.
+func SetBloblessAbove(size int64) {
+	bloblessAbove = size
+}
+
+// cloneFilter returns the filter to collect gf with: collector.FilterBlobless
+// if its last full mirror took at least bloblessAbove, else a full mirror, so
+// that the repositories never measured are cloned in full and measured, and
+// a partial mirror is cloned in full again once bloblessAbove is raised above
+// its size. Mirrors are kept as they are if bloblessAbove is negative.
+func cloneFilter(gf *repository.GitFile) string {
+	if bloblessAbove < 0 {
+		if gf != nil && !sqlutil.IsNull(gf.CloneFilter) {
+			return **gf.CloneFilter
+		}
+		return ""
+	}
+	if bloblessAbove == 0 {
+		return collector.FilterBlobless
+	}
+	if gf == nil || sqlutil.IsNull(gf.FullStorage) || **gf.FullStorage < bloblessAbove {
+		return ""
+	}
+	return collector.FilterBlobless
+}
+
+// SetFullWalk sets whether the whole log is walked on every collection,
+// ignoring the checkpoints stored by the previous collections.
+func SetFullWalk(full bool) {
+	fullWalk = full
+}
+
 func GetRunningTasks() []rpc.RunningTaskDTO {
 	muRunningTasks.Lock()
 	defer muRunningTasks.Unlock()
@@ -43,7 +109,11 @@ func GetRunningTasks() []rpc.RunningTaskDTO {
 	return tasks
 }
 
-func Collect(gitLink string, disableCollect bool) {
+// Collect clones or fetches gitLink and collects its metrics. If the host
+// throttled the clone, no failure is recorded: Collect returns the error and
+// the end of the backoff of the host, when the task is to be retried, and a
+// zero time otherwise.
+func Collect(gitLink string, disableCollect bool) (retryAt time.Time, throttled error) {
 	timeBegin := time.Now()
 
 	var currentTask = RunningTask{
@@ -120,13 +190,31 @@ func Collect(gitLink string, disableCollect bool) {
 			msg = sqlutil.ToData[*string](nil)
 		}
 
-		err := gmr.InsertOrUpdateGitFile(&repository.GitFile{
+		gf := &repository.GitFile{
 			GitLink:    sqlutil.ToData(gitLink),
 			FilePath:   sqlutil.ToData(filePathRel),
 			Message:    msg,
 			UpdateTime: sqlutil.ToNullable(time.Now()),
 			TakeTimeMs: sqlutil.ToNullable(time.Since(timeBegin).Milliseconds()),
-		}, success)
+		}
+		if success {
+			size, err := collector.DiskUsage(filePathAbs)
+			if err != nil {
+				logger.WithFields(map[string]any{
+					"gitlink": gitLink,
+				}).Warnf("Measuring mirror failed: %v", err)
+			} else {
+				gf.TakeStorage = sqlutil.ToNullable(size)
+			}
+			gf.Shallow = sqlutil.ToData(collector.IsShallow(filePathAbs))
+			filter := collector.Filter(filePathAbs)
+			if filter != "" {
+				gf.CloneFilter = sqlutil.ToNullable(filter)
+			} else if err == nil && !*gf.Shallow {
+				gf.FullStorage = sqlutil.ToNullable(size)
+			}
+		}
+		err := gmr.InsertOrUpdateGitFile(gf, success)
 		if err != nil {
 			logger.WithFields(map[string]any{
 				"gitlink": gitLink,
@@ -140,16 +228,21 @@ func Collect(gitLink string, disableCollect bool) {
 		// 	"gitlink": gitLink,
 		// }).Infof("git metrics collected successfully: %v", gitLink)
 
-		err := gmr.InsertOrUpdate(&repository.GitMetric{
+		gitMetric := &repository.GitMetric{
 			GitLink:          sqlutil.ToData(gitLink),
 			CreatedSince:     sqlutil.ToNullable(repo.CreatedSince),
 			UpdatedSince:     sqlutil.ToNullable(repo.UpdatedSince),
 			ContributorCount: sqlutil.ToNullable(repo.ContributorCount),
 			CommitFrequency:  sqlutil.ToNullable(repo.CommitFrequency),
 			OrgCount:         sqlutil.ToNullable(repo.OrgCount),
-			//* License:          sqlutil.ToNullable(pq.StringArray(repo.Licenses)),
-			Language: sqlutil.ToNullable(pq.StringArray(repo.Languages)),
-		})
+			Language:         sqlutil.ToNullable(pq.StringArray(repo.Languages)),
+		}
+		repo.SetActivityMetrics(gitMetric)
+		repo.SetConcentrationMetrics(gitMetric)
+		repo.SetReleaseMetrics(gitMetric)
+		repo.SetLicenseMetrics(gitMetric)
+
+		err := gmr.InsertOrUpdate(gitMetric)
 
 		if err != nil {
 			logger.Errorf("Inserting %s Failed", gitLink)
@@ -162,21 +255,114 @@ func Collect(gitLink string, disableCollect bool) {
 		logger.Errorf("url.ParseURL fail: %s: %v", gitLink, err)
 		return
 	}
-	r, err := collector.Collect(&u, filePathAbs, &currentTask.Progress)
+	release := limiter.Acquire(u.Resource)
+	r, err := collector.CollectFilter(&u, filePathAbs, cloneFilter(gf), &currentTask.Progress)
+	release(err, currentTask.Progress.String())
+	if hostlimit.IsThrottled(err, currentTask.Progress.String()) {
+		logger.WithFields(map[string]any{
+			"gitlink": gitLink,
+		}).Warnf("Clone throttled by %s: %v", u.Resource, err)
+		retryAt = limiter.BackoffUntil(u.Resource)
+		// the backoff has ended already if it is disabled
+		if now := time.Now(); retryAt.Before(now) {
+			retryAt = now
+		}
+		return retryAt, err
+	}
 	if err != nil {
 		recordClone(false, err)
 		return
 	}
 	recordClone(true, nil)
+	recordRedirect(gitLink, currentTask.Progress.String())
 
 	if !disableCollect {
-		repo, err := git.ParseRepo(r)
+		gcr := repository.NewGitCheckpointRepository(storage.GetDefaultAppDatabaseContext())
+		var cp *git.Checkpoint
+		if !fullWalk {
+			cp = loadCheckpoint(gcr, gitLink)
+		}
+		repo, err := git.ParseRepoFrom(r, cp)
 		if err != nil {
 			logger.WithFields(map[string]any{
 				"gitlink": gitLink,
 			}).Errorf("Parse repo error: %v", err)
+			// the whole log of a shallow mirror cannot be walked, it is
+			// cloned again by the next collection
+			if collector.IsShallow(filePathAbs) {
+				evictShallow(gmr, gitLink, filePathAbs)
+			}
 			return
 		}
 		recordParseSuccess(repo)
+		recordPackages(gitLink, repo)
+		saveCheckpoint(gcr, gitLink, repo.Checkpoint)
+		if repo.Checkpoint != nil {
+			recordHistory(gitLink, &repo.Checkpoint.History, repo.Checkpoint.CreatedSince)
+		}
+	}
+	return
+}
+
+// recordRedirect maps gitLink to the link its repository was redirected to
+// in output, so that it is collected and scored under its new link.
+func recordRedirect(gitLink string, output string) {
+	redirect := collector.Redirect(output)
+	if redirect == "" {
+		return
+	}
+	link, err := url.Canonicalize(redirect)
+	if err != nil || link == gitLink {
+		return
+	}
+	logger.WithFields(map[string]any{
+		"gitlink": gitLink,
+	}).Infof("Repository redirected to %s", link)
+	ar := repository.NewGitLinkAliasRepository(storage.GetDefaultAppDatabaseContext())
+	if err := ar.Insert(gitLink, link, repository.GitLinkAliasRedirect); err != nil {
+		logger.WithFields(map[string]any{
+			"gitlink": gitLink,
+		}).Errorf("Inserting alias failed: %v", err)
+	}
+}
+
+// loadCheckpoint returns the checkpoint stored for gitLink, nil if there is
+// none or it cannot be decoded.
+func loadCheckpoint(gcr repository.GitCheckpointRepository, gitLink string) *git.Checkpoint {
+	data, err := gcr.QueryByLink(gitLink)
+	if err != nil {
+		logger.WithFields(map[string]any{
+			"gitlink": gitLink,
+		}).Errorf("Query checkpoint failed: %v", err)
+		return nil
+	}
+	if data == nil || data.Data == nil {
+		return nil
+	}
+	cp, err := git.DecodeCheckpoint(*data.Data)
+	if err != nil {
+		logger.WithFields(map[string]any{
+			"gitlink": gitLink,
+		}).Warnf("Discarding checkpoint: %v", err)
+		return nil
+	}
+	return cp
+}
+
+func saveCheckpoint(gcr repository.GitCheckpointRepository, gitLink string, cp *git.Checkpoint) {
+	if cp == nil {
+		return
+	}
+	data, err := cp.Encode()
+	if err == nil {
+		err = gcr.InsertOrUpdate(&repository.GitCheckpoint{
+			GitLink: sqlutil.ToData(gitLink),
+			Data:    sqlutil.ToData(data),
+		})
+	}
+	if err != nil {
+		logger.WithFields(map[string]any{
+			"gitlink": gitLink,
+		}).Errorf("Saving checkpoint failed: %v", err)
 	}
 }
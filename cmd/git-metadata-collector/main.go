@@ -2,12 +2,13 @@ package main
 
 import (
 	"sync"
-	"time"
 
 	"github.com/HUSTSecLab/OpenSift/cmd/git-metadata-collector/internal/rpcserver"
 	"github.com/HUSTSecLab/OpenSift/cmd/git-metadata-collector/internal/schedule"
 	"github.com/HUSTSecLab/OpenSift/cmd/git-metadata-collector/internal/task"
 	"github.com/HUSTSecLab/OpenSift/pkg/config"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/collector"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/git"
 	"github.com/HUSTSecLab/OpenSift/pkg/logger"
 	"github.com/spf13/pflag"
 )
@@ -15,6 +16,13 @@ import (
 var flagJobsCount = pflag.IntP("jobs", "j", 256, "jobs count")
 var flagRpcPort = pflag.IntP("port", "p", 20324, "rpc server port")
 var flagDisableCollect = pflag.Bool("no-collect", false, "if set no, clone only but do not collect git metrics")
+var flagEmailDomains = pflag.String("email-domains", "", "email domains file replacing the bundled list used to count organizations")
+var flagFullWalk = pflag.Bool("full-walk", false, "walk the whole log of every repository, ignoring the stored checkpoints")
+var flagLeaseTimeout = pflag.Duration("lease-timeout", schedule.LeaseTimeout, "time after which a task not renewed by its collector is leased again")
+var flagMaxAttempts = pflag.Int("max-attempts", schedule.MaxAttempts, "number of leases of a task before it is parked for a day")
+var flagStorageBudget = pflag.String("storage-budget", "", "size of the mirrors of all collectors sharing the git storage, e.g. 500GiB, unlimited if empty")
+var flagStoragePrune = pflag.String("storage-prune", task.PruneEvict, "how mirrors over the storage budget are freed: evict or shallow")
+var flagBloblessAbove = pflag.String("blobless-above", "", "size of the full mirrors, e.g. 1GiB, from which they are cloned again without blobs, 0 for all mirrors, none if empty")
 
 func main() {
 	config.RegistCommonFlags(pflag.CommandLine)
@@ -22,6 +30,42 @@ func main() {
 	config.ParseFlags(pflag.CommandLine)
 	logger.SetContext("git-metadata-collector")
 
+	if *flagEmailDomains != "" {
+		if err := git.LoadEmailDomains(*flagEmailDomains); err != nil {
+			logger.Fatalf("Failed to load email domains: %v", err)
+		}
+	}
+
+	task.SetFullWalk(*flagFullWalk)
+
+	hostLimits, err := config.GetGitHostLimits()
+	if err != nil {
+		logger.Fatalf("Failed to read the host limits: %v", err)
+	}
+	task.SetHostLimits(hostLimits)
+
+	var storageBudget int64
+	if *flagStorageBudget != "" {
+		storageBudget, err = collector.ParseSize(*flagStorageBudget)
+		if err != nil {
+			logger.Fatalf("Failed to parse --storage-budget: %v", err)
+		}
+	}
+	if err := task.SetStorageBudget(storageBudget, *flagStoragePrune); err != nil {
+		logger.Fatalf("Failed to set the storage budget: %v", err)
+	}
+	if storageBudget > 0 {
+		go task.MaintainStorage()
+	}
+
+	if *flagBloblessAbove != "" {
+		bloblessAbove, err := collector.ParseSize(*flagBloblessAbove)
+		if err != nil {
+			logger.Fatalf("Failed to parse --blobless-above: %v", err)
+		}
+		task.SetBloblessAbove(bloblessAbove)
+	}
+
 	go rpcserver.RunServer(*flagRpcPort)
 
 	// psql.CreateTable(db)
@@ -29,28 +73,27 @@ func main() {
 	logger.Infof("Launching %d go routines...", *flagJobsCount)
 
 	schedule.SetFetchOptions(*flagJobsCount*10, *flagJobsCount*2)
+	if *flagLeaseTimeout <= 0 || *flagMaxAttempts <= 0 {
+		logger.Fatalf("--lease-timeout and --max-attempts must be positive")
+	}
+	schedule.SetLeaseOptions(*flagLeaseTimeout, *flagMaxAttempts)
 
 	var wg sync.WaitGroup
 
 	for i := 0; i < *flagJobsCount; i++ {
 		wg.Add(1)
 		go func() {
-			cnt := 0
 			for {
 				t, err := schedule.GetTask()
 				if err != nil {
 					logger.Fatalf("Failed to get task: %s", err)
 				}
 
-				// // begin sleep trick
-				if cnt%10 == 0 {
-					<-time.After(5 * time.Second)
-				} else {
-					<-time.After(2 * time.Second)
+				// the clones and fetches are limited per host by task.Collect
+				if retryAt, err := task.Collect(t, *flagDisableCollect); !retryAt.IsZero() {
+					schedule.DeferTask(t, retryAt, err.Error())
+					continue
 				}
-				cnt++
-				// end sleep trick
-				task.Collect(t, *flagDisableCollect)
 
 				schedule.FinishTask(t)
 
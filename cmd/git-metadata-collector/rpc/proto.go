@@ -8,10 +8,32 @@ type RunningTaskDTO struct {
 	Progress string    `json:"progress"`
 }
 
+type HostStatusDTO struct {
+	Host         string    `json:"host"`
+	Concurrency  int       `json:"concurrency"`
+	Rate         float64   `json:"rate"`
+	Running      int       `json:"running"`
+	Waiting      int       `json:"waiting"`
+	Throttled    int       `json:"throttled"`
+	BackoffUntil time.Time `json:"backoffUntil"`
+}
+
+// StorageStatusDTO is the usage of the mirrors at the last check of the
+// storage budget, sizes are in bytes
+type StorageStatusDTO struct {
+	Budget    int64     `json:"budget"`
+	Used      int64     `json:"used"`
+	Freed     int64     `json:"freed"`
+	Prune     string    `json:"prune"`
+	CheckTime time.Time `json:"checkTime"`
+}
+
 type StatusResp struct {
-	CurrentTasks []RunningTaskDTO `json:"currentTasks"`
-	PendingTasks []string         `json:"pendingTasks"`
-	IsRunning    bool             `json:"isRunning"`
+	CurrentTasks []RunningTaskDTO  `json:"currentTasks"`
+	PendingTasks []string          `json:"pendingTasks"`
+	IsRunning    bool              `json:"isRunning"`
+	Hosts        []HostStatusDTO   `json:"hosts"`
+	Storage      *StorageStatusDTO `json:"storage"`
 }
 
 type RpcService interface {
@@ -1,7 +1,10 @@
 package main
 
 import (
-	"log"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
 
 	"github.com/HUSTSecLab/OpenSift/pkg/config"
 	"github.com/HUSTSecLab/OpenSift/pkg/logger"
@@ -14,12 +17,22 @@ import (
 var (
 	batchSize     = pflag.Int("batch", 1000, "batch size")
 	calcType      = pflag.String("calc", "all", "calculation type: distro, git, langeco, all")
-	normalization = pflag.String("normalization", "log", "normalization type: log, sigmoid")
+	normalization = pflag.String("normalization", "log", "normalization type: log, sigmoid, percentile, zscore, minmax,\nselects the builtin scoring profile when --profile is not set")
+	profilePath   = pflag.String("profile", "", "scoring profile file in yaml or json format")
+	sensitivity   = pflag.Float64("sensitivity", 0, "if set, run sensitivity analysis instead of updating scores:\nperturb each weight and threshold by ± this percentage and compare rankings")
+	topK          = pflag.Int("topk", 100, "size of the top of the ranking compared in sensitivity analysis")
+	moves         = pflag.Int("moves", 10, "number of largest rank moves reported per perturbation in sensitivity analysis")
 )
 
 func main() {
 	config.RegistCommonFlags(pflag.CommandLine)
 	config.ParseFlags(pflag.CommandLine)
+	profile, err := scores.GetProfile(*profilePath, *normalization)
+	if err != nil {
+		logger.Fatalf("Failed to load scoring profile: %v", err)
+	}
+	logger.Infof("Using scoring profile %s (%s)", profile.Name, profile.Hash)
+
 	ac := storage.GetDefaultAppDatabaseContext()
 	scores.UpdatePackageList(ac)
 	linksMap := scores.FetchGitLink(ac)
@@ -27,31 +40,54 @@ func main() {
 	gitMeticMap := scores.FetchGitMetrics(ac)
 	langEcoMetricMap := scores.FetchLangEcoMetadata(ac)
 	distMetricMap := scores.FetchDistMetadata(ac)
-	var gitMetadataScore = make(map[string]*scores.GitMetadataScore)
-
-	packageScore := make(map[string]*scores.LinkScore)
-	round := scores.GetRound(ac)
 
-	for _, link := range linksMap {
-		if _, ok := distMetricMap[link]; !ok {
-			distMetricMap[link] = scores.NewDistScore()
+	if *sensitivity != 0 {
+		if *sensitivity < 0 || *sensitivity >= 100 {
+			logger.Fatalf("Sensitivity must be in (0, 100), got %v", *sensitivity)
 		}
-		distMetricMap[link].CalculateDistScore(*normalization)
-
-		if _, ok := langEcoMetricMap[link]; !ok {
-			langEcoMetricMap[link] = scores.NewLangEcoScore()
+		results, err := scores.AnalyzeSensitivity(linksMap, gitMeticMap, distMetricMap, langEcoMetricMap, profile, scores.SensitivityOptions{
+			Delta: *sensitivity / 100,
+			TopK:  *topK,
+			Moves: *moves,
+		})
+		if err != nil {
+			logger.Fatalf("Failed to analyze sensitivity: %v", err)
 		}
-		langEcoMetricMap[link].CalculateLangEcoScore(*normalization)
+		printSensitivityReport(os.Stdout, results)
+		return
+	}
 
-		gitMetadataScore[link] = scores.NewGitMetadataScore()
-		if _, ok := gitMeticMap[link]; !ok {
-			log.Println("No git metadata for ", link)
-		} else {
-			gitMetadataScore[link].CalculateGitMetadataScore(gitMeticMap[link], *normalization)
-		}
-		packageScore[link] = scores.NewLinkScore(gitMetadataScore[link], distMetricMap[link], langEcoMetricMap[link], round+1)
-		packageScore[link].CalculateScore(*normalization)
+	round := scores.GetRound(ac)
+
+	packageScore, err := scores.CalculateScores(linksMap, gitMeticMap, distMetricMap, langEcoMetricMap, profile, round+1)
+	if err != nil {
+		logger.Fatalf("Failed to calculate scores: %v", err)
 	}
 	logger.Println("Updating database...")
 	scores.UpdateScore(ac, packageScore)
 }
+
+func printSensitivityReport(w io.Writer, results []*scores.SensitivityResult) {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "PERTURBATION\tKENDALL TAU\tTOP-%d OVERLAP\n", *topK)
+	for _, r := range results {
+		fmt.Fprintf(tw, "%s\t%.4f\t%.4f\n", r.Perturbation, r.KendallTau, r.TopKOverlap)
+	}
+	tw.Flush()
+
+	for _, r := range results {
+		if len(r.Moves) == 0 || r.Moves[0].Distance() == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "\n%s, largest rank moves:\n", r.Perturbation)
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		fmt.Fprintf(tw, "  LINK\tBASE RANK\tRANK\tMOVE\n")
+		for _, m := range r.Moves {
+			if m.Distance() == 0 {
+				break
+			}
+			fmt.Fprintf(tw, "  %s\t%d\t%d\t%+d\n", m.Link, m.BaseRank, m.Rank, m.BaseRank-m.Rank)
+		}
+		tw.Flush()
+	}
+}
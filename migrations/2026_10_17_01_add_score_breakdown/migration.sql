@@ -0,0 +1,13 @@
+create table if not exists score_breakdowns
+(
+    id           int8 primary key generated always as identity,
+    score_id     int8 not null references scores (id),
+    dimension    varchar not null,
+    metric       varchar not null,
+    value        float8,
+    normalized   float8,
+    weight       float8,
+    contribution float8
+);
+
+create index if not exists score_breakdowns_score_id_idx on score_breakdowns (score_id);
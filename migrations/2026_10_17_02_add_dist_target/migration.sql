@@ -0,0 +1,84 @@
+-- packages are collected per (release, component, arch) target, the package
+-- tables are keyed by package and target, and the relationships tables by
+-- relationship and target of the dependent package, which still references
+-- its package.
+do
+$$
+    declare
+        t   text;
+        rel text;
+        c   text;
+    begin
+        foreach t in array array ['alpine', 'arch', 'aur', 'centos', 'debian', 'deepin', 'fedora', 'gentoo',
+            'homebrew', 'nix', 'ubuntu', 'openeuler', 'openkylin']
+            loop
+                if to_regclass(t || '_packages') is null then
+                    continue;
+                end if;
+                rel := t || '_relationships';
+                if to_regclass(rel) is null then
+                    rel := null;
+                end if;
+
+                execute format('alter table %I add column if not exists release varchar not null default '''', ' ||
+                               'add column if not exists component varchar not null default '''', ' ||
+                               'add column if not exists arch varchar not null default ''''', t || '_packages');
+
+                -- the keys of the relationships are dropped first, so that
+                -- the primary key of the packages is dropped without cascade
+                if rel is not null then
+                    execute format('alter table %I add column if not exists release varchar not null default '''', ' ||
+                                   'add column if not exists component varchar not null default '''', ' ||
+                                   'add column if not exists arch varchar not null default ''''', rel);
+                    for c in select conname
+                             from pg_constraint
+                             where conrelid = rel::regclass
+                               and contype in ('f', 'p')
+                             order by contype
+                        loop
+                            execute format('alter table %I drop constraint %I', rel, c);
+                        end loop;
+                end if;
+
+                for c in select conname
+                         from pg_constraint
+                         where conrelid = (t || '_packages')::regclass
+                           and contype = 'p'
+                    loop
+                        execute format('alter table %I drop constraint %I', t || '_packages', c);
+                    end loop;
+                execute format('alter table %I add primary key (package, release, component, arch)', t || '_packages');
+
+                if rel is not null then
+                    execute format('alter table %I add primary key (frompackage, topackage, release, component, arch)',
+                                   rel);
+                    execute format('alter table %I add foreign key (frompackage, release, component, arch) ' ||
+                                   'references %I (package, release, component, arch)', rel, t || '_packages');
+                end if;
+            end loop;
+    end
+$$;
+
+alter table distribution_dependencies
+    add column release   varchar not null default '',
+    add column component varchar not null default '',
+    add column arch      varchar not null default '';
+
+create index on distribution_dependencies (git_link, type, release, arch);
+
+-- the rows collected before are of the former single target of every
+-- distribution, so that the latest row of each target replaces them
+update distribution_dependencies d
+set release   = t.release,
+    component = t.component,
+    arch      = t.arch
+from (values (0, 'stable', 'main', 'amd64'),
+             (1, '', '', 'x86_64'),
+             (4, 'v3.21', 'main', 'x86_64'),
+             (5, '7', 'os', 'x86_64'),
+             (7, 'beige', 'main', 'amd64'),
+             (8, '41', 'Everything', 'source'),
+             (10, 'jammy', '', 'amd64'),
+             (11, '25.03', '', 'source'),
+             (12, 'huanghe', 'main', 'amd64')) as t (type, release, component, arch)
+where d.type = t.type;
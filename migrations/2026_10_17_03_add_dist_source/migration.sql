@@ -0,0 +1,20 @@
+-- source package of each binary package, collectors aggregate the binary
+-- packages of a source package before computing the dependency metrics
+do
+$$
+    declare
+        t text;
+    begin
+        foreach t in array array ['alpine', 'arch', 'aur', 'centos', 'debian', 'deepin', 'fedora', 'gentoo',
+            'homebrew', 'nix', 'ubuntu', 'openeuler', 'openkylin']
+            loop
+                if to_regclass(t || '_packages') is null then
+                    continue;
+                end if;
+                execute format('alter table %I add column if not exists source varchar', t || '_packages');
+                execute format('update %I set source = package where source is null', t || '_packages');
+                execute format('create index if not exists %I on %I (source)', t || '_packages_source_idx',
+                               t || '_packages');
+            end loop;
+    end
+$$;
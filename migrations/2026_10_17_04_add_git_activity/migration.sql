@@ -0,0 +1,19 @@
+-- activity of the windows of 30, 90 and 365 days before the collection and of
+-- the whole history, see Repo.WalkLog
+alter table git_metrics
+    add column if not exists commits_30d integer,
+    add column if not exists authors_30d integer,
+    add column if not exists orgs_30d integer,
+    add column if not exists maintainers_30d integer,
+    add column if not exists commits_90d integer,
+    add column if not exists authors_90d integer,
+    add column if not exists orgs_90d integer,
+    add column if not exists maintainers_90d integer,
+    add column if not exists commits_365d integer,
+    add column if not exists authors_365d integer,
+    add column if not exists orgs_365d integer,
+    add column if not exists maintainers_365d integer,
+    add column if not exists commits_lifetime integer,
+    add column if not exists authors_lifetime integer,
+    add column if not exists orgs_lifetime integer,
+    add column if not exists maintainers_lifetime integer;
@@ -0,0 +1,9 @@
+-- concentration of the commits of the last 12 months on few contributors and
+-- organizations, and the last commit of the top 3 contributors of the whole
+-- history, see git.Concentration
+alter table git_metrics
+    add column if not exists bus_factor_50               integer,
+    add column if not exists bus_factor_80               integer,
+    add column if not exists commit_gini                 double precision,
+    add column if not exists top_org_share               double precision,
+    add column if not exists top_maintainers_last_commit timestamp;
@@ -0,0 +1,7 @@
+-- state of the last walk of the log of a repository, so the next collection
+-- only walks the new commits, see git.Checkpoint
+create table if not exists git_checkpoints (
+    git_link    text not null primary key,
+    data        bytea not null,
+    update_time timestamptz
+);
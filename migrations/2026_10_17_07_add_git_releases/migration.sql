@@ -0,0 +1,6 @@
+-- releases found from the tags looking like versions, see git.Releases
+alter table git_metrics
+    add column if not exists semver_tags             integer,
+    add column if not exists releases_365d           integer,
+    add column if not exists last_release            timestamp,
+    add column if not exists median_release_interval double precision;
@@ -0,0 +1,8 @@
+-- license inventory of the files of the repository, see git.Licenses
+alter table git_metrics
+    add column if not exists license_expression text,
+    add column if not exists license_coverage   jsonb,
+    add column if not exists license_families   text[];
+
+create index if not exists idx_git_metrics_license_families
+    on git_metrics using gin (license_families);
@@ -0,0 +1,17 @@
+-- queue of the git links to collect shared by the collectors, a task is
+-- leased by one collector until leased_until, see repository.GitTaskRepository
+create table if not exists git_tasks (
+    git_link     text             not null primary key,
+    priority     double precision not null default 0,
+    manual       boolean          not null default false,
+    attempts     integer          not null default 0,
+    lease_owner  text,
+    leased_until timestamptz,
+    available_at timestamptz      not null default now(),
+    last_error   text,
+    enqueue_time timestamptz      not null default now(),
+    update_time  timestamptz      not null default now()
+);
+
+create index if not exists git_tasks_order_idx
+    on git_tasks (manual desc, priority desc, enqueue_time, git_link);
@@ -0,0 +1,8 @@
+-- state of the mirrors kept under the storage budget of the collector,
+-- take_storage is the size of the mirror in bytes, 0 once evicted
+alter table git_files
+    add column if not exists shallow    boolean not null default false,
+    add column if not exists evict_time timestamptz;
+
+create index if not exists git_files_take_storage_idx
+    on git_files (take_storage) where take_storage > 0;
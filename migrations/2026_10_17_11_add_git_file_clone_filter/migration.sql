@@ -0,0 +1,12 @@
+-- filter of the partial mirrors, e.g. blob:none, null for a full mirror
+alter table git_files
+    add column if not exists clone_filter text;
+
+-- size of the last full mirror, kept once it is pruned or cloned again
+-- without blobs
+alter table git_files
+    add column if not exists full_storage bigint;
+
+update git_files
+set full_storage = take_storage
+where take_storage > 0 and not shallow and clone_filter is null;
@@ -0,0 +1,38 @@
+-- spellings and former locations of the git links mapped to their canonical
+-- link, see repository.GitLinkAliasRepository
+create table if not exists git_link_aliases (
+    alias       varchar     not null primary key,
+    git_link    varchar     not null,
+    -- variant: another spelling of the link, redirect: the repository moved
+    reason      varchar     not null,
+    update_time timestamptz not null default now()
+);
+
+create index if not exists git_link_aliases_git_link_idx on git_link_aliases (git_link);
+
+-- links of every source, as they are spelled there
+create or replace view source_gitlinks as
+select git_link from (
+                         select distinct git_link from debian_packages
+                         union distinct select git_link from arch_packages
+                         union distinct select git_link from homebrew_packages
+                         union distinct select git_link from nix_packages
+                         union distinct select git_link from alpine_packages
+                         union distinct select git_link from centos_packages
+                         union distinct select git_link from aur_packages
+                         union distinct select git_link from deepin_packages
+                         union distinct select git_link from fedora_packages
+                         union distinct select git_link from gentoo_packages
+                         union distinct select git_link from ubuntu_packages
+                         union distinct select git_link from github_links
+                         union distinct select git_link from gitlab_links
+                         union distinct select git_link from bitbucket_links) t
+where git_link is not null and git_link <> '' and git_link <> 'NA' and git_link <> 'NaN';
+
+-- links of every source resolved through their aliases, once each
+create or replace view all_gitlinks as
+select distinct coalesce(a.git_link, s.git_link) as git_link
+from source_gitlinks s
+         left join git_link_aliases a on a.alias = s.git_link
+where s.git_link not in (select git_link from git_link_blacklist)
+  and coalesce(a.git_link, s.git_link) not in (select git_link from git_link_blacklist);
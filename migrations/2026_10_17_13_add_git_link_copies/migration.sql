@@ -0,0 +1,28 @@
+-- root commits and sketch of the commits of every repository collected, see
+-- git.History
+create table if not exists git_histories (
+    git_link    varchar     not null primary key,
+    roots       varchar[]   not null,
+    sketch      varchar[]   not null,
+    commits     integer     not null,
+    -- committer time of the oldest commit
+    created_since timestamptz,
+    update_time timestamptz not null default now()
+);
+
+create index if not exists git_histories_roots_idx on git_histories using gin (roots);
+
+-- mirrors and forks of another repository, see
+-- repository.GitLinkCopyRepository. Mirrors and the forks confirmed by a
+-- labeler are scored as their canonical repository.
+create table if not exists git_link_copies (
+    git_link    varchar     not null primary key,
+    canonical   varchar     not null,
+    -- mirror: same history as the canonical repository, fork: most of it
+    relation    varchar     not null,
+    -- a labeler confirmed the fork is a copy of the canonical repository
+    confirmed   boolean     not null default false,
+    update_time timestamptz not null default now()
+);
+
+create index if not exists git_link_copies_canonical_idx on git_link_copies (canonical);
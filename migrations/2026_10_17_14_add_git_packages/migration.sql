@@ -0,0 +1,32 @@
+-- packages declared by the manifests and lock files at the head of every
+-- repository collected, see git.LangEcoParsers
+create table if not exists git_packages (
+    git_link    varchar     not null,
+    -- parser ecosystem, e.g. npm, COMPOSER, rubygems
+    ecosystem   varchar     not null,
+    name        varchar     not null,
+    version     varchar,
+    update_time timestamptz not null default now(),
+    primary key (git_link, ecosystem, name)
+);
+
+-- packages the packages of a repository depend on
+create table if not exists git_package_dependencies (
+    git_link    varchar     not null,
+    ecosystem   varchar     not null,
+    name        varchar     not null,
+    update_time timestamptz not null default now(),
+    primary key (git_link, ecosystem, name)
+);
+
+create index if not exists git_package_dependencies_name_idx on git_package_dependencies (ecosystem, name);
+
+-- lang_ecosystems types of the ecosystems not covered by deps.dev, whose
+-- dependents are counted from git_package_dependencies:
+-- others 6
+-- packagist 7
+-- rubygems 8
+-- hex 9
+-- conan 10
+-- conda 11
+-- swiftpm 12
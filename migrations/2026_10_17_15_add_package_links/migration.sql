@@ -0,0 +1,27 @@
+-- repositories of the packages of language ecosystems as declared by their
+-- registries, recorded by the link enumerators
+create table if not exists registry_packages (
+    -- parser ecosystem, as git_packages.ecosystem
+    ecosystem   varchar     not null,
+    name        varchar     not null,
+    git_link    varchar     not null,
+    update_time timestamptz not null default now(),
+    primary key (ecosystem, name)
+);
+
+-- the repositories whose manifests declare a package, cross-validated with
+-- the repository declared by its registry, see depsdev.PackageLinks
+create table if not exists package_links (
+    ecosystem      varchar     not null,
+    name           varchar     not null,
+    manifest_links varchar[]   not null,
+    registry_link  varchar,
+    -- agree, disagree or unregistered
+    status         varchar     not null,
+    -- the repository of the package chosen by a labeler, for review only
+    label_link     varchar,
+    update_time    timestamptz not null default now(),
+    primary key (ecosystem, name)
+);
+
+create index if not exists package_links_status_idx on package_links (status);
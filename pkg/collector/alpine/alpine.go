@@ -1,11 +1,9 @@
 package alpine
 
 import (
-	"log"
 	"strings"
 
 	collector "github.com/HUSTSecLab/OpenSift/pkg/collector/internal"
-	"github.com/HUSTSecLab/OpenSift/pkg/storage"
 	"github.com/HUSTSecLab/OpenSift/pkg/storage/repository"
 )
 
@@ -14,24 +12,7 @@ type AlpineCollector struct {
 }
 
 func (ac *AlpineCollector) Collect(outputPath string) {
-	adc := storage.GetDefaultAppDatabaseContext()
-	data := ac.GetPackageInfo(collector.AlpineURL)
-	ac.ParseInfo(data)
-	ac.GetDep()
-	ac.PageRank(0.85, 20)
-	ac.GetDepCount()
-	ac.UpdateRelationships(adc)
-	ac.UpdateDistRepoCount(adc)
-	ac.CalculateDistImpact()
-	ac.UpdateOrInsertDatabase(adc)
-	ac.UpdateOrInsertDistDependencyDatabase(adc)
-	if outputPath != "" {
-		err := ac.GenerateDependencyGraph(outputPath)
-		if err != nil {
-			log.Printf("Error generating dependency graph: %v\n", err)
-			return
-		}
-	}
+	collector.Collect(ac.CollecterInterface, collector.Alpine, ac.ParseInfo, outputPath)
 }
 
 func (ac *AlpineCollector) ParseInfo(data string) {
@@ -63,6 +44,8 @@ func (ac *AlpineCollector) ParseInfo(data string) {
 				pkg.Description = line[2:]
 			case "U:":
 				pkg.Homepage = line[2:]
+			case "o:":
+				pkg.Source = line[2:]
 			}
 		}
 		if pkg.Name != "" {
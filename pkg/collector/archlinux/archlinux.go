@@ -1,11 +1,9 @@
 package archlinux
 
 import (
-	"log"
 	"strings"
 
 	collector "github.com/HUSTSecLab/OpenSift/pkg/collector/internal"
-	"github.com/HUSTSecLab/OpenSift/pkg/storage"
 	"github.com/HUSTSecLab/OpenSift/pkg/storage/repository"
 )
 
@@ -14,24 +12,7 @@ type ArchLinuxCollector struct {
 }
 
 func (al *ArchLinuxCollector) Collect(outputPath string) {
-	adc := storage.GetDefaultAppDatabaseContext()
-	data := al.GetPackageInfo(collector.ArchlinuxURL)
-	al.ParseInfo(data)
-	al.GetDep()
-	al.PageRank(0.85, 20)
-	al.GetDepCount()
-	al.UpdateRelationships(adc)
-	al.UpdateDistRepoCount(adc)
-	al.CalculateDistImpact()
-	al.UpdateOrInsertDatabase(adc)
-	al.UpdateOrInsertDistDependencyDatabase(adc)
-	if outputPath != "" {
-		err := al.GenerateDependencyGraph(outputPath)
-		if err != nil {
-			log.Printf("Error generating dependency graph: %v\n", err)
-			return
-		}
-	}
+	collector.Collect(al.CollecterInterface, collector.Archlinux, al.ParseInfo, outputPath)
 }
 
 func (al *ArchLinuxCollector) ParseInfo(data string) {
@@ -46,6 +27,8 @@ func (al *ArchLinuxCollector) ParseInfo(data string) {
 				al.SetPkgInfo(currentPkg.Name, currentPkg)
 			}
 			currentPkg = &collector.PackageInfo{Name: strings.TrimSpace(lines[idx+1])}
+		case line == "%BASE%":
+			currentPkg.Source = strings.TrimSpace(lines[idx+1])
 		case line == "%DESC%":
 			currentPkg.Description = strings.TrimSpace(lines[idx+1])
 		case line == "%VERSION%":
@@ -1,14 +1,10 @@
 package aur
 
 import (
-	"bufio"
 	"encoding/json"
 	"log"
-	"net/http"
-	"strings"
 
 	collector "github.com/HUSTSecLab/OpenSift/pkg/collector/internal"
-	"github.com/HUSTSecLab/OpenSift/pkg/storage"
 	"github.com/HUSTSecLab/OpenSift/pkg/storage/repository"
 )
 
@@ -17,57 +13,31 @@ type AurCollector struct {
 }
 
 func (ac *AurCollector) Collect(outputPath string) {
-	adc := storage.GetDefaultAppDatabaseContext()
-	data := ac.GetPackageInfo(collector.AurURL)
-	ac.ParseInfo(data)
-	ac.GetDep()
-	ac.PageRank(0.85, 20)
-	ac.GetDepCount()
-	ac.UpdateRelationships(adc)
-	ac.UpdateDistRepoCount(adc)
-	ac.CalculateDistImpact()
-	ac.UpdateOrInsertDatabase(adc)
-	ac.UpdateOrInsertDistDependencyDatabase(adc)
-	if outputPath != "" {
-		err := ac.GenerateDependencyGraph(outputPath)
-		if err != nil {
-			log.Printf("Error generating dependency graph: %v\n", err)
-			return
+	collector.Collect(ac.CollecterInterface, collector.Aur, func(data string) {
+		if err := ac.ParseInfo(data); err != nil {
+			log.Printf("Error parsing package info: %v\n", err)
 		}
-	}
+	}, outputPath)
 }
 
 func (ac *AurCollector) ParseInfo(data string) error {
-	var packages []collector.PackageInfo
+	var packages []struct {
+		collector.PackageInfo
+		// PackageBase is the source package the package is built from
+		PackageBase string
+	}
 	err := json.Unmarshal([]byte(data), &packages)
 	if err != nil {
 		return err
 	}
 
 	for _, pkg := range packages {
-		ac.SetPkgInfo(pkg.Name, &pkg)
+		pkg.Source = pkg.PackageBase
+		ac.SetPkgInfo(pkg.Name, &pkg.PackageInfo)
 	}
 	return nil
 }
 
-func (ac *AurCollector) GetPackageInfo(urls collector.PackageURL) string {
-	resp, err := http.Get(urls[0])
-	if err != nil {
-		log.Printf("Error making HTTP request: %v\n", err)
-	}
-	defer resp.Body.Close()
-
-	var result strings.Builder
-	scanner := bufio.NewScanner(resp.Body)
-	for scanner.Scan() {
-		result.WriteString(scanner.Text() + "\n")
-	}
-	if err := scanner.Err(); err != nil {
-		log.Printf("Error reading response body: %v\n", err)
-	}
-	return result.String()
-}
-
 func NewAurCollector() *AurCollector {
 	return &AurCollector{
 		collector.NewCollector(repository.Aur, repository.DistPackageTablePrefix("aur")),
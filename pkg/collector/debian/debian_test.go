@@ -0,0 +1,43 @@
+package debian
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseInfoFromMirror(t *testing.T) {
+	dc := NewDebianCollector()
+	dc.SetSources([]string{filepath.Join("testdata", "mirror")})
+
+	data := dc.GetPackageInfo(dc.GetSources(nil))
+	dc.ParseInfo(data)
+	dc.ResolveDepends()
+
+	curl := dc.GetPkgInfo("curl")
+	require.NotNil(t, curl)
+	require.Equal(t, "7.88.1-10", curl.Version)
+	require.Equal(t, "https://curl.se/", curl.Homepage)
+	require.Equal(t, "curl", curl.Source)
+	// Pre-Depends first, alternatives resolved to a single package, the
+	// virtual mail-transport-agent resolved to its provider
+	require.Equal(t, []string{"libc6", "libcurl4", "postfix", "zlib1g"}, curl.DirectDepends)
+
+	libcurl := dc.GetPkgInfo("libcurl4")
+	require.Equal(t, "curl", libcurl.Source)
+	// libz1 is provided by zlib1g, libnghttp2-14 is not in the index
+	require.Equal(t, []string{"libc6", "zlib1g", "libnghttp2-14"}, libcurl.DirectDepends)
+
+	libc := dc.GetPkgInfo("libc6")
+	require.Equal(t, "glibc", libc.Source)
+	require.Equal(t, "GNU C Library: Shared libraries\n"+
+		"Contains the standard libraries that are used by nearly all programs on\n"+
+		"the system.\n"+
+		"\n"+
+		"This package includes shared versions of the standard C library.", libc.Description)
+
+	require.Equal(t, "gcc-12", dc.GetPkgInfo("libgcc-s1").Source)
+	require.Equal(t, []string{"libc6"}, dc.GetPkgInfo("libgcc-s1").DirectDepends)
+	require.Equal(t, []string{"libz1"}, dc.GetPkgInfo("zlib1g").Provides)
+}
@@ -11,6 +11,7 @@ import (
 	"strings"
 
 	collector "github.com/HUSTSecLab/OpenSift/pkg/collector/internal"
+	"github.com/HUSTSecLab/OpenSift/pkg/graph"
 	"github.com/HUSTSecLab/OpenSift/pkg/storage"
 	"github.com/HUSTSecLab/OpenSift/pkg/storage/repository"
 )
@@ -27,13 +28,12 @@ func (hc *HomebrewCollector) Collect(outputPath string, downloadDir string) {
 		return
 	}
 	hc.ParseInfo(downloadDir)
-	hc.GetDep()
-	hc.PageRank(0.85, 20)
+	hc.PageRank(graph.DefaultPageRankOptions())
 	hc.GetDepCount()
-	hc.UpdateRelationships(adc)
 	hc.UpdateDistRepoCount(adc)
 	hc.CalculateDistImpact()
 	hc.UpdateOrInsertDatabase(adc)
+	hc.UpdateRelationships(adc)
 	hc.UpdateOrInsertDistDependencyDatabase(adc)
 	if outputPath != "" {
 		err = hc.GenerateDependencyGraph(outputPath)
@@ -1,32 +1,35 @@
 package collector
 
 import (
-	"archive/tar"
 	"bufio"
-	"compress/gzip"
 	"fmt"
-	"io"
 	"log"
-	"net/http"
 	"os"
+	"sort"
 	"strings"
 
+	"github.com/HUSTSecLab/OpenSift/pkg/graph"
 	"github.com/HUSTSecLab/OpenSift/pkg/storage"
 	"github.com/HUSTSecLab/OpenSift/pkg/storage/repository"
-	"github.com/klauspost/compress/zstd"
 	"github.com/samber/lo"
 )
 
 type CollecterInterface interface {
 	GetPackageInfo(urls PackageURL) string
+	SetSources(sources []string)
+	GetSources(defaults PackageURL) PackageURL
+	SetTargets(targets []string) error
+	GetTargets(defaults []Target) []Target
+	SetTarget(target Target)
+	Reset()
 	UpdateOrInsertDatabase(ac storage.AppDatabaseContext)
 	UpdateOrInsertDistDependencyDatabase(ac storage.AppDatabaseContext)
 	GenerateDependencyGraph(outputPath string) error
-	GetAllDep(pkgName string, visited map[string]bool, deps []string) []string
-	PageRank(d float64, iterations int)
+	PageRank(opts graph.PageRankOptions)
 	ParseInfo(data string)
 	GetDepCount()
-	GetDep()
+	ResolveDepends()
+	AggregateSources()
 	SetPkgInfo(pkgName string, pkgInfo *PackageInfo)
 	GetPkgInfo(pkgName string) *PackageInfo
 	CalculateDistImpact()
@@ -39,6 +42,15 @@ type Collecter struct {
 	DistRepoCount          int
 	Type                   repository.DistType
 	DistPackageTablePrefix repository.DistPackageTablePrefix
+	Sources                PackageURL
+	Targets                []Target
+	// Target is the target of the packages being parsed
+	Target Target
+	// Binaries holds the binary packages once PkgInfoMap holds their source
+	// packages, see AggregateSources
+	Binaries map[string]PackageInfo
+	// Cycles are the dependency cycles found by GetDepCount
+	Cycles [][]string
 }
 
 func NewCollector(Type repository.DistType, DistPackageTablePrefix repository.DistPackageTablePrefix) CollecterInterface {
@@ -49,8 +61,19 @@ func NewCollector(Type repository.DistType, DistPackageTablePrefix repository.Di
 	}
 }
 
+// UpdateOrInsertDatabase stores the packages. Once aggregated by source,
+// the binary packages are stored with the dependency count of their source.
 func (cl *Collecter) UpdateOrInsertDatabase(ac storage.AppDatabaseContext) {
-	for _, pkgInfo := range cl.PkgInfoMap {
+	packages := cl.PkgInfoMap
+	if cl.Binaries != nil {
+		packages = cl.Binaries
+	}
+	for _, pkgInfo := range packages {
+		if cl.Binaries != nil {
+			if source, ok := cl.PkgInfoMap[pkgInfo.SourceName()]; ok {
+				pkgInfo.DependsCount = source.DependsCount
+			}
+		}
 		distPackage := pkgInfo.ParseDistPackage()
 		repo := repository.NewDistPackageRepository(ac, cl.DistPackageTablePrefix)
 
@@ -75,22 +98,18 @@ func (cl *Collecter) GenerateDependencyGraph(outputPath string) error {
 	writer := bufio.NewWriter(file)
 	writer.WriteString("digraph {\n")
 
-	packageIndices := make(map[string]int)
-	index := 0
-
-	for pkgName, pkgInfo := range cl.PkgInfoMap {
-		packageIndices[pkgName] = index
-		label := fmt.Sprintf("%s@%s", pkgName, pkgInfo.Description)
-		writer.WriteString(fmt.Sprintf("  %d [label=\"%s\"];\n", index, label))
-		index++
+	// the direct dependencies, the transitive ones can be derived from them
+	g := cl.DependencyGraph()
+	for i := 0; i < g.Len(); i++ {
+		// the synopsis only, descriptions may span several lines
+		synopsis, _, _ := strings.Cut(cl.PkgInfoMap[g.Name(i)].Description, "\n")
+		label := fmt.Sprintf("%s@%s", g.Name(i), synopsis)
+		writer.WriteString(fmt.Sprintf("  %d [label=\"%s\"];\n", i, label))
 	}
 
-	for pkgName, pkgInfo := range cl.PkgInfoMap {
-		pkgIndex := packageIndices[pkgName]
-		for _, depName := range pkgInfo.IndirectDepends {
-			if depIndex, ok := packageIndices[depName]; ok {
-				writer.WriteString(fmt.Sprintf("  %d -> %d;\n", pkgIndex, depIndex))
-			}
+	for i := 0; i < g.Len(); i++ {
+		for _, j := range g.Neighbours(i) {
+			writer.WriteString(fmt.Sprintf("  %d -> %d;\n", i, j))
 		}
 	}
 
@@ -99,199 +118,249 @@ func (cl *Collecter) GenerateDependencyGraph(outputPath string) error {
 	return nil
 }
 
-func (cl *Collecter) GetAllDep(pkgName string, visited map[string]bool, deps []string) []string {
-	if visited[pkgName] {
-		return deps
-	}
-
-	visited[pkgName] = true
-	deps = append(deps, pkgName)
+// DependencyGraph returns the graph of the direct dependencies between the
+// packages of the collector. Dependencies outside of the collector are
+// dropped, version constraints such as foo>=1.0 are stripped.
+func (cl *Collecter) DependencyGraph() *graph.Graph {
+	return graph.Build(cl.PkgInfoMap, func(_ string, pkg PackageInfo) []string {
+		return lo.Map(pkg.DirectDepends, func(dep string, _ int) string { return dependencyName(dep) })
+	})
+}
 
-	if pkg, ok := cl.PkgInfoMap[pkgName]; ok {
-		for _, depName := range pkg.DirectDepends {
-			deps = cl.GetAllDep(depName, visited, deps)
-		}
+func dependencyName(dep string) string {
+	if idx := strings.IndexAny(dep, "<>="); idx > 0 {
+		return dep[:idx]
 	}
-	return deps
+	return dep
 }
 
-func (cl *Collecter) PageRank(d float64, iterations int) {
-	ranks := make(map[string]float64)
-	N := float64(len(cl.PkgInfoMap))
+// PageRank sets the PageRank of every package, rank flows from a package to
+// its dependencies.
+func (cl *Collecter) PageRank(opts graph.PageRankOptions) {
+	g := cl.DependencyGraph()
+	result, err := g.PageRank(opts)
+	if err != nil {
+		log.Printf("Error calculating PageRank: %v\n", err)
+		return
+	}
+	if !result.Converged {
+		log.Printf("PageRank did not converge after %d iterations\n", result.Iterations)
+	}
 
-	for pkgName := range cl.PkgInfoMap {
-		ranks[pkgName] = 1.0 / N
+	for i, rank := range result.Ranks {
+		name := g.Name(i)
+		pkgInfo := cl.PkgInfoMap[name]
+		pkgInfo.PageRank = rank
+		cl.PkgInfoMap[name] = pkgInfo
 	}
+}
 
-	for i := 0; i < iterations; i++ {
-		newRanks := make(map[string]float64)
+func (cl *Collecter) ParseInfo(pkgInfo string) {
+	log.Println("Parsing package info for", pkgInfo)
+}
 
-		for pkgName := range cl.PkgInfoMap {
-			newRanks[pkgName] = (1 - d) / N
-		}
+// GetPackageInfo returns the concatenated content of the package index
+// files of the current target at urls, see ExpandSources and ReadSource for
+// the accepted sources.
+// The repomd.xml of a rpm repository is replaced by its primary index.
+func (cl *Collecter) GetPackageInfo(urls PackageURL) string {
+	var result strings.Builder
 
-		for pkgName, pkgInfo := range cl.PkgInfoMap {
-			var depNum int
-			for _, depName := range pkgInfo.DirectDepends {
-				if _, exists := cl.PkgInfoMap[depName]; exists {
-					depNum++
-				}
-			}
+	sources, err := ExpandSources(urls, cl.Target)
+	if err != nil {
+		fmt.Println("Error expanding package sources:", err)
+		return ""
+	}
 
-			share := ranks[pkgName] / float64(depNum)
-			for _, dep := range pkgInfo.DirectDepends {
-				if _, exists := cl.PkgInfoMap[dep]; exists {
-					newRanks[dep] += d * share
-				}
+	for _, source := range sources {
+		if IsRepomd(source) {
+			source, err = ResolvePrimary(source)
+			if err != nil {
+				fmt.Println("Error resolving primary package index:", err)
+				continue
 			}
 		}
-
-		ranks = newRanks
+		data, err := ReadSource(source)
+		if err != nil {
+			fmt.Println("Error reading package source:", err)
+			continue
+		}
+		result.WriteString(data)
 	}
 
-	for pkgName, rank := range ranks {
-		pkgInfo := cl.PkgInfoMap[pkgName]
-		pkgInfo.PageRank = rank
-		cl.PkgInfoMap[pkgName] = pkgInfo
-	}
+	return result.String()
 }
 
-func (cl *Collecter) ParseInfo(pkgInfo string) {
-	log.Println("Parsing package info for", pkgInfo)
+// SetSources overrides the default package urls of the collector, e.g. with
+// the path of a local mirror snapshot.
+func (cl *Collecter) SetSources(sources []string) {
+	cl.Sources = sources
 }
 
-func (cl *Collecter) GetPackageInfo(urls PackageURL) string {
-	var result string
+// GetSources returns the sources set by SetSources, or defaults if none.
+func (cl *Collecter) GetSources(defaults PackageURL) PackageURL {
+	if len(cl.Sources) > 0 {
+		return cl.Sources
+	}
+	return defaults
+}
 
-	for _, url := range urls {
-		resp, err := http.Get(url)
+// SetTargets overrides the default targets of the collector, see
+// ParseTarget for the format.
+func (cl *Collecter) SetTargets(targets []string) error {
+	cl.Targets = make([]Target, 0, len(targets))
+	for _, t := range targets {
+		target, err := ParseTarget(t)
 		if err != nil {
-			fmt.Println("Error making HTTP request:", err)
-			continue
+			return err
 		}
-		defer resp.Body.Close()
+		cl.Targets = append(cl.Targets, target)
+	}
+	return nil
+}
 
-		switch {
-		case strings.HasSuffix(url, ".tar.gz"):
-			gzipReader, err := gzip.NewReader(resp.Body)
-			if err != nil {
-				fmt.Println("Error creating gzip reader:", err)
-				continue
-			}
-			defer gzipReader.Close()
+// GetTargets returns the targets set by SetTargets, or defaults if none.
+func (cl *Collecter) GetTargets(defaults []Target) []Target {
+	if len(cl.Targets) > 0 {
+		return cl.Targets
+	}
+	return defaults
+}
+
+// SetTarget sets the target recorded on the packages added by SetPkgInfo.
+func (cl *Collecter) SetTarget(target Target) {
+	cl.Target = target
+}
 
-			tarReader := tar.NewReader(gzipReader)
-			var body strings.Builder
+// Reset clears the packages collected so far, so the collector can be used
+// for another group of targets.
+func (cl *Collecter) Reset() {
+	cl.PkgInfoMap = make(map[string]PackageInfo)
+	cl.Binaries = nil
+	cl.DistRepoCount = 0
+}
 
-			for {
-				_, err := tarReader.Next()
-				if err == io.EOF {
-					break
-				}
-				if err != nil {
-					fmt.Println("Error reading tar content:", err)
-					break
-				}
+// ResolveDepends sets the direct dependencies of the packages having
+// dependency groups. Each group is resolved to a single package: its first
+// alternative which is a package, or else the first provider, by name, of
+// its first alternative which is a virtual package. A group which cannot be
+// resolved is kept as its first alternative.
+func (cl *Collecter) ResolveDepends() {
+	providers := make(map[string][]string)
+	for pkgName, pkgInfo := range cl.PkgInfoMap {
+		for _, provided := range pkgInfo.Provides {
+			providers[provided] = append(providers[provided], pkgName)
+		}
+	}
+	for _, p := range providers {
+		sort.Strings(p)
+	}
 
-				buf := new(strings.Builder)
-				if _, err := io.Copy(buf, tarReader); err != nil {
-					fmt.Println("Error extracting tar content:", err)
-					break
-				}
-				body.WriteString(buf.String())
-			}
-			result += body.String()
+	for pkgName, pkgInfo := range cl.PkgInfoMap {
+		if len(pkgInfo.DependGroups) == 0 {
+			continue
+		}
+		deps := make([]string, 0, len(pkgInfo.DependGroups))
+		for _, group := range pkgInfo.DependGroups {
+			deps = append(deps, cl.resolveGroup(group, providers))
+		}
+		pkgInfo.DirectDepends = lo.Without(lo.Uniq(deps), pkgName)
+		cl.PkgInfoMap[pkgName] = pkgInfo
+	}
+}
 
-		case strings.HasSuffix(url, ".gz"):
-			gzipReader, err := gzip.NewReader(resp.Body)
-			if err != nil {
-				fmt.Println("Error creating gzip reader:", err)
-				continue
-			}
-			defer gzipReader.Close()
+func (cl *Collecter) resolveGroup(group []string, providers map[string][]string) string {
+	for _, name := range group {
+		if _, ok := cl.PkgInfoMap[name]; ok {
+			return name
+		}
+	}
+	for _, name := range group {
+		if p, ok := providers[name]; ok {
+			return p[0]
+		}
+	}
+	return group[0]
+}
 
-			reader := bufio.NewReader(gzipReader)
-			var body strings.Builder
+// AggregateSources replaces the binary packages of PkgInfoMap by the graph
+// of their source packages, so a library split into several binary packages
+// is counted once. The dependencies of a source are the sources of the
+// dependencies of its binaries. The version, description and homepage are
+// the ones of the binary named after the source, or else of the first
+// binary by name. The binary packages are kept in Binaries.
+func (cl *Collecter) AggregateSources() {
+	sources := make(map[string]PackageInfo)
+	for pkgName, pkgInfo := range cl.PkgInfoMap {
+		source := sources[pkgInfo.SourceName()]
+		source.Binaries = append(source.Binaries, pkgName)
+		sources[pkgInfo.SourceName()] = source
+	}
 
-			for {
-				line, err := reader.ReadString('\n')
-				body.WriteString(line)
-				if err == io.EOF {
-					break
-				}
-				if err != nil {
-					fmt.Println("Error reading response body:", err)
-					break
-				}
-			}
-			result += body.String()
-		case strings.HasSuffix(url, ".zst"):
-			zstdReader, err := zstd.NewReader(resp.Body)
-			if err != nil {
-				fmt.Println("Error creating zstd reader:", err)
-				continue
-			}
-			defer zstdReader.Close()
-			reader := bufio.NewReader(zstdReader)
-			var body strings.Builder
-			for {
-				line, err := reader.ReadString('\n')
-				body.WriteString(line)
-				if err == io.EOF {
-					break
+	for sourceName, source := range sources {
+		sort.Strings(source.Binaries)
+		representative := source.Binaries[0]
+		if lo.Contains(source.Binaries, sourceName) {
+			representative = sourceName
+		}
+		binaries := source.Binaries
+		source = cl.PkgInfoMap[representative]
+		source.Name = sourceName
+		source.Source = sourceName
+		source.Binaries = binaries
+		source.Provides = nil
+		source.DependGroups = nil
+
+		deps := make([]string, 0)
+		for _, binary := range binaries {
+			for _, dep := range cl.PkgInfoMap[binary].DirectDepends {
+				if depInfo, ok := cl.PkgInfoMap[dep]; ok {
+					dep = depInfo.SourceName()
 				}
-				if err != nil {
-					fmt.Println("Error reading response body:", err)
-					break
+				if dep != sourceName {
+					deps = append(deps, dep)
 				}
 			}
-			result += body.String()
-		default:
-			fmt.Println("Unsupported file type:", url)
-			continue
 		}
+		source.DirectDepends = lo.Uniq(deps)
+		sources[sourceName] = source
 	}
 
-	return result
+	cl.Binaries = cl.PkgInfoMap
+	cl.PkgInfoMap = sources
 }
 
+// GetDepCount sets the DependsCount of every package, the number of packages
+// depending on it directly or transitively, itself included. The dependency
+// cycles found on the way are logged and kept in Cycles.
 func (cl *Collecter) GetDepCount() {
-	countMap := make(map[string]int)
-
-	for _, deps := range cl.PkgInfoMap {
-		for _, dep := range deps.IndirectDepends {
-			if idx := strings.Index(dep, ">"); idx != -1 {
-				dep = dep[:idx]
-			} else if idx := strings.Index(dep, "="); idx != -1 {
-				dep = dep[:idx]
-			} else if idx := strings.Index(dep, "<"); idx != -1 {
-				dep = dep[:idx]
-			}
-			countMap[dep]++
-		}
-	}
+	g := cl.DependencyGraph()
+	comps := g.StronglyConnectedComponents()
 
-	for pkgName, count := range countMap {
-		if pkgInfo, ok := cl.PkgInfoMap[pkgName]; ok {
-			pkgInfo.DependsCount = count
-			cl.PkgInfoMap[pkgName] = pkgInfo
-		}
+	cl.Cycles = make([][]string, 0)
+	for _, members := range g.Cycles(comps) {
+		cl.Cycles = append(cl.Cycles, lo.Map(members, func(v int, _ int) string { return g.Name(v) }))
+	}
+	if len(cl.Cycles) > 0 {
+		largest := lo.MaxBy(cl.Cycles, func(a, b []string) bool { return len(a) > len(b) })
+		log.Printf("Found %d dependency cycles in %d packages, the largest has %d packages\n",
+			len(cl.Cycles), len(cl.PkgInfoMap), len(largest))
 	}
-}
 
-func (cl *Collecter) GetDep() {
-	for pkgName := range cl.PkgInfoMap {
-		visited := make(map[string]bool)
-		deps := cl.GetAllDep(pkgName, visited, []string{})
-		pkgInfo := cl.PkgInfoMap[pkgName]
-		pkgInfo.IndirectDepends = deps
-		cl.PkgInfoMap[pkgName] = pkgInfo
+	for i, count := range g.AncestorCounts(comps) {
+		name := g.Name(i)
+		pkgInfo := cl.PkgInfoMap[name]
+		pkgInfo.DependsCount = count
+		cl.PkgInfoMap[name] = pkgInfo
 	}
 }
 
 func (cl *Collecter) SetPkgInfo(pkgName string, pkgInfo *PackageInfo) {
 	pkgInfo.Type = cl.Type
 	pkgInfo.DistPackageTablePrefix = cl.DistPackageTablePrefix
+	pkgInfo.Release = cl.Target.Release
+	pkgInfo.Component = cl.Target.Component
+	pkgInfo.Arch = cl.Target.Arch
 	cl.PkgInfoMap[pkgName] = *pkgInfo
 }
 
@@ -318,6 +387,7 @@ func (cl *Collecter) UpdateOrInsertDistDependencyDatabase(ac storage.AppDatabase
 				distMap[*distPackage.GitLink].DepCount = lo.ToPtr(*distMap[*distPackage.GitLink].DepCount + *distPackage.DepCount)
 				distMap[*distPackage.GitLink].DepImpact = lo.ToPtr(*distMap[*distPackage.GitLink].DepImpact + *distPackage.DepImpact)
 				distMap[*distPackage.GitLink].PageRank = lo.ToPtr(*distMap[*distPackage.GitLink].PageRank + *distPackage.PageRank)
+				distMap[*distPackage.GitLink].Component = lo.ToPtr(mergeComponents(*distMap[*distPackage.GitLink].Component, *distPackage.Component))
 			}
 		}
 	}
@@ -331,6 +401,15 @@ func (cl *Collecter) UpdateOrInsertDistDependencyDatabase(ac storage.AppDatabase
 	}
 }
 
+// mergeComponents returns the sorted union of two comma separated lists of
+// components.
+func mergeComponents(a, b string) string {
+	components := lo.Uniq(append(strings.Split(a, ","), strings.Split(b, ",")...))
+	components = lo.Without(components, "")
+	sort.Strings(components)
+	return strings.Join(components, ",")
+}
+
 func (cl *Collecter) CalculateDistImpact() {
 	for _, pkgInfo := range cl.PkgInfoMap {
 		pkgInfo.CalculateImpact(cl.DistRepoCount)
@@ -340,25 +419,51 @@ func (cl *Collecter) CalculateDistImpact() {
 
 func (cl *Collecter) UpdateDistRepoCount(ac storage.AppDatabaseContext) {
 	repo := repository.NewDistDependencyRepository(ac)
-	count, err := repo.QueryDistCountByType(cl.Type)
+	count, err := repo.QueryDistCountByTarget(cl.Type, cl.Target.Release, cl.Target.Arch)
 	if err != nil {
 		log.Println("Error getting count from dist dependency repository:", err)
 		return
 	}
 
-	cl.DistRepoCount = count
+	// packages of a target collected for the first time are not stored yet
+	cl.DistRepoCount = max(count, len(cl.PkgInfoMap))
 }
 
+// UpdateRelationships stores the direct dependencies between the packages
+// stored by UpdateOrInsertDatabase, the binary packages once aggregated by
+// source, in the target of the dependent package. It must run after
+// UpdateOrInsertDatabase, the relationships reference the packages.
 func (cl *Collecter) UpdateRelationships(ac storage.AppDatabaseContext) {
 	repo := repository.NewDistDependencyRepository(ac)
-	relationships := make(map[string][]string)
-	for pkgName, pkgInfo := range cl.PkgInfoMap {
-		relationships[pkgName] = pkgInfo.IndirectDepends
+	packages := cl.PkgInfoMap
+	if cl.Binaries != nil {
+		packages = cl.Binaries
+	}
+	relationships := make([]*repository.DistRelationship, 0)
+	for _, pkgInfo := range packages {
+		if pkgInfo.Name == "" {
+			continue
+		}
+		deps := lo.Uniq(lo.Map(pkgInfo.DirectDepends, func(dep string, _ int) string { return dependencyName(dep) }))
+		for _, dep := range deps {
+			// dependencies outside of the collector are dropped, as in
+			// DependencyGraph
+			if _, ok := packages[dep]; !ok || dep == pkgInfo.Name {
+				continue
+			}
+			relationships = append(relationships, &repository.DistRelationship{
+				From:      pkgInfo.Name,
+				To:        dep,
+				Release:   pkgInfo.Release,
+				Component: pkgInfo.Component,
+				Arch:      pkgInfo.Arch,
+			})
+		}
 	}
 	err := repo.InsertRelationships(cl.Type, relationships)
 	if err != nil {
-		fmt.Printf("Error inserting relationships for %s: %v\n", cl.Type, err)
+		fmt.Printf("Error inserting relationships for %v: %v\n", cl.Type, err)
 	} else {
-		fmt.Printf("Successfully inserted relationships for %s.\n", cl.Type)
+		fmt.Printf("Successfully inserted relationships for %v.\n", cl.Type)
 	}
 }
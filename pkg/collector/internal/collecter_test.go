@@ -0,0 +1,47 @@
+package collector
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/repository"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAggregateSources(t *testing.T) {
+	cl := NewCollector(repository.Debian, repository.DistPackageTablePrefix("debian"))
+	packages := []*PackageInfo{
+		{Name: "libfoo1", Source: "foo", Version: "1.0-1", Description: "foo library"},
+		{Name: "libfoo-dev", Source: "foo", DirectDepends: []string{"libfoo1", "libc6"}},
+		{Name: "libfoo-doc", Source: "foo"},
+		{Name: "foo", Source: "foo", Version: "1.0-1", Description: "foo tools", DirectDepends: []string{"libfoo1"}},
+		{Name: "libc6", Source: "glibc", Version: "2.36-9"},
+		{Name: "bar", DirectDepends: []string{"libfoo1", "libfoo-dev", "missing"}},
+		{Name: "baz", DirectDepends: []string{"libfoo-doc"}},
+	}
+	for _, pkg := range packages {
+		cl.SetPkgInfo(pkg.Name, pkg)
+	}
+
+	cl.AggregateSources()
+	cl.GetDepCount()
+
+	foo := cl.GetPkgInfo("foo")
+	require.NotNil(t, foo)
+	require.Equal(t, []string{"foo", "libfoo-dev", "libfoo-doc", "libfoo1"}, foo.Binaries)
+	require.Equal(t, "foo tools", foo.Description)
+	require.Equal(t, []string{"glibc"}, foo.DirectDepends)
+	// foo itself, bar and baz, bar once although it depends on two binaries
+	require.Equal(t, 3, foo.DependsCount)
+
+	glibc := cl.GetPkgInfo("glibc")
+	require.Equal(t, []string{"libc6"}, glibc.Binaries)
+	require.Equal(t, "2.36-9", glibc.Version)
+
+	bar := cl.GetPkgInfo("bar")
+	deps := append([]string{}, bar.DirectDepends...)
+	sort.Strings(deps)
+	require.Equal(t, []string{"foo", "missing"}, deps)
+
+	require.Nil(t, cl.GetPkgInfo("libfoo1"))
+}
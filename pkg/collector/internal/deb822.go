@@ -0,0 +1,123 @@
+package collector
+
+import (
+	"strings"
+)
+
+// Stanza is a paragraph of a deb822 control file, e.g. a package of a
+// Packages index. Field names are case insensitive, see Get.
+type Stanza map[string]string
+
+// Get returns the value of field, multi-line values keep their line breaks
+// and a line holding a single "." is an empty line.
+func (s Stanza) Get(field string) string {
+	return s[strings.ToLower(field)]
+}
+
+// ParseDeb822 parses the stanzas of a deb822 control file, as described in
+// deb822(5). Stanzas are separated by blank lines, continuation lines start
+// with a space or a tab and lines starting with # are comments.
+func ParseDeb822(data string) []Stanza {
+	ret := make([]Stanza, 0)
+	var current Stanza
+	var field string
+
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.TrimRight(line, "\r")
+		switch {
+		case strings.TrimSpace(line) == "":
+			if current != nil {
+				ret = append(ret, current)
+			}
+			current, field = nil, ""
+		case strings.HasPrefix(line, "#"):
+		case line[0] == ' ' || line[0] == '\t':
+			if current == nil || field == "" {
+				continue
+			}
+			value := strings.TrimSpace(line)
+			if value == "." {
+				value = ""
+			}
+			current[field] += "\n" + value
+		default:
+			name, value, ok := strings.Cut(line, ":")
+			if !ok {
+				continue
+			}
+			if current == nil {
+				current = make(Stanza)
+			}
+			field = strings.ToLower(strings.TrimSpace(name))
+			current[field] = strings.TrimSpace(value)
+		}
+	}
+	if current != nil {
+		ret = append(ret, current)
+	}
+	return ret
+}
+
+// ParseDebRelations parses a relationship field such as Depends into its
+// groups of alternatives, e.g. "a (>= 1) | b, c" is [[a b] [c]]. Version
+// constraints, architecture restrictions, build profiles and architecture
+// qualifiers are dropped.
+func ParseDebRelations(field string) [][]string {
+	ret := make([][]string, 0)
+	for _, group := range strings.Split(field, ",") {
+		alternatives := make([]string, 0)
+		for _, alternative := range strings.Split(group, "|") {
+			if name := debRelationName(alternative); name != "" {
+				alternatives = append(alternatives, name)
+			}
+		}
+		if len(alternatives) > 0 {
+			ret = append(ret, alternatives)
+		}
+	}
+	return ret
+}
+
+func debRelationName(relation string) string {
+	relation = strings.TrimSpace(relation)
+	if idx := strings.IndexAny(relation, " \t\n([<"); idx != -1 {
+		relation = relation[:idx]
+	}
+	if idx := strings.Index(relation, ":"); idx != -1 {
+		relation = relation[:idx]
+	}
+	return relation
+}
+
+// ParseDebPackages parses the stanzas of a Packages index and sets them in
+// cl. The dependencies are the groups of Pre-Depends and Depends, they are
+// resolved against every package of the targets by ResolveDepends.
+func ParseDebPackages(cl CollecterInterface, data string) {
+	for _, stanza := range ParseDeb822(data) {
+		name := stanza.Get("Package")
+		if name == "" {
+			continue
+		}
+
+		pkg := &PackageInfo{
+			Name:        name,
+			Version:     stanza.Get("Version"),
+			Description: stanza.Get("Description"),
+			Homepage:    stanza.Get("Homepage"),
+			Source:      debRelationName(stanza.Get("Source")),
+		}
+		// placeholder of the dh_make templates
+		if strings.HasPrefix(pkg.Homepage, "<") {
+			pkg.Homepage = ""
+		}
+		if pkg.Source == "" {
+			pkg.Source = name
+		}
+		for _, group := range ParseDebRelations(stanza.Get("Provides")) {
+			pkg.Provides = append(pkg.Provides, group...)
+		}
+		pkg.DependGroups = append(ParseDebRelations(stanza.Get("Pre-Depends")), ParseDebRelations(stanza.Get("Depends"))...)
+
+		cl.SetPkgInfo(name, pkg)
+	}
+}
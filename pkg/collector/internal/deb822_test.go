@@ -0,0 +1,39 @@
+package collector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDeb822(t *testing.T) {
+	data := "# comment\n" +
+		"Package: zlib1g\r\n" +
+		"pre-depends: libc6 (>= 2.14)\n" +
+		"Description: compression library - runtime\n" +
+		" zlib is a library implementing the deflate compression method.\n" +
+		" .\n" +
+		" This package includes the shared library.\n" +
+		"\n\n" +
+		"Package: zlib1g-dev\n" +
+		"Depends: zlib1g (= 1:1.2.13.dfsg-1), libc6-dev | libc-dev\n"
+
+	stanzas := ParseDeb822(data)
+	require.Len(t, stanzas, 2)
+	require.Equal(t, "zlib1g", stanzas[0].Get("Package"))
+	require.Equal(t, "libc6 (>= 2.14)", stanzas[0].Get("Pre-Depends"))
+	require.Equal(t, "compression library - runtime\n"+
+		"zlib is a library implementing the deflate compression method.\n"+
+		"\n"+
+		"This package includes the shared library.", stanzas[0].Get("Description"))
+	require.Equal(t, "zlib1g-dev", stanzas[1].Get("package"))
+	require.Equal(t, "", stanzas[1].Get("Description"))
+}
+
+func TestParseDebRelations(t *testing.T) {
+	require.Equal(t, [][]string{{"a", "b"}, {"c"}}, ParseDebRelations("a (>= 1) | b, c"))
+	require.Equal(t, [][]string{{"libc6"}, {"python3"}, {"gcc"}},
+		ParseDebRelations("libc6:any (>= 2.34), python3:native, gcc [amd64 arm64] <!nocheck>"))
+	require.Equal(t, [][]string{{"a"}, {"b"}}, ParseDebRelations("a,\n b,"))
+	require.Empty(t, ParseDebRelations(""))
+}
@@ -1,10 +1,12 @@
 package collector
 
 import (
+	"fmt"
 	"log"
 
 	"github.com/HUSTSecLab/OpenSift/pkg/storage"
 	"github.com/HUSTSecLab/OpenSift/pkg/storage/repository"
+	"github.com/samber/lo"
 )
 
 type PackageInfoInterface interface {
@@ -16,7 +18,6 @@ type PackageInfoInterface interface {
 
 type PackageInfo struct {
 	DirectDepends          []string `json:"Depends"`
-	IndirectDepends        []string
 	DependsCount           int
 	Description            string
 	Homepage               string `json:"URL"`
@@ -26,68 +27,122 @@ type PackageInfo struct {
 	Impact                 float64
 	Gitlink                string
 	Type                   repository.DistType
+	Release                string
+	Component              string
+	Arch                   string
 	DistPackageTablePrefix repository.DistPackageTablePrefix
+	// Source is the source package the package is built from
+	Source string
+	// Provides are the virtual packages provided by the package
+	Provides []string
+	// DependGroups are the dependencies as groups of alternatives, see
+	// ResolveDepends
+	DependGroups [][]string
+	// Binaries are the binary packages built from a source package, see
+	// AggregateSources
+	Binaries []string
 }
 
 type PackageURL []string
 
 var (
-	FedoraURL = PackageURL{
-		"https://mirrors.aliyun.com/fedora/releases/41/Everything/source/tree/repodata/df7750a80c5a4e4ff04ff5a1a499d32b6379dd50680b29140638e6edb1d71d68-primary.xml.gz",
-	}
-	DebianURL = PackageURL{
-		"https://mirrors.hust.edu.cn/debian/dists/stable/main/binary-amd64/Packages.gz",
-	}
-	CentOSURL = PackageURL{
-		"https://mirrors.aliyun.com/centos/7/os/x86_64/repodata/2b479c0f3efa73f75b7fb76c82687744275fff78e4a138b5b3efba95f91e099e-primary.xml.gz",
-	}
-	GentooURL = PackageURL{
-		"https://github.com/gentoo/gentoo.git",
-	}
-	HomebrewURL = PackageURL{
-		"https://github.com/Homebrew/homebrew-core.git",
-	}
-	UbuntuURL = PackageURL{
-		"https://mirrors.hust.edu.cn/ubuntu/dists/jammy/main/binary-amd64/Packages.gz",
-		"https://mirrors.hust.edu.cn/ubuntu/dists/jammy/universe/binary-amd64/Packages.gz",
-		"https://mirrors.hust.edu.cn/ubuntu/dists/jammy/multiverse/binary-amd64/Packages.gz",
-		"https://mirrors.hust.edu.cn/ubuntu/dists/jammy/restricted/binary-amd64/Packages.gz",
-	}
-	AlpineURL = PackageURL{
-		"https://mirrors.aliyun.com/alpine/v3.21/main/x86_64/APKINDEX.tar.gz",
-	}
-	ArchlinuxURL = PackageURL{
-		"https://mirrors.hust.edu.cn/archlinux/community/os/x86_64/community.files.tar.gz",
-		"https://mirrors.hust.edu.cn/archlinux/community-staging/os/x86_64/community-staging.files.tar.gz",
-		"https://mirrors.hust.edu.cn/archlinux/community-testing/os/x86_64/community-testing.files.tar.gz",
-		"https://mirrors.hust.edu.cn/archlinux/core/os/x86_64/core.files.tar.gz",
-		"https://mirrors.hust.edu.cn/archlinux/core-staging/os/x86_64/core-staging.files.tar.gz",
-		"https://mirrors.hust.edu.cn/archlinux/core-testing/os/x86_64/core-testing.files.tar.gz",
-		"https://mirrors.hust.edu.cn/archlinux/extra/os/x86_64/extra.files.tar.gz",
-		"https://mirrors.hust.edu.cn/archlinux/extra-staging/os/x86_64/extra-staging.files.tar.gz",
-		"https://mirrors.hust.edu.cn/archlinux/extra-testing/os/x86_64/extra-testing.files.tar.gz",
-		"https://mirrors.hust.edu.cn/archlinux/gnome-unstable/os/x86_64/gnome-unstable.files.tar.gz",
-		"https://mirrors.hust.edu.cn/archlinux/kde-unstable/os/x86_64/kde-unstable.files.tar.gz",
-		"https://mirrors.hust.edu.cn/archlinux/multilib/os/x86_64/multilib.files.tar.gz",
-		"https://mirrors.hust.edu.cn/archlinux/multilib-staging/os/x86_64/multilib-staging.files.tar.gz",
-		"https://mirrors.hust.edu.cn/archlinux/multilib-testing/os/x86_64/multilib-testing.files.tar.gz",
-		"https://mirrors.hust.edu.cn/archlinux/staging/os/x86_64/staging.files.tar.gz",
-		"https://mirrors.hust.edu.cn/archlinux/testing/os/x86_64/testing.files.tar.gz",
-	}
-	AurURL = PackageURL{
-		"https://aur.archlinux.org/packages-meta-ext-v1.json.gz",
-	}
-	DeepinURL = PackageURL{
-		"https://mirrors.hust.edu.cn/deepin/beige/dists/beige/main/binary-amd64/Packages.gz",
-	}
-	OpenEulerURL = PackageURL{
-		"https://mirrors.hust.edu.cn/openeuler/openEuler-25.03/source/repodata/d2c8439b5d4ef77caf0aba57453b255c955625b95b5231266c0f668223524800-primary.xml.zst",
-	}
-	OpenKylinURL = PackageURL{
-		"https://mirrors.hust.edu.cn/openkylin/dists/huanghe/main/binary-amd64/Packages.gz",
+	Fedora = &Distribution{
+		Mirror:  "https://mirrors.aliyun.com/fedora",
+		Targets: []Target{{Release: "41", Component: "Everything", Arch: "source"}},
+		URL: func(mirror string, t Target) string {
+			if t.Arch == "source" {
+				return fmt.Sprintf("%s/releases/%s/%s/source/tree/repodata/repomd.xml", mirror, t.Release, t.Component)
+			}
+			return fmt.Sprintf("%s/releases/%s/%s/%s/os/repodata/repomd.xml", mirror, t.Release, t.Component, t.Arch)
+		},
+	}
+	Debian = &Distribution{
+		Mirror:  "https://mirrors.hust.edu.cn/debian",
+		Targets: []Target{{Release: "stable", Component: "main", Arch: "amd64"}},
+		URL:     debURL,
+	}
+	CentOS = &Distribution{
+		Mirror:  "https://mirrors.aliyun.com/centos",
+		Targets: []Target{{Release: "7", Component: "os", Arch: "x86_64"}},
+		URL: func(mirror string, t Target) string {
+			return fmt.Sprintf("%s/%s/%s/%s/repodata/repomd.xml", mirror, t.Release, t.Component, t.Arch)
+		},
+	}
+	Ubuntu = &Distribution{
+		Mirror: "https://mirrors.hust.edu.cn/ubuntu",
+		Targets: []Target{
+			{Release: "jammy", Component: "main", Arch: "amd64"},
+			{Release: "jammy", Component: "universe", Arch: "amd64"},
+			{Release: "jammy", Component: "multiverse", Arch: "amd64"},
+			{Release: "jammy", Component: "restricted", Arch: "amd64"},
+		},
+		URL: debURL,
+	}
+	Alpine = &Distribution{
+		Mirror:  "https://mirrors.aliyun.com/alpine",
+		Targets: []Target{{Release: "v3.21", Component: "main", Arch: "x86_64"}},
+		URL:     apkURL,
+	}
+	Archlinux = &Distribution{
+		Mirror: "https://mirrors.hust.edu.cn/archlinux",
+		Targets: []Target{
+			{Component: "community", Arch: "x86_64"},
+			{Component: "community-staging", Arch: "x86_64"},
+			{Component: "community-testing", Arch: "x86_64"},
+			{Component: "core", Arch: "x86_64"},
+			{Component: "core-staging", Arch: "x86_64"},
+			{Component: "core-testing", Arch: "x86_64"},
+			{Component: "extra", Arch: "x86_64"},
+			{Component: "extra-staging", Arch: "x86_64"},
+			{Component: "extra-testing", Arch: "x86_64"},
+			{Component: "gnome-unstable", Arch: "x86_64"},
+			{Component: "kde-unstable", Arch: "x86_64"},
+			{Component: "multilib", Arch: "x86_64"},
+			{Component: "multilib-staging", Arch: "x86_64"},
+			{Component: "multilib-testing", Arch: "x86_64"},
+			{Component: "staging", Arch: "x86_64"},
+			{Component: "testing", Arch: "x86_64"},
+		},
+		URL: pacmanURL,
+	}
+	Aur = &Distribution{
+		Mirror:  "https://aur.archlinux.org",
+		Targets: []Target{{}},
+		URL: func(mirror string, t Target) string {
+			return mirror + "/packages-meta-ext-v1.json.gz"
+		},
+	}
+	Deepin = &Distribution{
+		Mirror:  "https://mirrors.hust.edu.cn/deepin/beige",
+		Targets: []Target{{Release: "beige", Component: "main", Arch: "amd64"}},
+		URL:     debURL,
+	}
+	OpenEuler = &Distribution{
+		Mirror:  "https://mirrors.hust.edu.cn/openeuler",
+		Targets: []Target{{Release: "25.03", Arch: "source"}},
+		URL: func(mirror string, t Target) string {
+			if t.Arch == "source" {
+				return fmt.Sprintf("%s/openEuler-%s/source/repodata/repomd.xml", mirror, t.Release)
+			}
+			return fmt.Sprintf("%s/openEuler-%s/%s/%s/repodata/repomd.xml", mirror, t.Release, t.Component, t.Arch)
+		},
+	}
+	OpenKylin = &Distribution{
+		Mirror:  "https://mirrors.hust.edu.cn/openkylin",
+		Targets: []Target{{Release: "huanghe", Component: "main", Arch: "amd64"}},
+		URL:     debURL,
 	}
 )
 
+// SourceName returns the source package of the package, the package
+// itself if unknown.
+func (pkg *PackageInfo) SourceName() string {
+	if pkg.Source != "" {
+		return pkg.Source
+	}
+	return pkg.Name
+}
+
 func NewPackageInfo() PackageInfoInterface {
 	return &PackageInfo{}
 }
@@ -98,6 +153,10 @@ func (pkg *PackageInfo) ParseDistPackage() *repository.DistPackage {
 		HomePage:     &pkg.Homepage,
 		Version:      &pkg.Version,
 		DependsCount: &pkg.DependsCount,
+		Release:      &pkg.Release,
+		Component:    &pkg.Component,
+		Arch:         &pkg.Arch,
+		Source:       lo.ToPtr(pkg.SourceName()),
 	}
 }
 
@@ -108,19 +167,26 @@ func (pkg *PackageInfo) ParseDistLinkInfo() *repository.DistDependency {
 		Type:      &pkg.Type,
 		DepCount:  &pkg.DependsCount,
 		PageRank:  &pkg.PageRank,
+		Release:   &pkg.Release,
+		Component: &pkg.Component,
+		Arch:      &pkg.Arch,
 	}
 }
 
+// GetGitlinkByPkg sets the git link of the package from the database. A
+// source package is linked through itself or else its binary packages.
 func (pkg *PackageInfo) GetGitlinkByPkg(ac storage.AppDatabaseContext) {
 	repo := repository.NewDistPackageRepository(ac, pkg.DistPackageTablePrefix)
-	pkgInfo, err := repo.GetByName(pkg.Name)
-	if err != nil {
-		log.Println("Error getting package info from database:", err)
-	}
-	if pkgInfo.GitLink != nil {
-		pkg.Gitlink = *pkgInfo.GitLink
-	} else {
-		log.Println("Error getting package info from database:", err)
+	for _, name := range lo.Uniq(append([]string{pkg.Name}, pkg.Binaries...)) {
+		pkgInfo, err := repo.GetByName(name)
+		if err != nil {
+			log.Println("Error getting package info from database:", err)
+			continue
+		}
+		if pkgInfo != nil && pkgInfo.GitLink != nil {
+			pkg.Gitlink = *pkgInfo.GitLink
+			return
+		}
 	}
 }
 
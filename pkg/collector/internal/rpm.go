@@ -0,0 +1,167 @@
+package collector
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// ParseRpmPrimary parses the packages of the primary.xml index of a rpm
+// repository and sets them in cl. A package already set is kept.
+func ParseRpmPrimary(cl CollecterInterface, data string) error {
+	data = strings.Replace(data, "\x00", "", -1)
+	decoder := xml.NewDecoder(strings.NewReader(data))
+	decoder.CharsetReader = func(charset string, input io.Reader) (io.Reader, error) {
+		if charset == "utf-8" {
+			return input, nil
+		}
+		return nil, fmt.Errorf("unsupported charset: %s", charset)
+	}
+	for {
+		tok, err := decoder.Token()
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			return err
+		}
+
+		switch se := tok.(type) {
+		case xml.StartElement:
+			if se.Name.Local == "package" {
+				var pkgData struct {
+					Type string `xml:"type,attr"`
+					XML  string `xml:",innerxml"`
+				}
+				err := decoder.DecodeElement(&pkgData, &se)
+				if err != nil {
+					return err
+				}
+
+				if pkgData.Type == "rpm" {
+					pkgInfo, err := parseRpmPackageXML(pkgData.XML)
+					if err != nil {
+						return err
+					}
+
+					if exists := cl.GetPkgInfo(pkgInfo.Name); exists == nil {
+						cl.SetPkgInfo(pkgInfo.Name, &pkgInfo)
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func parseRpmPackageXML(data string) (PackageInfo, error) {
+	// invalid utf-8 is dropped, the decoder rejects it
+	data = strings.Map(func(r rune) rune {
+		if r == '\x00' || r == utf8.RuneError {
+			return -1
+		}
+		return r
+	}, data)
+	decoder := xml.NewDecoder(strings.NewReader(data))
+	decoder.CharsetReader = func(charset string, input io.Reader) (io.Reader, error) {
+		if charset == "utf-8" {
+			return input, nil
+		}
+		return nil, fmt.Errorf("unsupported charset: %s", charset)
+	}
+	var pkgInfo PackageInfo
+	// the dependency list of the entries being decoded, e.g. requires
+	var section string
+
+	for {
+		tok, err := decoder.Token()
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			return PackageInfo{}, err
+		}
+
+		switch se := tok.(type) {
+		case xml.EndElement:
+			if se.Name.Local == section {
+				section = ""
+			}
+		case xml.StartElement:
+			switch se.Name.Local {
+			case "name":
+				var name string
+				if err := decoder.DecodeElement(&name, &se); err != nil {
+					return PackageInfo{}, err
+				}
+				pkgInfo.Name = name
+			case "description":
+				var description string
+				if err := decoder.DecodeElement(&description, &se); err != nil {
+					return PackageInfo{}, err
+				}
+				pkgInfo.Description = description
+			case "url":
+				var url string
+				if err := decoder.DecodeElement(&url, &se); err != nil {
+					return PackageInfo{}, err
+				}
+				pkgInfo.Homepage = url
+			case "version":
+				var version struct {
+					Epoch string `xml:"epoch,attr"`
+					Ver   string `xml:"ver,attr"`
+					Rel   string `xml:"rel,attr"`
+				}
+				if err := decoder.DecodeElement(&version, &se); err != nil {
+					return PackageInfo{}, err
+				}
+				pkgInfo.Version = fmt.Sprintf("%s:%s-%s", version.Epoch, version.Ver, version.Rel)
+			case "sourcerpm":
+				var sourceRpm string
+				if err := decoder.DecodeElement(&sourceRpm, &se); err != nil {
+					return PackageInfo{}, err
+				}
+				pkgInfo.Source = RpmSourceName(sourceRpm)
+			case "provides", "requires", "conflicts", "obsoletes",
+				"recommends", "suggests", "supplements", "enhances":
+				section = se.Name.Local
+			case "entry":
+				var entry struct {
+					Name string `xml:"name,attr"`
+				}
+				if err := decoder.DecodeElement(&entry, &se); err != nil {
+					return PackageInfo{}, err
+				}
+				switch section {
+				case "provides":
+					pkgInfo.Provides = append(pkgInfo.Provides, entry.Name)
+				case "requires":
+					pkgInfo.DependGroups = append(pkgInfo.DependGroups, []string{entry.Name})
+				}
+			}
+		}
+	}
+
+	if pkgInfo.Source == "" {
+		pkgInfo.Source = pkgInfo.Name
+	}
+	return pkgInfo, nil
+}
+
+// RpmSourceName returns the name of a source rpm from its file name, e.g.
+// zlib from zlib-1.3.1-1.fc41.src.rpm.
+func RpmSourceName(sourceRpm string) string {
+	name := strings.TrimSuffix(sourceRpm, ".src.rpm")
+	name = strings.TrimSuffix(name, ".nosrc.rpm")
+	for i := 0; i < 2; i++ {
+		idx := strings.LastIndex(name, "-")
+		if idx == -1 {
+			return name
+		}
+		name = name[:idx]
+	}
+	return name
+}
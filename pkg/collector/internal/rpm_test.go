@@ -0,0 +1,74 @@
+package collector
+
+import (
+	"testing"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/repository"
+	"github.com/stretchr/testify/require"
+)
+
+const primaryFixture = `<?xml version="1.0" encoding="UTF-8"?>
+<metadata xmlns="http://linux.duke.edu/metadata/common" xmlns:rpm="http://linux.duke.edu/metadata/rpm" packages="2">
+<package type="rpm">
+  <name>zlib-ng-compat</name>
+  <arch>x86_64</arch>
+  <version epoch="0" ver="2.1.7" rel="1.fc41"/>
+  <description>zlib data compression library with the zlib compatible API.
+
+zlib-ng is a zlib replacement with optimizations for “next generation” systems.</description>
+  <url>https://github.com/zlib-ng/zlib-ng</url>
+  <format>
+    <rpm:sourcerpm>zlib-ng-2.1.7-1.fc41.src.rpm</rpm:sourcerpm>
+    <rpm:provides>
+      <rpm:entry name="libz.so.1()(64bit)"/>
+      <rpm:entry name="zlib-ng-compat" flags="EQ" epoch="0" ver="2.1.7" rel="1.fc41"/>
+    </rpm:provides>
+    <rpm:requires>
+      <rpm:entry name="libc.so.6()(64bit)"/>
+    </rpm:requires>
+    <rpm:obsoletes>
+      <rpm:entry name="zlib" flags="LT" epoch="0" ver="1.3"/>
+    </rpm:obsoletes>
+  </format>
+</package>
+<package type="rpm">
+  <name>zlib-ng-compat-devel</name>
+  <arch>x86_64</arch>
+  <version epoch="0" ver="2.1.7" rel="1.fc41"/>
+  <format>
+    <rpm:sourcerpm>zlib-ng-2.1.7-1.fc41.src.rpm</rpm:sourcerpm>
+    <rpm:requires>
+      <rpm:entry name="libz.so.1()(64bit)"/>
+      <rpm:entry name="zlib-ng-compat" flags="EQ" epoch="0" ver="2.1.7" rel="1.fc41"/>
+    </rpm:requires>
+  </format>
+</package>
+</metadata>
+`
+
+func TestParseRpmPrimary(t *testing.T) {
+	cl := NewCollector(repository.Fedora, repository.DistPackageTablePrefix("fedora"))
+	require.NoError(t, ParseRpmPrimary(cl, primaryFixture))
+	cl.ResolveDepends()
+
+	compat := cl.GetPkgInfo("zlib-ng-compat")
+	require.NotNil(t, compat)
+	require.Equal(t, "0:2.1.7-1.fc41", compat.Version)
+	require.Equal(t, "https://github.com/zlib-ng/zlib-ng", compat.Homepage)
+	require.Equal(t, "zlib data compression library with the zlib compatible API.\n\n"+
+		"zlib-ng is a zlib replacement with optimizations for “next generation” systems.", compat.Description)
+	require.Equal(t, "zlib-ng", compat.Source)
+	require.Equal(t, []string{"libz.so.1()(64bit)", "zlib-ng-compat"}, compat.Provides)
+	require.Equal(t, []string{"libc.so.6()(64bit)"}, compat.DirectDepends)
+
+	devel := cl.GetPkgInfo("zlib-ng-compat-devel")
+	require.Equal(t, "zlib-ng", devel.Source)
+	require.Equal(t, []string{"zlib-ng-compat"}, devel.DirectDepends)
+}
+
+func TestRpmSourceName(t *testing.T) {
+	require.Equal(t, "zlib-ng", RpmSourceName("zlib-ng-2.1.7-1.fc41.src.rpm"))
+	require.Equal(t, "python3.13", RpmSourceName("python3.13-3.13.0-1.fc41.src.rpm"))
+	require.Equal(t, "glibc", RpmSourceName("glibc-2.40-3.fc41.nosrc.rpm"))
+	require.Equal(t, "zlib", RpmSourceName("zlib"))
+}
@@ -0,0 +1,271 @@
+package collector
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/samber/lo"
+)
+
+// indexFilePatterns are the names of the package index files picked up when a
+// source is a local directory, e.g. a mirror snapshot.
+var indexFilePatterns = []string{
+	"Packages",
+	"Packages.gz",
+	"Packages.zst",
+	"APKINDEX",
+	"APKINDEX.tar.gz",
+	"*primary.xml",
+	"*primary.xml.gz",
+	"*primary.xml.zst",
+	"*.files.tar.gz",
+	"packages-meta-ext-v1.json",
+	"packages-meta-ext-v1.json.gz",
+}
+
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// IsIndexFile reports whether name is the name of a package index file.
+func IsIndexFile(name string) bool {
+	for _, pattern := range indexFilePatterns {
+		if ok, _ := filepath.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// localPath returns the local path of a source, and false if the source is
+// a remote url.
+func localPath(source string) (string, bool) {
+	u, err := url.Parse(source)
+	if err != nil || u.Scheme == "" || len(u.Scheme) == 1 {
+		// no scheme, or a windows drive letter
+		return source, true
+	}
+	if u.Scheme == "file" {
+		return filepath.FromSlash(u.Path), true
+	}
+	return "", false
+}
+
+// ExpandSources replaces every local directory in sources with the package
+// index files of target found in it, recursively and in lexical order, see
+// selectIndexFiles. Urls and regular files are kept as is.
+func ExpandSources(sources PackageURL, target Target) (PackageURL, error) {
+	ret := make(PackageURL, 0, len(sources))
+	for _, source := range sources {
+		path, ok := localPath(source)
+		if !ok {
+			ret = append(ret, source)
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			ret = append(ret, path)
+			continue
+		}
+
+		files := make([]string, 0)
+		err = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() && IsIndexFile(d.Name()) {
+				files = append(files, p)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		files = selectIndexFiles(files, target)
+		if len(files) == 0 {
+			return nil, fmt.Errorf("no package index file found in %s", path)
+		}
+		ret = append(ret, files...)
+	}
+	return ret, nil
+}
+
+// compressionExts are the extensions of the compressed index files, the
+// uncompressed one first, in the order they are preferred.
+var compressionExts = []string{"", ".zst", ".gz"}
+
+// selectIndexFiles returns the index files of target among files, sorted.
+// The installer packages (udebs) are left out. The release, component and
+// arch of target select the files having them, or binary-<arch>, as a
+// directory, unless no file has, e.g. a snapshot of a single arch. Of the
+// compressions of an index, only one is kept, see compressionExts.
+func selectIndexFiles(files []string, target Target) []string {
+	hasDir := func(file string, name string) bool {
+		dirs := strings.Split(filepath.ToSlash(filepath.Dir(file)), "/")
+		return slices.Contains(dirs, name) || (name == target.Arch && slices.Contains(dirs, "binary-"+name))
+	}
+	files = lo.Filter(files, func(f string, _ int) bool {
+		return !hasDir(f, "debian-installer")
+	})
+	for _, name := range []string{target.Release, target.Component, target.Arch} {
+		if name == "" {
+			continue
+		}
+		if matching := lo.Filter(files, func(f string, _ int) bool { return hasDir(f, name) }); len(matching) > 0 {
+			files = matching
+		}
+	}
+
+	indexes := make(map[string]string)
+	// the rank of an uncompressed file, e.g. primary.xml, is 0
+	rank := func(file string) int {
+		return max(slices.Index(compressionExts, filepath.Ext(file)), 0)
+	}
+	for _, f := range files {
+		index := f
+		if rank(f) > 0 {
+			index = strings.TrimSuffix(f, filepath.Ext(f))
+		}
+		if cur, ok := indexes[index]; !ok || rank(f) < rank(cur) {
+			indexes[index] = f
+		}
+	}
+	ret := lo.Values(indexes)
+	sort.Strings(ret)
+	return ret
+}
+
+// OpenSource opens a http(s) url, a file:// url or a local path.
+func OpenSource(source string) (io.ReadCloser, error) {
+	if path, ok := localPath(source); ok {
+		return os.Open(path)
+	}
+	resp, err := http.Get(source)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("unexpected status %s for %s", resp.Status, source)
+	}
+	return resp.Body, nil
+}
+
+// ReadSource reads the content of a source. Gzip and zstd compressed
+// content is decompressed, and the entries of a tar archive are
+// concatenated. The format is detected from the content, not the name, as
+// mirrors may already serve compressed files with a content encoding.
+func ReadSource(source string) (string, error) {
+	rc, err := OpenSource(source)
+	if err != nil {
+		return "", err
+	}
+	defer rc.Close()
+
+	r, err := decompress(bufio.NewReader(rc))
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", source, err)
+	}
+	defer r.Close()
+
+	br := bufio.NewReader(r)
+	header, _ := br.Peek(262)
+	var body strings.Builder
+	if len(header) == 262 && bytes.HasPrefix(header[257:], []byte("ustar")) {
+		tarReader := tar.NewReader(br)
+		for {
+			_, err := tarReader.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return "", fmt.Errorf("%s: failed to read tar content: %w", source, err)
+			}
+			if _, err := io.Copy(&body, tarReader); err != nil {
+				return "", fmt.Errorf("%s: failed to extract tar content: %w", source, err)
+			}
+		}
+		return body.String(), nil
+	}
+
+	if _, err := io.Copy(&body, br); err != nil {
+		return "", fmt.Errorf("%s: %w", source, err)
+	}
+	return body.String(), nil
+}
+
+// IsRepomd reports whether source is the repomd.xml of a rpm repository.
+func IsRepomd(source string) bool {
+	return path.Base(source) == "repomd.xml"
+}
+
+// ResolvePrimary returns the location of the primary package index listed
+// in the repomd.xml at source. Repositories name the primary index after its
+// checksum, so it cannot be pinned.
+func ResolvePrimary(source string) (string, error) {
+	data, err := ReadSource(source)
+	if err != nil {
+		return "", err
+	}
+	var repomd struct {
+		Data []struct {
+			Type     string `xml:"type,attr"`
+			Location struct {
+				Href string `xml:"href,attr"`
+			} `xml:"location"`
+		} `xml:"data"`
+	}
+	if err := xml.Unmarshal([]byte(data), &repomd); err != nil {
+		return "", fmt.Errorf("%s: %w", source, err)
+	}
+	for _, d := range repomd.Data {
+		if d.Type != "primary" {
+			continue
+		}
+		// href is relative to the repository root, the parent of repodata
+		if p, ok := localPath(source); ok {
+			return filepath.Join(filepath.Dir(filepath.Dir(p)), filepath.FromSlash(d.Location.Href)), nil
+		}
+		u, err := url.Parse(source)
+		if err != nil {
+			return "", err
+		}
+		return u.ResolveReference(&url.URL{Path: "../" + d.Location.Href}).String(), nil
+	}
+	return "", fmt.Errorf("%s: no primary package index", source)
+}
+
+func decompress(r *bufio.Reader) (io.ReadCloser, error) {
+	magic, _ := r.Peek(4)
+	switch {
+	case bytes.HasPrefix(magic, gzipMagic):
+		return gzip.NewReader(r)
+	case bytes.HasPrefix(magic, zstdMagic):
+		zr, err := zstd.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		return zr.IOReadCloser(), nil
+	default:
+		return io.NopCloser(r), nil
+	}
+}
@@ -0,0 +1,131 @@
+package collector
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/repository"
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/require"
+)
+
+const packagesFixture = "Package: zlib1g\nVersion: 1:1.2.13.dfsg-1\n"
+
+func writeFixture(t *testing.T, path string, data []byte) string {
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, data, 0o644))
+	return path
+}
+
+func gzipData(t *testing.T, data []byte) []byte {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	_, err := w.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func TestReadSource(t *testing.T) {
+	dir := t.TempDir()
+
+	zstdEncoder, err := zstd.NewWriter(nil)
+	require.NoError(t, err)
+	zstdData := zstdEncoder.EncodeAll([]byte(packagesFixture), nil)
+
+	var tarData bytes.Buffer
+	tw := tar.NewWriter(&tarData)
+	for _, name := range []string{"APKINDEX", "DESCRIPTION"} {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(packagesFixture))}))
+		_, err := tw.Write([]byte(packagesFixture))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+
+	tests := []struct {
+		name string
+		path string
+		want string
+	}{
+		{
+			name: "plain",
+			path: writeFixture(t, filepath.Join(dir, "Packages"), []byte(packagesFixture)),
+			want: packagesFixture,
+		},
+		{
+			name: "gzip",
+			path: writeFixture(t, filepath.Join(dir, "Packages.gz"), gzipData(t, []byte(packagesFixture))),
+			want: packagesFixture,
+		},
+		{
+			name: "zstd",
+			path: writeFixture(t, filepath.Join(dir, "primary.xml.zst"), zstdData),
+			want: packagesFixture,
+		},
+		{
+			name: "tar.gz",
+			path: writeFixture(t, filepath.Join(dir, "APKINDEX.tar.gz"), gzipData(t, tarData.Bytes())),
+			want: packagesFixture + packagesFixture,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ReadSource(tt.path)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+
+			got, err = ReadSource("file://" + filepath.ToSlash(tt.path))
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+
+	_, err = ReadSource(filepath.Join(dir, "missing"))
+	require.Error(t, err)
+}
+
+func TestExpandSources(t *testing.T) {
+	dir := t.TempDir()
+	target := Target{Release: "stable", Component: "main", Arch: "amd64"}
+	writeFixture(t, filepath.Join(dir, "dists", "stable", "main", "binary-amd64", "Packages.gz"), nil)
+	main := writeFixture(t, filepath.Join(dir, "dists", "stable", "main", "binary-amd64", "Packages.zst"), nil)
+	writeFixture(t, filepath.Join(dir, "dists", "stable", "main", "binary-arm64", "Packages.gz"), nil)
+	writeFixture(t, filepath.Join(dir, "dists", "stable", "main", "debian-installer", "binary-amd64", "Packages.gz"), nil)
+	writeFixture(t, filepath.Join(dir, "dists", "stable", "contrib", "binary-amd64", "Packages.gz"), nil)
+	writeFixture(t, filepath.Join(dir, "dists", "testing", "main", "binary-amd64", "Packages.gz"), nil)
+	writeFixture(t, filepath.Join(dir, "dists", "stable", "Release"), nil)
+
+	sources, err := ExpandSources(PackageURL{"file://" + filepath.ToSlash(dir), "https://example.com/Packages.gz"}, target)
+	require.NoError(t, err)
+	require.Equal(t, PackageURL{main, "https://example.com/Packages.gz"}, sources)
+
+	// a snapshot of a single target, whose path does not name it
+	single := t.TempDir()
+	packages := writeFixture(t, filepath.Join(single, "Packages"), nil)
+	writeFixture(t, filepath.Join(single, "Packages.gz"), nil)
+	sources, err = ExpandSources(PackageURL{single}, target)
+	require.NoError(t, err)
+	require.Equal(t, PackageURL{packages}, sources)
+
+	_, err = ExpandSources(PackageURL{filepath.Join(dir, "dists", "stable", "main", "binary-amd64", "missing")}, target)
+	require.Error(t, err)
+
+	_, err = ExpandSources(PackageURL{t.TempDir()}, target)
+	require.Error(t, err)
+}
+
+func TestGetPackageInfoSources(t *testing.T) {
+	dir := t.TempDir()
+	writeFixture(t, filepath.Join(dir, "main", "Packages"), []byte(packagesFixture))
+
+	cl := NewCollector(repository.Debian, repository.DistPackageTablePrefix("debian"))
+	require.Equal(t, Debian.URLs(Debian.Targets[0]), cl.GetSources(Debian.URLs(Debian.Targets[0])))
+
+	cl.SetSources(PackageURL{dir})
+	require.Equal(t, PackageURL{dir}, cl.GetSources(Debian.URLs(Debian.Targets[0])))
+	require.Equal(t, packagesFixture, cl.GetPackageInfo(cl.GetSources(Debian.URLs(Debian.Targets[0]))))
+}
@@ -0,0 +1,126 @@
+package collector
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+	"strings"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/graph"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage"
+)
+
+// Target is a release, component and architecture of a distribution, e.g.
+// Debian stable/main/amd64. Fields not applicable to a distribution are
+// empty, e.g. the release of Arch Linux.
+type Target struct {
+	Release   string
+	Component string
+	Arch      string
+}
+
+func (t Target) String() string {
+	return t.Release + "/" + t.Component + "/" + t.Arch
+}
+
+// ParseTarget parses a target in the release/component/arch format.
+func ParseTarget(s string) (Target, error) {
+	parts := strings.Split(s, "/")
+	if len(parts) != 3 {
+		return Target{}, fmt.Errorf("invalid target %q, expected release/component/arch", s)
+	}
+	return Target{Release: parts[0], Component: parts[1], Arch: parts[2]}, nil
+}
+
+// GroupTargets groups targets sharing a release and an architecture, in
+// the order of their first appearance. Packages of a component depend on
+// packages of other components, so each group is one dependency graph.
+func GroupTargets(targets []Target) [][]Target {
+	ret := make([][]Target, 0)
+	index := make(map[string]int)
+	for _, t := range targets {
+		key := t.Release + "/" + t.Arch
+		i, ok := index[key]
+		if !ok {
+			i = len(ret)
+			index[key] = i
+			ret = append(ret, nil)
+		}
+		ret[i] = append(ret[i], t)
+	}
+	return ret
+}
+
+// Distribution is the mirror and the default targets of a distribution.
+type Distribution struct {
+	Mirror  string
+	Targets []Target
+	// URL returns the url of the package index of target in mirror
+	URL func(mirror string, target Target) string
+}
+
+// URLs returns the package index urls of target.
+func (d *Distribution) URLs(target Target) PackageURL {
+	return PackageURL{d.URL(d.Mirror, target)}
+}
+
+// Collect runs the collection of every group of targets of the collector,
+// see GroupTargets. parse is called with the package index of each target
+// of a group, then the dependency graph of the group is analyzed and stored.
+func Collect(cl CollecterInterface, dist *Distribution, parse func(data string), outputPath string) {
+	adc := storage.GetDefaultAppDatabaseContext()
+	targets := cl.GetTargets(dist.Targets)
+	if len(cl.GetSources(nil)) > 0 && len(targets) > 1 {
+		log.Printf("Error collecting %d targets: sources can only be set for a single target\n", len(targets))
+		return
+	}
+
+	groups := GroupTargets(targets)
+	for _, group := range groups {
+		cl.Reset()
+		for _, target := range group {
+			cl.SetTarget(target)
+			parse(cl.GetPackageInfo(cl.GetSources(dist.URLs(target))))
+		}
+		cl.ResolveDepends()
+		cl.AggregateSources()
+		cl.PageRank(graph.DefaultPageRankOptions())
+		cl.GetDepCount()
+		cl.UpdateDistRepoCount(adc)
+		cl.CalculateDistImpact()
+		cl.UpdateOrInsertDatabase(adc)
+		cl.UpdateRelationships(adc)
+		cl.UpdateOrInsertDistDependencyDatabase(adc)
+		if outputPath != "" {
+			path := outputPath
+			if len(groups) > 1 {
+				path = targetOutputPath(outputPath, group[0])
+			}
+			err := cl.GenerateDependencyGraph(path)
+			if err != nil {
+				log.Printf("Error generating dependency graph: %v\n", err)
+				return
+			}
+		}
+	}
+}
+
+// targetOutputPath inserts the release and arch of target before the
+// extension of path, e.g. deps.dot becomes deps.stable-amd64.dot.
+func targetOutputPath(path string, target Target) string {
+	ext := filepath.Ext(path)
+	suffix := strings.Trim(target.Release+"-"+target.Arch, "-")
+	return strings.TrimSuffix(path, ext) + "." + suffix + ext
+}
+
+func debURL(mirror string, t Target) string {
+	return fmt.Sprintf("%s/dists/%s/%s/binary-%s/Packages.gz", mirror, t.Release, t.Component, t.Arch)
+}
+
+func apkURL(mirror string, t Target) string {
+	return fmt.Sprintf("%s/%s/%s/%s/APKINDEX.tar.gz", mirror, t.Release, t.Component, t.Arch)
+}
+
+func pacmanURL(mirror string, t Target) string {
+	return fmt.Sprintf("%s/%s/os/%s/%s.files.tar.gz", mirror, t.Component, t.Arch, t.Component)
+}
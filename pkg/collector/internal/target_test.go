@@ -0,0 +1,96 @@
+package collector
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/repository"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTarget(t *testing.T) {
+	target, err := ParseTarget("testing/main/arm64")
+	require.NoError(t, err)
+	require.Equal(t, Target{Release: "testing", Component: "main", Arch: "arm64"}, target)
+	require.Equal(t, "testing/main/arm64", target.String())
+
+	target, err = ParseTarget("/core/x86_64")
+	require.NoError(t, err)
+	require.Equal(t, Target{Component: "core", Arch: "x86_64"}, target)
+
+	_, err = ParseTarget("stable/main")
+	require.Error(t, err)
+}
+
+func TestGroupTargets(t *testing.T) {
+	targets := []Target{
+		{"stable", "main", "amd64"},
+		{"testing", "main", "amd64"},
+		{"stable", "contrib", "amd64"},
+		{"stable", "main", "arm64"},
+	}
+	require.Equal(t, [][]Target{
+		{{"stable", "main", "amd64"}, {"stable", "contrib", "amd64"}},
+		{{"testing", "main", "amd64"}},
+		{{"stable", "main", "arm64"}},
+	}, GroupTargets(targets))
+}
+
+func TestDistributionURLs(t *testing.T) {
+	require.Equal(t, PackageURL{"https://mirrors.hust.edu.cn/debian/dists/testing/main/binary-arm64/Packages.gz"},
+		Debian.URLs(Target{"testing", "main", "arm64"}))
+	require.Equal(t, PackageURL{"https://mirrors.aliyun.com/alpine/v3.21/main/x86_64/APKINDEX.tar.gz"},
+		Alpine.URLs(Alpine.Targets[0]))
+	require.Equal(t, PackageURL{"https://mirrors.hust.edu.cn/archlinux/core/os/x86_64/core.files.tar.gz"},
+		Archlinux.URLs(Target{Component: "core", Arch: "x86_64"}))
+	require.Equal(t, PackageURL{"https://mirrors.aliyun.com/fedora/releases/41/Everything/source/tree/repodata/repomd.xml"},
+		Fedora.URLs(Fedora.Targets[0]))
+	require.Equal(t, PackageURL{"https://mirrors.aliyun.com/fedora/releases/42/Everything/aarch64/os/repodata/repomd.xml"},
+		Fedora.URLs(Target{"42", "Everything", "aarch64"}))
+
+	require.Equal(t, "deps.stable-amd64.dot", targetOutputPath("deps.dot", Target{"stable", "main", "amd64"}))
+	require.Equal(t, "deps.x86_64", targetOutputPath("deps", Target{Component: "core", Arch: "x86_64"}))
+}
+
+func TestResolvePrimary(t *testing.T) {
+	dir := t.TempDir()
+	repomd := writeFixture(t, filepath.Join(dir, "os", "repodata", "repomd.xml"), []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<repomd xmlns="http://linux.duke.edu/metadata/repo">
+  <data type="filelists">
+    <location href="repodata/0123-filelists.xml.gz"/>
+  </data>
+  <data type="primary">
+    <location href="repodata/4567-primary.xml.gz"/>
+  </data>
+</repomd>`))
+
+	require.True(t, IsRepomd(repomd))
+	primary, err := ResolvePrimary(repomd)
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(dir, "os", "repodata", "4567-primary.xml.gz"), primary)
+}
+
+func TestSetTargets(t *testing.T) {
+	cl := NewCollector(repository.Debian, repository.DistPackageTablePrefix("debian"))
+	require.Equal(t, Debian.Targets, cl.GetTargets(Debian.Targets))
+
+	require.NoError(t, cl.SetTargets([]string{"testing/main/arm64"}))
+	require.Equal(t, []Target{{"testing", "main", "arm64"}}, cl.GetTargets(Debian.Targets))
+	require.Error(t, cl.SetTargets([]string{"testing"}))
+
+	cl.SetTarget(Target{"testing", "main", "arm64"})
+	cl.SetPkgInfo("zlib1g", &PackageInfo{Name: "zlib1g"})
+	pkg := cl.GetPkgInfo("zlib1g")
+	require.Equal(t, "testing", pkg.Release)
+	require.Equal(t, "main", pkg.Component)
+	require.Equal(t, "arm64", pkg.Arch)
+
+	cl.Reset()
+	require.Nil(t, cl.GetPkgInfo("zlib1g"))
+}
+
+func TestMergeComponents(t *testing.T) {
+	require.Equal(t, "main", mergeComponents("", "main"))
+	require.Equal(t, "contrib,main", mergeComponents("main", "contrib"))
+	require.Equal(t, "contrib,main,non-free", mergeComponents("contrib,main", "non-free,main"))
+}
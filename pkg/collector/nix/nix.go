@@ -12,6 +12,7 @@ import (
 	"unicode"
 
 	collector "github.com/HUSTSecLab/OpenSift/pkg/collector/internal"
+	"github.com/HUSTSecLab/OpenSift/pkg/graph"
 	"github.com/HUSTSecLab/OpenSift/pkg/storage"
 	"github.com/HUSTSecLab/OpenSift/pkg/storage/repository"
 )
@@ -27,13 +28,12 @@ func (nc *NixCollector) Collect(workerCount int, batchSize int, outputPath strin
 		fmt.Printf("Error retrieving Nix packages: %v\n", err)
 		return
 	}
-	nc.GetDep()
-	nc.PageRank(0.85, 20)
+	nc.PageRank(graph.DefaultPageRankOptions())
 	nc.GetDepCount()
-	nc.UpdateRelationships(adc)
 	nc.UpdateDistRepoCount(adc)
 	nc.CalculateDistImpact()
 	nc.UpdateOrInsertDatabase(adc)
+	nc.UpdateRelationships(adc)
 	nc.UpdateOrInsertDistDependencyDatabase(adc)
 	if outputPath != "" {
 		err = nc.GenerateDependencyGraph(outputPath)
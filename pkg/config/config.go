@@ -6,6 +6,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/collector/hostlimit"
 	"github.com/HUSTSecLab/OpenSift/pkg/logger"
 	"github.com/HUSTSecLab/OpenSift/pkg/storage"
 	"github.com/spf13/viper"
@@ -107,6 +108,23 @@ func GetGitStoragePath() string {
 	return viper.GetString("git.storage")
 }
 
+// GetGitHostLimits returns the limits of the clones and fetches per host,
+// set in the config file by a list under git.hosts, e.g.
+//
+//	git:
+//	  hosts:
+//	    - host: github.com
+//	      concurrency: 32
+//	      rate: 4
+//	      burst: 8
+//	      backoff: 1m
+//	      max-backoff: 1h
+func GetGitHostLimits() ([]hostlimit.Limit, error) {
+	var limits []hostlimit.Limit
+	err := viper.UnmarshalKey("git.hosts", &limits)
+	return limits, err
+}
+
 func GetRpcCollectorAddress() string {
 	return viper.GetString("rpc.collector")
 }
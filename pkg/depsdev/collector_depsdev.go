@@ -14,6 +14,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/HUSTSecLab/OpenSift/pkg/graph"
 	"github.com/HUSTSecLab/OpenSift/pkg/storage"
 	"github.com/HUSTSecLab/OpenSift/pkg/storage/repository"
 	"github.com/go-redis/redis/v8"
@@ -31,6 +32,13 @@ var PackageCounts = map[repository.LangEcosystemType]int{
 	repository.NuGet:  430e3,
 	repository.Cargo:  168e3,
 	repository.Others: 1,
+	// registry sizes of the ecosystems counted by PackageDependents
+	repository.Packagist: 430e3,
+	repository.RubyGems:  185e3,
+	repository.Hex:       17e3,
+	repository.Conan:     1.7e3,
+	repository.Conda:     27e3,
+	repository.SwiftPM:   9e3,
 }
 
 type DependentInfo struct {
@@ -452,49 +460,19 @@ func fetchDep(depMap *sync.Map, threadnum int) *sync.Map {
 	return depMapNew
 }
 
-func calculatePageRank(pkgInfoMap map[string][]Version, iterations int, dampingFactor float64) map[string]float64 {
-	pageRank := &sync.Map{}
-	numPackages := len(pkgInfoMap)
-
-	for pkgName := range pkgInfoMap {
-		pageRank.Store(pkgName, 1.0/float64(numPackages))
-	}
-
-	for i := 0; i < iterations; i++ {
-		newPageRank := &sync.Map{}
-
-		for pkgName := range pkgInfoMap {
-			newPageRank.Store(pkgName, (1-dampingFactor)/float64(numPackages))
-		}
-
-		var wg sync.WaitGroup
-		for pkgName, deps := range pkgInfoMap {
-			wg.Add(1)
-			go func(pkgName string, deps []Version) {
-				defer wg.Done()
-				depNum := len(deps)
-				for _, depName := range deps {
-					if _, exists := pkgInfoMap[depName.Name]; exists {
-						if val, ok := pageRank.Load(pkgName); ok {
-							if newVal, ok := newPageRank.Load(depName.Name); ok {
-								newPageRank.Store(depName.Name, newVal.(float64)+dampingFactor*(val.(float64)/float64(depNum)))
-							}
-						}
-					}
-				}
-			}(pkgName, deps)
-		}
-		wg.Wait()
-		pageRank = newPageRank
-	}
-
-	result := make(map[string]float64)
-	pageRank.Range(func(key, value interface{}) bool {
-		result[key.(string)] = value.(float64)
-		return true
+func calculatePageRank(pkgInfoMap map[string][]Version, maxIterations int, dampingFactor float64) map[string]float64 {
+	g := graph.Build(pkgInfoMap, func(_ string, deps []Version) []string {
+		return lo.Map(deps, func(dep Version, _ int) string { return dep.Name })
 	})
-
-	return result
+	opts := graph.DefaultPageRankOptions()
+	opts.Damping = dampingFactor
+	opts.MaxIterations = maxIterations
+	result, err := g.PageRank(opts)
+	if err != nil {
+		fmt.Println("Error calculating PageRank:", err)
+		return make(map[string]float64)
+	}
+	return result.Map(g)
 }
 
 func getAndProcessDependencies(system, name, version string) Dependencies {
@@ -0,0 +1,143 @@
+package depsdev
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
+	giturl "github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/url"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/repository"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/sqlutil"
+	"github.com/lib/pq"
+)
+
+var pypiSeparatorRegexp = regexp.MustCompile(`[-_.]+`)
+
+// NormalizePackageName returns the name under which the registry of eco
+// lists the package name: PyPI names are case insensitive and do not tell
+// -, _ and . apart, crates.io names do not tell - and _ apart, and Packagist
+// and NuGet names are case insensitive.
+func NormalizePackageName(eco string, name string) string {
+	switch eco {
+	case parser.PYPI:
+		return pypiSeparatorRegexp.ReplaceAllString(strings.ToLower(name), "-")
+	case parser.CARGO:
+		return strings.ReplaceAll(strings.ToLower(name), "_", "-")
+	case parser.COMPOSER, parser.NUGET:
+		return strings.ToLower(name)
+	}
+	return name
+}
+
+// PackageLinks indexes the repositories declaring every package in their
+// manifests, and cross-validates the index with the repositories declared by
+// the registries. resolve returns the link a link is scored as, e.g. the
+// canonical repository of a mirror. Packages declared by no manifest are left
+// out.
+//
+// The registry disagrees if the repository it declares is not one declaring
+// the package, while another one does, or while it declares other packages
+// of the ecosystem: the registry field is stale, or the package claims the
+// repository of another one, as typo-squats do.
+func PackageLinks(packages []*repository.GitPackage, registry []*repository.RegistryPackage, resolve func(string) string) []*repository.PackageLink {
+	manifestLinks := make(map[packageKey][]string)
+	declaring := make(map[string]map[string]bool)
+	for _, p := range packages {
+		link := resolve(*p.GitLink)
+		k := packageKey{*p.Ecosystem, NormalizePackageName(*p.Ecosystem, *p.Name)}
+		if !slices.Contains(manifestLinks[k], link) {
+			manifestLinks[k] = append(manifestLinks[k], link)
+		}
+		if declaring[*p.Ecosystem] == nil {
+			declaring[*p.Ecosystem] = make(map[string]bool)
+		}
+		declaring[*p.Ecosystem][link] = true
+	}
+	registryLinks := make(map[packageKey]string)
+	for _, r := range registry {
+		registryLinks[packageKey{*r.Ecosystem, NormalizePackageName(*r.Ecosystem, *r.Name)}] = *r.GitLink
+	}
+
+	ret := make([]*repository.PackageLink, 0, len(manifestLinks))
+	for k, links := range manifestLinks {
+		slices.Sort(links)
+		link := &repository.PackageLink{
+			Ecosystem:     sqlutil.ToData(k.eco),
+			Name:          sqlutil.ToData(k.name),
+			ManifestLinks: sqlutil.ToData(pq.StringArray(links)),
+			RegistryLink:  sqlutil.ToData[*string](nil),
+		}
+		status := repository.PackageLinkUnregistered
+		if registryLink, ok := registryLinks[k]; ok {
+			link.RegistryLink = sqlutil.ToNullable(registryLink)
+			// a registry link which is not a git link, e.g. a homepage,
+			// declares no repository
+			if canonical, err := giturl.Canonicalize(registryLink); err == nil {
+				canonical = resolve(canonical)
+				link.RegistryLink = sqlutil.ToNullable(canonical)
+				if slices.Contains(links, canonical) {
+					status = repository.PackageLinkAgree
+				} else {
+					status = repository.PackageLinkDisagree
+				}
+			}
+		}
+		link.Status = sqlutil.ToData(status)
+		ret = append(ret, link)
+	}
+
+	// the packages whose registry declares a repository declaring other
+	// packages only
+	for k, registryLink := range registryLinks {
+		if _, ok := manifestLinks[k]; ok {
+			continue
+		}
+		canonical, err := giturl.Canonicalize(registryLink)
+		if err != nil || !declaring[k.eco][resolve(canonical)] {
+			continue
+		}
+		ret = append(ret, &repository.PackageLink{
+			Ecosystem:     sqlutil.ToData(k.eco),
+			Name:          sqlutil.ToData(k.name),
+			ManifestLinks: sqlutil.ToData(pq.StringArray{}),
+			RegistryLink:  sqlutil.ToNullable(resolve(canonical)),
+			Status:        sqlutil.ToData(repository.PackageLinkDisagree),
+		})
+	}
+	return ret
+}
+
+// UpdatePackageLinks builds the index of PackageLinks from the packages
+// stored by the collector and the repositories stored by the link
+// enumerators, and returns the number of packages of every status. The index
+// replaces the previous one, except for the packages labeled.
+func UpdatePackageLinks(ac storage.AppDatabaseContext, resolve func(string) string, dryRun bool) (map[repository.PackageLinkStatus]int, error) {
+	packagesIter, err := repository.NewGitPackageRepository(ac).QueryPackages()
+	if err != nil {
+		return nil, err
+	}
+	packages := make([]*repository.GitPackage, 0)
+	for p := range packagesIter {
+		packages = append(packages, p)
+	}
+	registryIter, err := repository.NewRegistryPackageRepository(ac).Query()
+	if err != nil {
+		return nil, err
+	}
+	registry := make([]*repository.RegistryPackage, 0)
+	for r := range registryIter {
+		registry = append(registry, r)
+	}
+
+	links := PackageLinks(packages, registry, resolve)
+	counts := make(map[repository.PackageLinkStatus]int)
+	for _, link := range links {
+		counts[*link.Status]++
+	}
+	if dryRun {
+		return counts, nil
+	}
+	return counts, repository.NewPackageLinkRepository(ac).Replace(links)
+}
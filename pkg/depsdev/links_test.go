@@ -0,0 +1,72 @@
+package depsdev
+
+import (
+	"testing"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/repository"
+	"github.com/lib/pq"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizePackageName(t *testing.T) {
+	require.Equal(t, "zope-interface", NormalizePackageName(parser.PYPI, "Zope.Interface"))
+	require.Equal(t, "typing-extensions", NormalizePackageName(parser.PYPI, "typing__extensions"))
+	require.Equal(t, "serde-json", NormalizePackageName(parser.CARGO, "serde_json"))
+	require.Equal(t, "monolog/monolog", NormalizePackageName(parser.COMPOSER, "Monolog/Monolog"))
+	require.Equal(t, "@Scope/pkg", NormalizePackageName(parser.NPM, "@Scope/pkg"))
+}
+
+func TestPackageLinks(t *testing.T) {
+	const (
+		requests = "https://github.com/psf/requests"
+		mirror   = "https://gitee.com/mirrors/requests"
+		flask    = "https://github.com/pallets/flask"
+		fork     = "https://github.com/someone/flask"
+	)
+	pkg := func(link, eco, name string) *repository.GitPackage {
+		return &repository.GitPackage{GitLink: lo.ToPtr(link), Ecosystem: lo.ToPtr(eco), Name: lo.ToPtr(name)}
+	}
+	reg := func(eco, name, link string) *repository.RegistryPackage {
+		return &repository.RegistryPackage{Ecosystem: lo.ToPtr(eco), Name: lo.ToPtr(name), GitLink: lo.ToPtr(link)}
+	}
+	resolve := func(link string) string {
+		if link == mirror {
+			return requests
+		}
+		return link
+	}
+	links := PackageLinks([]*repository.GitPackage{
+		pkg(requests, parser.PYPI, "requests"),
+		pkg(mirror, parser.PYPI, "requests"),
+		pkg(flask, parser.PYPI, "Flask"),
+		pkg(fork, parser.PYPI, "flask"),
+		pkg(flask, parser.PYPI, "flask-docs"),
+	}, []*repository.RegistryPackage{
+		reg(parser.PYPI, "requests", "git@github.com:psf/requests.git"),
+		reg(parser.PYPI, "flask", "https://github.com/pallets/flask-old"),
+		// a typo-squat claiming the repository of requests
+		reg(parser.PYPI, "reqeusts", "https://github.com/psf/requests"),
+		reg(parser.PYPI, "numpy", "https://github.com/numpy/numpy"),
+	}, resolve)
+
+	byName := make(map[string]*repository.PackageLink)
+	for _, l := range links {
+		byName[*l.Name] = l
+	}
+	require.Len(t, byName, 4)
+
+	require.Equal(t, repository.PackageLinkAgree, *byName["requests"].Status)
+	require.Equal(t, pq.StringArray{requests}, *byName["requests"].ManifestLinks)
+
+	require.Equal(t, repository.PackageLinkDisagree, *byName["flask"].Status)
+	require.Equal(t, pq.StringArray{flask, fork}, *byName["flask"].ManifestLinks)
+	require.Equal(t, "https://github.com/pallets/flask-old", **byName["flask"].RegistryLink)
+
+	require.Equal(t, repository.PackageLinkDisagree, *byName["reqeusts"].Status)
+	require.Empty(t, *byName["reqeusts"].ManifestLinks)
+
+	require.Equal(t, repository.PackageLinkUnregistered, *byName["flask-docs"].Status)
+	require.Nil(t, *byName["flask-docs"].RegistryLink)
+}
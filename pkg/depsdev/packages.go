@@ -0,0 +1,145 @@
+package depsdev
+
+import (
+	"fmt"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
+	giturl "github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/url"
+	"github.com/HUSTSecLab/OpenSift/pkg/graph"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/repository"
+	"github.com/samber/lo"
+)
+
+// PackageEcosystems are the lang_ecosystems types of the ecosystems which
+// deps.dev does not cover, by parser ecosystem. Their dependents are counted
+// from the packages of the repositories collected, see PackageDependents.
+var PackageEcosystems = map[string]repository.LangEcosystemType{
+	parser.COMPOSER: repository.Packagist,
+	parser.GEMS:     repository.RubyGems,
+	parser.ELIXIR:   repository.Hex,
+	parser.CONAN:    repository.Conan,
+	parser.CONDA:    repository.Conda,
+	parser.SWIFT:    repository.SwiftPM,
+}
+
+type packageKey struct {
+	eco  string
+	name string
+}
+
+// PackageDependents returns the lang_ecosystems rows of the repositories
+// declaring packages of PackageEcosystems: the number of other repositories
+// depending on them, and the PageRank of the repository in the graph of the
+// repositories of the ecosystem, where rank flows from a repository to the
+// repositories declaring its dependencies.
+//
+// Swift packages are named by the link of their repository, so a Swift
+// dependency is declared by the repository it links to.
+func PackageDependents(packages []*repository.GitPackage, dependencies []*repository.GitPackageDependency) []*repository.LangEcosystem {
+	declaring := make(map[packageKey][]string)
+	owners := make(map[repository.LangEcosystemType]map[string]bool)
+	addOwner := func(ltype repository.LangEcosystemType, link string) {
+		if owners[ltype] == nil {
+			owners[ltype] = make(map[string]bool)
+		}
+		owners[ltype][link] = true
+	}
+	for _, p := range packages {
+		ltype, ok := PackageEcosystems[*p.Ecosystem]
+		if !ok {
+			continue
+		}
+		key := packageKey{*p.Ecosystem, *p.Name}
+		declaring[key] = append(declaring[key], *p.GitLink)
+		addOwner(ltype, *p.GitLink)
+	}
+
+	adjacency := make(map[repository.LangEcosystemType]map[string][]string)
+	dependents := make(map[repository.LangEcosystemType]map[string]map[string]bool)
+	for _, d := range dependencies {
+		ltype, ok := PackageEcosystems[*d.Ecosystem]
+		if !ok {
+			continue
+		}
+		links := declaring[packageKey{*d.Ecosystem, *d.Name}]
+		if *d.Ecosystem == parser.SWIFT {
+			if link, err := giturl.Canonicalize(*d.Name); err == nil {
+				links = append(links, link)
+				addOwner(ltype, link)
+			}
+		}
+		if adjacency[ltype] == nil {
+			adjacency[ltype] = make(map[string][]string)
+			dependents[ltype] = make(map[string]map[string]bool)
+		}
+		for _, link := range links {
+			if link == *d.GitLink {
+				continue
+			}
+			adjacency[ltype][*d.GitLink] = append(adjacency[ltype][*d.GitLink], link)
+			if dependents[ltype][link] == nil {
+				dependents[ltype][link] = make(map[string]bool)
+			}
+			dependents[ltype][link][*d.GitLink] = true
+		}
+	}
+
+	ret := make([]*repository.LangEcosystem, 0)
+	for ltype, links := range owners {
+		if adjacency[ltype] == nil {
+			adjacency[ltype] = make(map[string][]string)
+		}
+		for link := range links {
+			if _, ok := adjacency[ltype][link]; !ok {
+				adjacency[ltype][link] = nil
+			}
+		}
+		g := graph.FromAdjacency(adjacency[ltype])
+		pageRank := make(map[string]float64)
+		if result, err := g.PageRank(graph.DefaultPageRankOptions()); err != nil {
+			fmt.Println("Error calculating PageRank:", err)
+		} else {
+			pageRank = result.Map(g)
+		}
+		for link := range links {
+			depCount := len(dependents[ltype][link])
+			ret = append(ret, &repository.LangEcosystem{
+				GitLink:           lo.ToPtr(link),
+				Type:              lo.ToPtr(ltype),
+				DepCount:          lo.ToPtr(depCount),
+				LangEcoImpact:     lo.ToPtr(float64(depCount) / float64(PackageCounts[ltype])),
+				Lang_eco_pagerank: lo.ToPtr(pageRank[link]),
+			})
+		}
+	}
+	return ret
+}
+
+// UpdatePackageDependents inserts the lang_ecosystems rows computed by
+// PackageDependents from the packages stored by the collector.
+func UpdatePackageDependents(ac storage.AppDatabaseContext) error {
+	pr := repository.NewGitPackageRepository(ac)
+	packagesIter, err := pr.QueryPackages()
+	if err != nil {
+		return err
+	}
+	packages := make([]*repository.GitPackage, 0)
+	for p := range packagesIter {
+		packages = append(packages, p)
+	}
+	dependenciesIter, err := pr.QueryDependencies()
+	if err != nil {
+		return err
+	}
+	dependencies := make([]*repository.GitPackageDependency, 0)
+	for d := range dependenciesIter {
+		dependencies = append(dependencies, d)
+	}
+
+	rows := PackageDependents(packages, dependencies)
+	if len(rows) == 0 {
+		return nil
+	}
+	return repository.NewLangEcoLinkRepository(ac).BatchInsertOrUpdate(rows)
+}
@@ -0,0 +1,53 @@
+package depsdev
+
+import (
+	"testing"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/repository"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPackageDependents(t *testing.T) {
+	const (
+		monolog = "https://github.com/Seldaek/monolog"
+		laravel = "https://github.com/laravel/framework"
+		app     = "https://github.com/owner/app"
+		alamo   = "https://github.com/alamofire/alamofire"
+	)
+	pkg := func(link, eco, name string) *repository.GitPackage {
+		return &repository.GitPackage{GitLink: lo.ToPtr(link), Ecosystem: lo.ToPtr(eco), Name: lo.ToPtr(name)}
+	}
+	dep := func(link, eco, name string) *repository.GitPackageDependency {
+		return &repository.GitPackageDependency{GitLink: lo.ToPtr(link), Ecosystem: lo.ToPtr(eco), Name: lo.ToPtr(name)}
+	}
+	rows := PackageDependents([]*repository.GitPackage{
+		pkg(monolog, parser.COMPOSER, "monolog/monolog"),
+		pkg(laravel, parser.COMPOSER, "laravel/framework"),
+		pkg(app, parser.NPM, "app"),
+	}, []*repository.GitPackageDependency{
+		dep(laravel, parser.COMPOSER, "monolog/monolog"),
+		dep(app, parser.COMPOSER, "monolog/monolog"),
+		dep(app, parser.COMPOSER, "laravel/framework"),
+		dep(app, parser.SWIFT, "https://github.com/Alamofire/Alamofire.git"),
+		// a package depending on itself, e.g. from its lock file
+		dep(monolog, parser.COMPOSER, "monolog/monolog"),
+		dep(app, parser.NPM, "left-pad"),
+	})
+
+	byLink := make(map[string]*repository.LangEcosystem)
+	for _, row := range rows {
+		byLink[*row.GitLink] = row
+	}
+	require.Len(t, byLink, 3)
+
+	require.Equal(t, repository.Packagist, *byLink[monolog].Type)
+	require.Equal(t, 2, *byLink[monolog].DepCount)
+	require.Equal(t, 1, *byLink[laravel].DepCount)
+	require.InDelta(t, 2/float64(PackageCounts[repository.Packagist]), *byLink[monolog].LangEcoImpact, 1e-12)
+	require.Greater(t, *byLink[monolog].Lang_eco_pagerank, *byLink[laravel].Lang_eco_pagerank)
+
+	require.Equal(t, repository.SwiftPM, *byLink[alamo].Type)
+	require.Equal(t, 1, *byLink[alamo].DepCount)
+}
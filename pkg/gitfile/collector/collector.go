@@ -5,6 +5,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 
 	"github.com/HUSTSecLab/OpenSift/pkg/config"
 	parser "github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
@@ -17,13 +18,32 @@ import (
 
 // clone or update the repository, and collect metadata
 func Collect(u *url.RepoURL, path string, progress io.Writer) (*gogit.Repository, error) {
+	return CollectFilter(u, path, Filter(path), progress)
+}
+
+// CollectFilter clones the repository as a partial clone with filter, e.g.
+// FilterBlobless, a full mirror if filter is empty, if it does not exist or
+// is filtered otherwise. Existing mirrors are updated. The blobs of the tree
+// of HEAD are fetched into partial mirrors.
+func CollectFilter(u *url.RepoURL, path string, filter string, progress io.Writer) (*gogit.Repository, error) {
 	_, err := Open(path)
-	if err != nil { // not exsists
-		r, err := CloneOutProcess(u, path, progress)
+	if err != nil || Filter(path) != filter { // not exsists
+		r, err := cloneOutProcess(u, path, filter, progress)
 		if err != nil {
 			logger.Errorf("Failed to Clone %s, %v", u.URL, err)
+			return r, err
+		}
+		if filter != "" {
+			err = FetchHeadBlobs(path, progress)
 		}
 		return r, err
+	} else if Filter(path) != "" {
+		r, err := UpdateOutProcess(path, progress)
+		if err != nil {
+			logger.Errorf("Failed to Update %s, %v", u.URL, err)
+			return r, err
+		}
+		return r, FetchHeadBlobs(path, progress)
 	} else {
 		r, err := Update(u, path, progress)
 		if err != nil {
@@ -46,6 +66,10 @@ func EzCollect(u *url.RepoURL) (*gogit.Repository, error) {
 
 // clone use system git
 func CloneOutProcess(u *url.RepoURL, path string, progress io.Writer) (*gogit.Repository, error) {
+	return cloneOutProcess(u, path, "", progress)
+}
+
+func cloneOutProcess(u *url.RepoURL, path string, filter string, progress io.Writer) (*gogit.Repository, error) {
 	// get parent path of the path
 	tmpPath := filepath.Dir(path)
 	if config.GetGitStoragePath() != "" {
@@ -66,7 +90,11 @@ func CloneOutProcess(u *url.RepoURL, path string, progress io.Writer) (*gogit.Re
 
 	defer os.RemoveAll(tmpDir)
 
-	cmd := exec.Command("git", "clone", "--mirror", "--progress", u.URL, tmpDir)
+	args := []string{"clone", "--mirror", "--progress"}
+	if filter != "" {
+		args = append(args, "--filter="+filter)
+	}
+	cmd := exec.Command("git", append(args, u.URL, tmpDir)...)
 	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
 	cmd.Stderr = progress
 	cmd.Stdout = progress
@@ -92,6 +120,17 @@ func CloneOutProcess(u *url.RepoURL, path string, progress io.Writer) (*gogit.Re
 	return Open(path)
 }
 
+// Redirect returns the link system git reported being redirected to in its
+// output, e.g. by a renamed or transferred repository, empty if it was not.
+func Redirect(output string) string {
+	for _, line := range strings.Split(output, "\n") {
+		if link, ok := strings.CutPrefix(strings.TrimSpace(line), "warning: redirecting to "); ok {
+			return strings.TrimRight(link, "/")
+		}
+	}
+	return ""
+}
+
 // Very slow, USE CloneOutProcess Instead
 // only clone the repository, if it exists, return error
 func Clone(u *url.RepoURL, path string, progress io.Writer) (*gogit.Repository, error) {
@@ -71,3 +71,11 @@ func TestCheck(t *testing.T) {
 		})
 	}
 }
+
+func TestRedirect(t *testing.T) {
+	output := "Cloning into bare repository '/tmp/clone-tmp-1'...\n" +
+		"warning: redirecting to https://github.com/new-owner/repo.git/\n" +
+		"remote: Enumerating objects: 3, done.\n"
+	require.Equal(t, "https://github.com/new-owner/repo.git", Redirect(output))
+	require.Empty(t, Redirect("Cloning into bare repository '/tmp/clone-tmp-1'...\n"))
+}
@@ -0,0 +1,89 @@
+package collector
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	parser "github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
+	gogit "github.com/go-git/go-git/v5"
+)
+
+// FilterBlobless clones the commits and trees without any blob. The log is
+// walked as in a full mirror, and only the blobs of the tree of HEAD, needed
+// by the language, license and manifest parsers, are fetched.
+const FilterBlobless = "blob:none"
+
+// Filter returns the filter of the partial mirror at path, empty for a full
+// mirror or if there is none.
+func Filter(path string) string {
+	r, err := Open(path)
+	if err != nil {
+		return ""
+	}
+	cfg, err := r.Config()
+	if err != nil {
+		return ""
+	}
+	remote := cfg.Raw.Section("remote").Subsection(parser.DEFAULT_REMOTE_NAME)
+	if !remote.HasOption("promisor") {
+		return ""
+	}
+	return remote.Option("partialclonefilter")
+}
+
+// git runs a git command in the repository at path.
+func git(path string, stdin io.Reader, stdout, progress io.Writer, args ...string) error {
+	cmd := exec.Command("git", append([]string{"-C", path}, args...)...)
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	cmd.Stdin = stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = progress
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("git %s: %w", strings.Join(args, " "), err)
+	}
+	return nil
+}
+
+// UpdateOutProcess fetches the partial mirror at path from its remote with
+// system git, which keeps fetching it without the filtered objects.
+func UpdateOutProcess(path string, progress io.Writer) (*gogit.Repository, error) {
+	err := git(path, nil, progress, progress, "fetch", "--prune", "--progress", parser.DEFAULT_REMOTE_NAME)
+	if err != nil {
+		return nil, err
+	}
+	return Open(path)
+}
+
+// FetchHeadBlobs fetches the objects of the tree of HEAD missing from the
+// partial mirror at path, in one request rather than one per object as git
+// would do when reading them.
+func FetchHeadBlobs(path string, progress io.Writer) error {
+	// nothing to fetch into an empty repository
+	if r, err := Open(path); err != nil {
+		return err
+	} else if _, err := r.Head(); err != nil {
+		return nil
+	}
+	var objects bytes.Buffer
+	err := git(path, nil, &objects, progress, "rev-list", "--objects", "--missing=print", "--no-walk", "HEAD")
+	if err != nil {
+		return err
+	}
+	var missing strings.Builder
+	for _, line := range strings.Split(objects.String(), "\n") {
+		if hash, ok := strings.CutPrefix(line, "?"); ok {
+			missing.WriteString(hash + "\n")
+		}
+	}
+	if missing.Len() == 0 {
+		return nil
+	}
+	return git(path, strings.NewReader(missing.String()), progress, progress,
+		"-c", "fetch.negotiationAlgorithm=noop", "fetch", "--stdin",
+		"--no-tags", "--no-write-fetch-head", "--recurse-submodules=no",
+		"--filter="+FilterBlobless, parser.DEFAULT_REMOTE_NAME)
+}
@@ -0,0 +1,110 @@
+package collector
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	url "github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/url"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/stretchr/testify/require"
+)
+
+// newSourceRepository returns the url of a local repository serving partial
+// clones, with one commit per content of file f.
+func newSourceRepository(t *testing.T, contents ...string) (*url.RepoURL, func(string)) {
+	dir := filepath.Join(t.TempDir(), "src")
+	run := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	require.NoError(t, os.MkdirAll(dir, 0777))
+	run("init", "-q")
+	run("config", "uploadpack.allowFilter", "true")
+	run("config", "uploadpack.allowAnySHA1InWant", "true")
+	commit := func(content string) {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "f"), []byte(content), 0666))
+		run("add", "f")
+		run("-c", "user.name=a", "-c", "user.email=a@a.org", "commit", "-q", "-m", content)
+	}
+	for _, content := range contents {
+		commit(content)
+	}
+	return &url.RepoURL{URL: "file://" + dir}, commit
+}
+
+// blob returns the content of f at the given revision of the mirror at path.
+func blob(t *testing.T, path string, rev string) (string, error) {
+	r, err := Open(path)
+	require.NoError(t, err)
+	hash, err := r.ResolveRevision(plumbing.Revision(rev))
+	require.NoError(t, err)
+	c, err := r.CommitObject(*hash)
+	require.NoError(t, err)
+	f, err := c.File("f")
+	if err != nil {
+		return "", err
+	}
+	return f.Contents()
+}
+
+func TestCollectBlobless(t *testing.T) {
+	u, commit := newSourceRepository(t, "one", "two")
+	path := filepath.Join(t.TempDir(), "mirror.git")
+
+	_, err := CollectFilter(u, path, FilterBlobless, nil)
+	require.NoError(t, err)
+	require.Equal(t, FilterBlobless, Filter(path))
+	content, err := blob(t, path, "HEAD")
+	require.NoError(t, err)
+	require.Equal(t, "two", content)
+	_, err = blob(t, path, "HEAD~1")
+	require.Error(t, err)
+
+	// updated without the blobs of the history
+	commit("three")
+	_, err = Collect(u, path, nil)
+	require.NoError(t, err)
+	require.Equal(t, FilterBlobless, Filter(path))
+	content, err = blob(t, path, "HEAD")
+	require.NoError(t, err)
+	require.Equal(t, "three", content)
+	_, err = blob(t, path, "HEAD~2")
+	require.Error(t, err)
+}
+
+func TestCollectBloblessReplacesFullMirror(t *testing.T) {
+	u, _ := newSourceRepository(t, "one", "two")
+	path := filepath.Join(t.TempDir(), "mirror.git")
+
+	_, err := Collect(u, path, nil)
+	require.NoError(t, err)
+	require.Empty(t, Filter(path))
+	_, err = blob(t, path, "HEAD~1")
+	require.NoError(t, err)
+
+	_, err = CollectFilter(u, path, FilterBlobless, nil)
+	require.NoError(t, err)
+	require.Equal(t, FilterBlobless, Filter(path))
+	_, err = blob(t, path, "HEAD~1")
+	require.Error(t, err)
+}
+
+func TestCollectFullReplacesBloblessMirror(t *testing.T) {
+	u, _ := newSourceRepository(t, "one", "two")
+	path := filepath.Join(t.TempDir(), "mirror.git")
+
+	_, err := CollectFilter(u, path, FilterBlobless, nil)
+	require.NoError(t, err)
+	_, err = blob(t, path, "HEAD~1")
+	require.Error(t, err)
+
+	_, err = CollectFilter(u, path, "", nil)
+	require.NoError(t, err)
+	require.Empty(t, Filter(path))
+	content, err := blob(t, path, "HEAD~1")
+	require.NoError(t, err)
+	require.Equal(t, "one", content)
+}
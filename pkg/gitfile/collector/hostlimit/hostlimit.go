@@ -0,0 +1,286 @@
+// Package hostlimit limits the clones and fetches of git repositories per
+// host, so the hosts are not hammered.
+package hostlimit
+
+import (
+	"errors"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/logger"
+)
+
+// DefaultHost is the host of the limit applied to the hosts without one,
+// e.g. self-hosted cgit or gitweb servers.
+const DefaultHost = "*"
+
+// Limit limits the clones and fetches of the repositories of a host.
+type Limit struct {
+	// Host is the host name, it also applies to its subdomains, e.g.
+	// kernel.org to git.kernel.org
+	Host string `mapstructure:"host"`
+	// Concurrency is the maximum number of clones and fetches at a time,
+	// negative for no limit
+	Concurrency int `mapstructure:"concurrency"`
+	// Rate is the maximum number of clones and fetches started per second,
+	// up to Burst at once, negative for no limit
+	Rate  float64 `mapstructure:"rate"`
+	Burst int     `mapstructure:"burst"`
+	// Backoff is the time no clone or fetch starts after the host throttled
+	// one, doubled every time it throttles again up to MaxBackoff, negative
+	// for no backoff
+	Backoff    time.Duration `mapstructure:"backoff"`
+	MaxBackoff time.Duration `mapstructure:"max-backoff"`
+}
+
+// withDefaults returns limit with its unset (zero) fields taken from def.
+func (limit Limit) withDefaults(def Limit) Limit {
+	if limit.Concurrency == 0 {
+		limit.Concurrency = def.Concurrency
+	}
+	if limit.Rate == 0 {
+		limit.Rate = def.Rate
+	}
+	if limit.Burst == 0 {
+		limit.Burst = def.Burst
+	}
+	if limit.Backoff == 0 {
+		limit.Backoff = def.Backoff
+	}
+	if limit.MaxBackoff == 0 {
+		limit.MaxBackoff = def.MaxBackoff
+	}
+	return limit
+}
+
+// DefaultLimits are the limits of the hosts without a configured one.
+var DefaultLimits = []Limit{
+	{Host: "github.com", Concurrency: 32, Rate: 4, Burst: 8, Backoff: time.Minute, MaxBackoff: time.Hour},
+	{Host: "gitlab.com", Concurrency: 16, Rate: 2, Burst: 4, Backoff: time.Minute, MaxBackoff: time.Hour},
+	{Host: "gitee.com", Concurrency: 8, Rate: 1, Burst: 2, Backoff: time.Minute, MaxBackoff: time.Hour},
+	{Host: "kernel.org", Concurrency: 4, Rate: 0.5, Burst: 2, Backoff: 2 * time.Minute, MaxBackoff: time.Hour},
+	{Host: DefaultHost, Concurrency: 4, Rate: 0.5, Burst: 2, Backoff: 2 * time.Minute, MaxBackoff: time.Hour},
+}
+
+// throttled matches the messages of git and go-git when a host refuses a
+// request because of its load, e.g. "The requested URL returned error: 429"
+// or "unexpected requesting ... status code: 503".
+var throttled = regexp.MustCompile(`(?i)(?:error:?|status(?: code)?:?|HTTP/[\d.]+)\s*(?:429|503)\b|too many requests|service unavailable|connection reset|rate limit exceeded`)
+
+// IsThrottled reports whether err, and output the output of the clone or
+// fetch, show that the host throttled it.
+func IsThrottled(err error, output string) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, syscall.ECONNRESET) {
+		return true
+	}
+	return throttled.MatchString(err.Error()) || throttled.MatchString(output)
+}
+
+// Status is the state of the limiter of a host.
+type Status struct {
+	Host        string
+	Concurrency int
+	Rate        float64
+	Running     int
+	Waiting     int
+	// Throttled is the number of clones and fetches throttled by the host
+	Throttled    int
+	BackoffUntil time.Time
+}
+
+type hostState struct {
+	limit Limit
+	cond  *sync.Cond
+
+	running int
+	waiting int
+	// token bucket of the rate limit
+	tokens float64
+	last   time.Time
+
+	throttled    int
+	backoff      time.Duration
+	backoffUntil time.Time
+}
+
+// Limiter limits the clones and fetches per host, see Limit.
+type Limiter struct {
+	mu     sync.Mutex
+	limits map[string]Limit
+	hosts  map[string]*hostState
+}
+
+// NewLimiter returns a limiter of the hosts of limits, and of the hosts of
+// DefaultLimits not in limits. The fields of limits left unset take the
+// default limit of their host.
+func NewLimiter(limits []Limit) *Limiter {
+	defaults := make(map[string]Limit)
+	for _, limit := range DefaultLimits {
+		defaults[limit.Host] = limit
+	}
+	l := &Limiter{
+		limits: make(map[string]Limit),
+		hosts:  make(map[string]*hostState),
+	}
+	for host, limit := range defaults {
+		l.limits[host] = limit
+	}
+	for _, limit := range limits {
+		limit.Host = strings.ToLower(limit.Host)
+		l.limits[limit.Host] = limit.withDefaults(limitOf(defaults, limit.Host))
+	}
+	return l
+}
+
+// limitOf returns the limit of host in limits, of its nearest parent domain
+// having one or else of DefaultHost.
+func limitOf(limits map[string]Limit, host string) Limit {
+	for h := host; h != ""; {
+		if limit, ok := limits[h]; ok {
+			return limit
+		}
+		i := strings.IndexByte(h, '.')
+		if i < 0 {
+			break
+		}
+		h = h[i+1:]
+	}
+	return limits[DefaultHost]
+}
+
+// limitOf returns the limit of host, see limitOf.
+func (l *Limiter) limitOf(host string) Limit {
+	return limitOf(l.limits, host)
+}
+
+func (l *Limiter) state(host string) *hostState {
+	host = strings.ToLower(host)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	h, ok := l.hosts[host]
+	if !ok {
+		limit := l.limitOf(host)
+		h = &hostState{limit: limit, cond: sync.NewCond(&l.mu), tokens: float64(limit.Burst)}
+		l.hosts[host] = h
+	}
+	return h
+}
+
+// reserve takes a token of the bucket of h at now, it returns the time to
+// wait for one if there is none.
+func (h *hostState) reserve(now time.Time) time.Duration {
+	if h.limit.Rate <= 0 {
+		return 0
+	}
+	burst := float64(max(h.limit.Burst, 1))
+	if !h.last.IsZero() {
+		h.tokens = min(burst, h.tokens+now.Sub(h.last).Seconds()*h.limit.Rate)
+	}
+	h.last = now
+	if h.tokens >= 1 {
+		h.tokens--
+		return 0
+	}
+	return time.Duration((1 - h.tokens) / h.limit.Rate * float64(time.Second))
+}
+
+// Acquire waits until a clone or fetch of host may start. The returned
+// function must be called when it ends, with its error and output.
+func (l *Limiter) Acquire(host string) func(err error, output string) {
+	h := l.state(host)
+
+	l.mu.Lock()
+	h.waiting++
+	for {
+		if h.limit.Concurrency > 0 && h.running >= h.limit.Concurrency {
+			h.cond.Wait()
+			continue
+		}
+		now := time.Now()
+		wait := h.backoffUntil.Sub(now)
+		if wait <= 0 {
+			wait = h.reserve(now)
+		}
+		if wait <= 0 {
+			break
+		}
+		l.mu.Unlock()
+		time.Sleep(wait)
+		l.mu.Lock()
+	}
+	h.waiting--
+	h.running++
+	l.mu.Unlock()
+
+	var once sync.Once
+	return func(err error, output string) {
+		once.Do(func() {
+			l.release(host, h, err, output)
+		})
+	}
+}
+
+func (l *Limiter) release(host string, h *hostState, err error, output string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	h.running--
+	if IsThrottled(err, output) {
+		h.throttled++
+		if h.limit.Backoff < 0 {
+			h.backoff = 0
+		} else if h.backoff == 0 {
+			h.backoff = h.limit.Backoff
+		} else {
+			h.backoff *= 2
+		}
+		if h.limit.MaxBackoff > 0 && h.backoff > h.limit.MaxBackoff {
+			h.backoff = h.limit.MaxBackoff
+		}
+		// another throttled request may have backed off longer
+		if until := time.Now().Add(h.backoff); until.After(h.backoffUntil) {
+			h.backoffUntil = until
+		}
+		logger.Warnf("%s throttled a request, backing off for %v: %v", host, h.backoff, err)
+	} else if err == nil {
+		h.backoff = 0
+	}
+	h.cond.Broadcast()
+}
+
+// BackoffUntil returns the time until which no clone or fetch of host
+// starts, it is in the past if the host is not backed off.
+func (l *Limiter) BackoffUntil(host string) time.Time {
+	h := l.state(host)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return h.backoffUntil
+}
+
+// Status returns the state of every host, sorted by host.
+func (l *Limiter) Status() []Status {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	ret := make([]Status, 0, len(l.hosts))
+	for host, h := range l.hosts {
+		ret = append(ret, Status{
+			Host:         host,
+			Concurrency:  h.limit.Concurrency,
+			Rate:         h.limit.Rate,
+			Running:      h.running,
+			Waiting:      h.waiting,
+			Throttled:    h.throttled,
+			BackoffUntil: h.backoffUntil,
+		})
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Host < ret[j].Host
+	})
+	return ret
+}
@@ -0,0 +1,116 @@
+package hostlimit
+
+import (
+	"errors"
+	"fmt"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsThrottled(t *testing.T) {
+	exit := errors.New("exit status 128")
+	tests := []struct {
+		err       error
+		output    string
+		throttled bool
+	}{
+		{nil, "error: The requested URL returned error: 429", false},
+		{exit, "fatal: unable to access 'https://example.org/a.git/': The requested URL returned error: 429", true},
+		{exit, "error: RPC failed; HTTP 503 curl 22 The requested URL returned error: 503", true},
+		{errors.New(`unexpected client error: unexpected requesting "https://example.org/a.git/info/refs" status code: 429`), "", true},
+		{fmt.Errorf("read: %w", syscall.ECONNRESET), "", true},
+		{exit, "fatal: read error: Connection reset by peer", true},
+		{exit, "Receiving objects:  42% (429/1000)\nfatal: repository 'https://example.org/a.git/' not found", false},
+		{exit, "fatal: Authentication failed", false},
+	}
+	for _, tt := range tests {
+		require.Equal(t, tt.throttled, IsThrottled(tt.err, tt.output), tt.output)
+	}
+}
+
+func TestLimitOf(t *testing.T) {
+	l := NewLimiter([]Limit{{Host: "Example.org", Concurrency: 1}, {Host: "gitlab.com", Concurrency: 2}})
+	require.Equal(t, "example.org", l.limitOf("git.example.org").Host)
+	require.Equal(t, 2, l.limitOf("gitlab.com").Concurrency)
+	require.Equal(t, "kernel.org", l.limitOf("git.kernel.org").Host)
+	require.Equal(t, DefaultHost, l.limitOf("cgit.freedesktop.org").Host)
+}
+
+func TestLimitDefaults(t *testing.T) {
+	l := NewLimiter([]Limit{{Host: "github.com", Concurrency: 8}, {Host: "example.org", Rate: -1}})
+	require.Equal(t, Limit{Host: "github.com", Concurrency: 8, Rate: 4, Burst: 8, Backoff: time.Minute, MaxBackoff: time.Hour},
+		l.limitOf("github.com"))
+	require.Equal(t, Limit{Host: "example.org", Concurrency: 4, Rate: -1, Burst: 2, Backoff: 2 * time.Minute, MaxBackoff: time.Hour},
+		l.limitOf("example.org"))
+}
+
+func TestLimiterConcurrency(t *testing.T) {
+	l := NewLimiter([]Limit{{Host: "example.org", Concurrency: 1}})
+	release := l.Acquire("example.org")
+
+	acquired := make(chan func(error, string))
+	go func() {
+		acquired <- l.Acquire("example.org")
+	}()
+	select {
+	case <-acquired:
+		t.Fatal("acquired above the concurrency of the host")
+	case <-time.After(50 * time.Millisecond):
+	}
+	status := l.Status()
+	require.Len(t, status, 1)
+	require.Equal(t, 1, status[0].Running)
+	require.Equal(t, 1, status[0].Waiting)
+
+	release(nil, "")
+	select {
+	case release = <-acquired:
+	case <-time.After(time.Second):
+		t.Fatal("not acquired after a release")
+	}
+	release(nil, "")
+	require.Equal(t, 0, l.Status()[0].Running)
+}
+
+func TestLimiterRate(t *testing.T) {
+	l := NewLimiter([]Limit{{Host: "example.org", Rate: 20, Burst: 2}})
+	begin := time.Now()
+	for i := 0; i < 4; i++ {
+		l.Acquire("example.org")(nil, "")
+	}
+	// 2 at once, then one every 50ms
+	require.GreaterOrEqual(t, time.Since(begin), 90*time.Millisecond)
+}
+
+func TestLimiterBackoff(t *testing.T) {
+	l := NewLimiter([]Limit{{Host: "example.org", Backoff: time.Minute, MaxBackoff: 3 * time.Minute}})
+	throttle := errors.New("exit status 128")
+	output := "The requested URL returned error: 429"
+
+	l.Acquire("example.org")(throttle, output)
+	h := l.state("example.org")
+	require.Equal(t, time.Minute, h.backoff)
+	require.Equal(t, 1, l.Status()[0].Throttled)
+	require.True(t, l.Status()[0].BackoffUntil.After(time.Now().Add(59*time.Second)))
+
+	// throttled again while backing off, e.g. by a request started before
+	release := func(err error, output string) {
+		l.mu.Lock()
+		h.running++
+		l.mu.Unlock()
+		l.release("example.org", h, err, output)
+	}
+	release(throttle, output)
+	require.Equal(t, 2*time.Minute, h.backoff)
+	release(throttle, output)
+	require.Equal(t, 3*time.Minute, h.backoff)
+
+	// a success resets the backoff, other failures keep it
+	release(errors.New("exit status 128"), "fatal: repository not found")
+	require.Equal(t, 3*time.Minute, h.backoff)
+	release(nil, "")
+	require.Equal(t, time.Duration(0), h.backoff)
+}
@@ -0,0 +1,98 @@
+package collector
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	url "github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/url"
+)
+
+// DiskUsage returns the size in bytes of the files under path.
+func DiskUsage(path string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		size += info.Size()
+		return nil
+	})
+	return size, err
+}
+
+// IsShallow returns whether the mirror at path has been pruned by
+// ShallowPrune.
+func IsShallow(path string) bool {
+	_, err := os.Stat(filepath.Join(path, "shallow"))
+	return err == nil
+}
+
+// ShallowPrune fetches only the tips of the refs of the mirror at path, then
+// drops the rest of the history. The commits walked before are still counted
+// from the checkpoint of the mirror, but a full walk of a shallow mirror
+// fails, it must be cloned again.
+func ShallowPrune(u *url.RepoURL, path string, progress io.Writer) error {
+	commands := [][]string{
+		{"fetch", "--depth=1", "--progress", u.URL, "+refs/*:refs/*"},
+		{"reflog", "expire", "--expire=now", "--all"},
+		{"gc", "--prune=now", "--quiet"},
+	}
+	for _, args := range commands {
+		if err := git(path, nil, progress, progress, args...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var sizeUnits = []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB"}
+
+// ParseSize parses a size in bytes such as 1024, 512M, 500GB or 1.5TiB. As
+// for git, the units are powers of 1024.
+func ParseSize(s string) (int64, error) {
+	num := strings.TrimSpace(s)
+	unit := strings.TrimLeft(num, "0123456789.")
+	num = strings.TrimSpace(num[:len(num)-len(unit)])
+	unit = strings.ToUpper(strings.TrimSpace(unit))
+	unit = strings.TrimSuffix(strings.TrimSuffix(unit, "B"), "I")
+
+	v, err := strconv.ParseFloat(num, 64)
+	if err != nil || v < 0 {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	exp := strings.Index("KMGTP", unit) + 1
+	if unit == "" {
+		exp = 0
+	} else if exp == 0 || len(unit) > 1 {
+		return 0, fmt.Errorf("invalid size %q: unknown unit", s)
+	}
+	for range exp {
+		v *= 1024
+	}
+	return int64(v), nil
+}
+
+// FormatSize formats size in bytes in the largest unit below it.
+func FormatSize(size int64) string {
+	v := float64(size)
+	i := 0
+	for ; i < len(sizeUnits)-1 && (v >= 1024 || v <= -1024); i++ {
+		v /= 1024
+	}
+	if i == 0 {
+		return fmt.Sprintf("%d B", size)
+	}
+	return fmt.Sprintf("%.1f %s", v, sizeUnits[i])
+}
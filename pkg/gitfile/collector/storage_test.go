@@ -0,0 +1,53 @@
+package collector
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		input string
+		size  int64
+	}{
+		{"1024", 1024},
+		{"512M", 512 << 20},
+		{"500GB", 500 << 30},
+		{"1.5TiB", 3 << 39},
+		{" 2 gib ", 2 << 30},
+		{"0", 0},
+	}
+	for _, tt := range tests {
+		size, err := ParseSize(tt.input)
+		require.NoError(t, err, tt.input)
+		require.Equal(t, tt.size, size, tt.input)
+	}
+	for _, input := range []string{"", "GB", "-1G", "10XB", "10GiBB"} {
+		_, err := ParseSize(input)
+		require.Error(t, err, input)
+	}
+}
+
+func TestFormatSize(t *testing.T) {
+	require.Equal(t, "512 B", FormatSize(512))
+	require.Equal(t, "1.5 KiB", FormatSize(1536))
+	require.Equal(t, "500.0 GiB", FormatSize(500<<30))
+}
+
+func TestDiskUsage(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "objects", "pack"), 0777))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "HEAD"), []byte("ref: refs/heads/main\n"), 0666))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "objects", "pack", "a.pack"), make([]byte, 4096), 0666))
+
+	size, err := DiskUsage(dir)
+	require.NoError(t, err)
+	require.Equal(t, int64(21+4096), size)
+	require.False(t, IsShallow(dir))
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "shallow"), nil, 0666))
+	require.True(t, IsShallow(dir))
+}
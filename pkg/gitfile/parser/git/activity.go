@@ -0,0 +1,140 @@
+package git
+
+import (
+	"fmt"
+	"time"
+
+	parser "github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/repository"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/sqlutil"
+)
+
+// Window is the number of days of history before the walk of the log that
+// an Activity covers, WindowLifetime covers the whole history.
+type Window int
+
+const (
+	WindowLifetime Window = 0
+	Window30Days   Window = 30
+	Window90Days   Window = 90
+	Window365Days  Window = 365
+)
+
+var Windows = []Window{Window30Days, Window90Days, Window365Days, WindowLifetime}
+
+func (w Window) String() string {
+	if w == WindowLifetime {
+		return "lifetime"
+	}
+	return fmt.Sprintf("%dd", int(w))
+}
+
+// Since returns the beginning of the window ending at now.
+func (w Window) Since(now time.Time) time.Time {
+	if w == WindowLifetime {
+		return time.Time{}
+	}
+	return now.AddDate(0, 0, -int(w))
+}
+
+// Activity is the activity of a repository during a window, by the commit
+// time of the commits. Authors are contributors merged by IdentityResolver,
+// and Orgs are the organizations of their corporate email domains.
+type Activity struct {
+	Commits int
+	Authors int
+	Orgs    int
+	// Maintainers are the authors of at least parser.MAINTAINER_MIN_COMMITS
+	// commits in the window
+	Maintainers int
+}
+
+type activityCounter struct {
+	since   time.Time
+	commits int
+	authors map[Identity]int
+}
+
+func newActivityCounter(since time.Time) *activityCounter {
+	return &activityCounter{
+		since:   since,
+		authors: make(map[Identity]int),
+	}
+}
+
+func newActivityCounters(now time.Time) map[Window]*activityCounter {
+	ret := make(map[Window]*activityCounter, len(Windows))
+	for _, w := range Windows {
+		ret[w] = newActivityCounter(w.Since(now))
+	}
+	return ret
+}
+
+func (c *activityCounter) add(author Identity, when time.Time, commits int) {
+	if when.Before(c.since) {
+		return
+	}
+	c.commits += commits
+	c.authors[author] += commits
+}
+
+// activity returns the activity of the window, the authors are merged and
+// their organizations found by resolver.
+func (c *activityCounter) activity(resolver *IdentityResolver) Activity {
+	contributors := c.contributors(resolver)
+	orgs := c.orgs(resolver)
+
+	maintainers := 0
+	for _, commits := range contributors {
+		if commits >= parser.MAINTAINER_MIN_COMMITS {
+			maintainers++
+		}
+	}
+	return Activity{
+		Commits:     c.commits,
+		Authors:     len(contributors),
+		Orgs:        len(orgs),
+		Maintainers: maintainers,
+	}
+}
+
+// contributors returns the commits of every contributor of the window.
+func (c *activityCounter) contributors(resolver *IdentityResolver) map[string]int {
+	ret := make(map[string]int)
+	for author, commits := range c.authors {
+		ret[resolver.Contributor(author)] += commits
+	}
+	return ret
+}
+
+// orgs returns the commits of every organization of the window.
+func (c *activityCounter) orgs(resolver *IdentityResolver) map[string]int {
+	ret := make(map[string]int)
+	for author, commits := range c.authors {
+		if org, ok := resolver.Organization(author); ok {
+			ret[org] += commits
+		}
+	}
+	return ret
+}
+
+// SetActivityMetrics sets the activity columns of m, windows not walked are
+// left unset.
+func (repo *Repo) SetActivityMetrics(m *repository.GitMetric) {
+	columns := map[Window][4]***int{
+		Window30Days:   {&m.Commits30d, &m.Authors30d, &m.Orgs30d, &m.Maintainers30d},
+		Window90Days:   {&m.Commits90d, &m.Authors90d, &m.Orgs90d, &m.Maintainers90d},
+		Window365Days:  {&m.Commits365d, &m.Authors365d, &m.Orgs365d, &m.Maintainers365d},
+		WindowLifetime: {&m.CommitsLifetime, &m.AuthorsLifetime, &m.OrgsLifetime, &m.MaintainersLifetime},
+	}
+	for w, c := range columns {
+		a, ok := repo.Activity[w]
+		if !ok {
+			continue
+		}
+		*c[0] = sqlutil.ToNullable(a.Commits)
+		*c[1] = sqlutil.ToNullable(a.Authors)
+		*c[2] = sqlutil.ToNullable(a.Orgs)
+		*c[3] = sqlutil.ToNullable(a.Maintainers)
+	}
+}
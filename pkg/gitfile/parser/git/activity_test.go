@@ -0,0 +1,154 @@
+package git
+
+import (
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/repository"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"github.com/stretchr/testify/require"
+)
+
+type testCommit struct {
+	name  string
+	email string
+	age   time.Duration
+}
+
+// writeTestTree writes the tree of files, by slash separated path.
+func writeTestTree(t *testing.T, r *git.Repository, files map[string]string) plumbing.Hash {
+	tree := &object.Tree{}
+	subtrees := map[string]map[string]string{}
+	for name, content := range files {
+		if dir, rest, ok := strings.Cut(name, "/"); ok {
+			if subtrees[dir] == nil {
+				subtrees[dir] = map[string]string{}
+			}
+			subtrees[dir][rest] = content
+			continue
+		}
+		obj := r.Storer.NewEncodedObject()
+		obj.SetType(plumbing.BlobObject)
+		w, err := obj.Writer()
+		require.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+		hash, err := r.Storer.SetEncodedObject(obj)
+		require.NoError(t, err)
+		tree.Entries = append(tree.Entries, object.TreeEntry{Name: name, Mode: filemode.Regular, Hash: hash})
+	}
+	for dir, files := range subtrees {
+		tree.Entries = append(tree.Entries, object.TreeEntry{Name: dir, Mode: filemode.Dir, Hash: writeTestTree(t, r, files)})
+	}
+	// git requires the entries of a tree to be sorted, directories as if
+	// their name ended with a slash
+	sortName := func(e object.TreeEntry) string {
+		if e.Mode == filemode.Dir {
+			return e.Name + "/"
+		}
+		return e.Name
+	}
+	sort.Slice(tree.Entries, func(i, j int) bool {
+		return sortName(tree.Entries[i]) < sortName(tree.Entries[j])
+	})
+	obj := r.Storer.NewEncodedObject()
+	require.NoError(t, tree.Encode(obj))
+	hash, err := r.Storer.SetEncodedObject(obj)
+	require.NoError(t, err)
+	return hash
+}
+
+// newTestRepository returns an in-memory repository with a chain of commits,
+// from the oldest to the newest, all of them with the same files.
+func newTestRepository(t *testing.T, now time.Time, files map[string]string, commits []testCommit) *git.Repository {
+	r, err := git.Init(memory.NewStorage(), nil)
+	require.NoError(t, err)
+
+	treeHash := writeTestTree(t, r, files)
+
+	var parents []plumbing.Hash
+	for _, c := range commits {
+		signature := object.Signature{Name: c.name, Email: c.email, When: now.Add(-c.age)}
+		commit := &object.Commit{
+			Author:       signature,
+			Committer:    signature,
+			Message:      "commit",
+			TreeHash:     treeHash,
+			ParentHashes: parents,
+		}
+		obj := r.Storer.NewEncodedObject()
+		require.NoError(t, commit.Encode(obj))
+		hash, err := r.Storer.SetEncodedObject(obj)
+		require.NoError(t, err)
+		parents = []plumbing.Hash{hash}
+	}
+	ref := plumbing.NewHashReference(plumbing.NewBranchReferenceName("master"), parents[0])
+	require.NoError(t, r.Storer.SetReference(ref))
+	return r
+}
+
+func TestWalkLogActivity(t *testing.T) {
+	now := time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	r := newTestRepository(t, now, nil, []testCommit{
+		{"alice", "alice@a.org", 1000 * day},
+		{"alice", "alice@a.org", 800 * day},
+		{"bob", "bob@b.org", 200 * day},
+		{"bob", "bob@b.org", 60 * day},
+		{"bob", "bob@b.org", 50 * day},
+		{"carol", "carol@c.org", 20 * day},
+		{"bob", "bob@b.org", 10 * day},
+		{"alice", "alice@a.org", 1 * day},
+	})
+
+	repo := NewRepo()
+	require.NoError(t, repo.walkLog(r, nil, now))
+
+	require.Equal(t, Activity{Commits: 3, Authors: 3, Orgs: 3, Maintainers: 0}, repo.Activity[Window30Days])
+	require.Equal(t, Activity{Commits: 5, Authors: 3, Orgs: 3, Maintainers: 1}, repo.Activity[Window90Days])
+	require.Equal(t, Activity{Commits: 6, Authors: 3, Orgs: 3, Maintainers: 1}, repo.Activity[Window365Days])
+	require.Equal(t, Activity{Commits: 8, Authors: 3, Orgs: 3, Maintainers: 2}, repo.Activity[WindowLifetime])
+
+	require.Equal(t, now.Add(-1000*day), repo.CreatedSince.UTC())
+	require.Equal(t, now.Add(-1*day), repo.UpdatedSince.UTC())
+	require.Equal(t, 3, repo.ContributorCount)
+	require.Equal(t, 3, repo.OrgCount)
+	require.InDelta(t, 6.0/52, repo.CommitFrequency, 1e-9)
+
+	m := &repository.GitMetric{}
+	repo.SetActivityMetrics(m)
+	require.Equal(t, 3, **m.Commits30d)
+	require.Equal(t, 1, **m.Maintainers90d)
+	require.Equal(t, 8, **m.CommitsLifetime)
+}
+
+func TestWalkLogIdentities(t *testing.T) {
+	now := time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	mailmap := "Alice Liddell <alice@redhat.com> <alice@laptop.localdomain>\n"
+	r := newTestRepository(t, now, map[string]string{".mailmap": mailmap}, []testCommit{
+		{"alice", "alice@laptop.localdomain", 40 * day},
+		{"Alice Liddell", "alice.liddell@gmail.com", 30 * day},
+		{"Alice Liddell", "alice@redhat.com", 20 * day},
+		{"bob", "12345+bob@users.noreply.github.com", 10 * day},
+		{"bob", "bob@users.noreply.github.com", 5 * day},
+		{"root", "root@localhost", 3 * day},
+		{"carol", "carol@us.ibm.com", 2 * day},
+		{"dave", "dave@ibm.com", 1 * day},
+	})
+
+	repo := NewRepo()
+	require.NoError(t, repo.walkLog(r, nil, now))
+
+	// alice, bob, root, carol and dave; redhat.com and ibm.com
+	require.Equal(t, Activity{Commits: 8, Authors: 5, Orgs: 2, Maintainers: 1}, repo.Activity[WindowLifetime])
+	require.Equal(t, 5, repo.ContributorCount)
+	require.Equal(t, 2, repo.OrgCount)
+}
@@ -0,0 +1,340 @@
+package git
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	parser "github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// checkpointVersion is bumped whenever the encoding or the meaning of a
+// Checkpoint changes, older checkpoints are then discarded.
+const checkpointVersion = 2
+
+var (
+	errCheckpointVersion = errors.New("unsupported checkpoint version")
+	errCheckpointHorizon = errors.New("checkpoint horizon is after the oldest window")
+)
+
+// Checkpoint is the state of a walk of the log of a repository: the tips of
+// the refs walked and the commits of every author reachable from them. The
+// commits after Horizon are kept one by one, so every window can be counted
+// again at a later time, older commits are only counted per month.
+//
+// A checkpoint is stored after each collection, the next collection only
+// walks the commits added since and merges them, see Repo.WalkLogFrom.
+type Checkpoint struct {
+	// Refs are the hashes of the refs walked, by ref name
+	Refs         map[string]plumbing.Hash
+	Horizon      time.Time
+	CreatedSince time.Time
+	UpdatedSince time.Time
+	// History is the history of the commits walked
+	History History
+	authors map[Identity]*authorHistory
+}
+
+type authorHistory struct {
+	// months are the commits before the horizon, by month of commit time
+	months map[string]int
+	recent []time.Time
+	last   time.Time
+}
+
+// historyHorizon returns the beginning of the oldest window ending at now,
+// commits before it are only needed for the lifetime window.
+func historyHorizon(now time.Time) time.Time {
+	ret := now.AddDate(0, -parser.BUS_FACTOR_MONTHS, 0)
+	for _, w := range Windows {
+		if since := w.Since(now); w != WindowLifetime && since.Before(ret) {
+			ret = since
+		}
+	}
+	return ret
+}
+
+func monthOf(when time.Time) string {
+	return when.UTC().Format("2006-01")
+}
+
+// NewCheckpoint returns an empty checkpoint, commits before horizon are
+// counted per month.
+func NewCheckpoint(horizon time.Time) *Checkpoint {
+	return &Checkpoint{
+		Refs:    make(map[string]plumbing.Hash),
+		Horizon: horizon,
+		authors: make(map[Identity]*authorHistory),
+	}
+}
+
+func (cp *Checkpoint) add(author Identity, when time.Time) {
+	h, ok := cp.authors[author]
+	if !ok {
+		h = &authorHistory{months: make(map[string]int)}
+		cp.authors[author] = h
+	}
+	if when.Before(cp.Horizon) {
+		h.months[monthOf(when)]++
+	} else {
+		h.recent = append(h.recent, when)
+	}
+	if when.After(h.last) {
+		h.last = when
+	}
+	if cp.CreatedSince.IsZero() || when.Before(cp.CreatedSince) {
+		cp.CreatedSince = when
+	}
+	if cp.UpdatedSince.IsZero() || when.After(cp.UpdatedSince) {
+		cp.UpdatedSince = when
+	}
+}
+
+// advance moves the horizon forward to horizon, the commits before it are
+// moved to the monthly counts.
+func (cp *Checkpoint) advance(horizon time.Time) {
+	if !horizon.After(cp.Horizon) {
+		return
+	}
+	for _, h := range cp.authors {
+		recent := h.recent[:0]
+		for _, when := range h.recent {
+			if when.Before(horizon) {
+				h.months[monthOf(when)]++
+			} else {
+				recent = append(recent, when)
+			}
+		}
+		h.recent = recent
+	}
+	cp.Horizon = horizon
+}
+
+// Empty reports whether no commit has been walked.
+func (cp *Checkpoint) Empty() bool {
+	return len(cp.authors) == 0
+}
+
+// sortedAuthors returns the authors ordered by email and name, so the
+// identities are resolved in the same order in every collection.
+func (cp *Checkpoint) sortedAuthors() []Identity {
+	ret := make([]Identity, 0, len(cp.authors))
+	for author := range cp.authors {
+		ret = append(ret, author)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].Email != ret[j].Email {
+			return ret[i].Email < ret[j].Email
+		}
+		return ret[i].Name < ret[j].Name
+	})
+	return ret
+}
+
+// each calls fn with the commits of h, the monthly counts are given at the
+// beginning of their month.
+func (h *authorHistory) each(fn func(when time.Time, commits int)) {
+	for month, commits := range h.months {
+		when, err := time.Parse("2006-01", month)
+		if err != nil {
+			continue
+		}
+		fn(when, commits)
+	}
+	for _, when := range h.recent {
+		fn(when, 1)
+	}
+}
+
+// refTips returns the hashes of the refs of r, symbolic refs are skipped as
+// they point to another ref.
+func refTips(r *git.Repository) (map[string]plumbing.Hash, error) {
+	refs, err := r.References()
+	if err != nil {
+		return nil, err
+	}
+	ret := make(map[string]plumbing.Hash)
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		if ref.Type() == plumbing.HashReference {
+			ret[ref.Name().String()] = ref.Hash()
+		}
+		return nil
+	})
+	return ret, err
+}
+
+// inLogRange reports whether a commit is counted by a walk of the log at now,
+// commits dated in the future are skipped.
+func inLogRange(c *object.Commit, now time.Time) bool {
+	return !c.Committer.When.Before(parser.BEGIN_TIME) && !c.Committer.When.After(now)
+}
+
+// fullCheckpoint walks the whole log of r.
+func fullCheckpoint(r *git.Repository, now time.Time) (*Checkpoint, error) {
+	cp := NewCheckpoint(historyHorizon(now))
+	tips, err := refTips(r)
+	if err != nil {
+		return nil, err
+	}
+	cp.Refs = tips
+
+	cIter, err := r.Log(&git.LogOptions{
+		All:   true,
+		Since: &parser.BEGIN_TIME,
+		Until: &now,
+		Order: git.LogOrderCommitterTime,
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = cIter.ForEach(func(c *object.Commit) error {
+		cp.add(Identity{Name: c.Author.Name, Email: c.Author.Email}, c.Committer.When)
+		cp.History.add(c)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return cp, nil
+}
+
+// update merges the commits of r added since the checkpoint. It returns
+// errHistoryRewritten, and leaves cp unchanged, if commits walked before are
+// no longer in the history of r.
+func (cp *Checkpoint) update(r *git.Repository, now time.Time) error {
+	horizon := historyHorizon(now)
+	if horizon.Before(cp.Horizon) {
+		return errCheckpointHorizon
+	}
+	tips, err := refTips(r)
+	if err != nil {
+		return err
+	}
+	commits, err := newCommits(r.Storer, cp.Refs, tips)
+	if err != nil {
+		return err
+	}
+
+	cp.advance(horizon)
+	for _, c := range commits {
+		if inLogRange(c, now) {
+			cp.add(Identity{Name: c.Author.Name, Email: c.Author.Email}, c.Committer.When)
+			cp.History.add(c)
+		}
+	}
+	cp.Refs = tips
+	return nil
+}
+
+type checkpointData struct {
+	Version      int                `json:"version"`
+	Refs         map[string]string  `json:"refs"`
+	Horizon      time.Time          `json:"horizon"`
+	CreatedSince time.Time          `json:"created_since"`
+	UpdatedSince time.Time          `json:"updated_since"`
+	Roots        []string           `json:"roots,omitempty"`
+	Sketch       []string           `json:"sketch,omitempty"`
+	Commits      int                `json:"commits"`
+	Authors      []checkpointAuthor `json:"authors"`
+}
+
+type checkpointAuthor struct {
+	Name   string         `json:"name"`
+	Email  string         `json:"email"`
+	Months map[string]int `json:"months,omitempty"`
+	// Recent are unix times, most of the size of a checkpoint
+	Recent []int64 `json:"recent,omitempty"`
+	Last   int64   `json:"last"`
+}
+
+// Encode returns the gzipped json encoding of cp.
+func (cp *Checkpoint) Encode() ([]byte, error) {
+	data := checkpointData{
+		Version:      checkpointVersion,
+		Refs:         make(map[string]string, len(cp.Refs)),
+		Horizon:      cp.Horizon,
+		CreatedSince: cp.CreatedSince,
+		UpdatedSince: cp.UpdatedSince,
+		Roots:        HashStrings(cp.History.Roots),
+		Sketch:       HashStrings(cp.History.Sketch),
+		Commits:      cp.History.Commits,
+		Authors:      make([]checkpointAuthor, 0, len(cp.authors)),
+	}
+	for name, hash := range cp.Refs {
+		data.Refs[name] = hash.String()
+	}
+	for _, author := range cp.sortedAuthors() {
+		h := cp.authors[author]
+		a := checkpointAuthor{
+			Name:   author.Name,
+			Email:  author.Email,
+			Months: h.months,
+			Recent: make([]int64, len(h.recent)),
+			Last:   h.last.Unix(),
+		}
+		for i, when := range h.recent {
+			a.Recent[i] = when.Unix()
+		}
+		data.Authors = append(data.Authors, a)
+	}
+
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if err := json.NewEncoder(w).Encode(&data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// DecodeCheckpoint decodes a checkpoint encoded by Checkpoint.Encode.
+func DecodeCheckpoint(b []byte) (*Checkpoint, error) {
+	r, err := gzip.NewReader(bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	var data checkpointData
+	if err := json.NewDecoder(r).Decode(&data); err != nil {
+		return nil, err
+	}
+	if data.Version != checkpointVersion {
+		return nil, fmt.Errorf("%w: %d", errCheckpointVersion, data.Version)
+	}
+
+	cp := NewCheckpoint(data.Horizon)
+	cp.CreatedSince = data.CreatedSince
+	cp.UpdatedSince = data.UpdatedSince
+	cp.History = History{
+		Roots:   ParseHashes(data.Roots),
+		Sketch:  ParseHashes(data.Sketch),
+		Commits: data.Commits,
+	}
+	for name, hash := range data.Refs {
+		cp.Refs[name] = plumbing.NewHash(hash)
+	}
+	for _, a := range data.Authors {
+		h := &authorHistory{
+			months: a.Months,
+			recent: make([]time.Time, len(a.Recent)),
+			last:   time.Unix(a.Last, 0),
+		}
+		if h.months == nil {
+			h.months = make(map[string]int)
+		}
+		for i, when := range a.Recent {
+			h.recent[i] = time.Unix(when, 0)
+		}
+		cp.authors[Identity{Name: a.Name, Email: a.Email}] = h
+	}
+	return cp, nil
+}
@@ -0,0 +1,174 @@
+package git
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"github.com/stretchr/testify/require"
+)
+
+// commitOn adds a commit on parent and points the branch to it.
+func commitOn(t *testing.T, r *git.Repository, branch string, parent plumbing.Hash, c testCommit, now time.Time) plumbing.Hash {
+	p, err := r.CommitObject(parent)
+	require.NoError(t, err)
+	signature := object.Signature{Name: c.name, Email: c.email, When: now.Add(-c.age)}
+	commit := &object.Commit{
+		Author:       signature,
+		Committer:    signature,
+		Message:      "commit",
+		TreeHash:     p.TreeHash,
+		ParentHashes: []plumbing.Hash{parent},
+	}
+	obj := r.Storer.NewEncodedObject()
+	require.NoError(t, commit.Encode(obj))
+	hash, err := r.Storer.SetEncodedObject(obj)
+	require.NoError(t, err)
+	ref := plumbing.NewHashReference(plumbing.NewBranchReferenceName(branch), hash)
+	require.NoError(t, r.Storer.SetReference(ref))
+	return hash
+}
+
+func requireSameLog(t *testing.T, want, got *Repo) {
+	require.Equal(t, want.Activity, got.Activity)
+	require.True(t, want.CreatedSince.Equal(got.CreatedSince))
+	require.True(t, want.UpdatedSince.Equal(got.UpdatedSince))
+	require.Equal(t, want.ContributorCount, got.ContributorCount)
+	require.Equal(t, want.OrgCount, got.OrgCount)
+	require.Equal(t, want.CommitFrequency, got.CommitFrequency)
+	wc, gc := want.Concentration, got.Concentration
+	require.True(t, wc.TopMaintainersLastCommit.Equal(gc.TopMaintainersLastCommit))
+	wc.TopMaintainersLastCommit, gc.TopMaintainersLastCommit = time.Time{}, time.Time{}
+	require.Equal(t, wc, gc)
+	require.Equal(t, want.Checkpoint.History, got.Checkpoint.History)
+}
+
+// walkFrom walks r from the encoded checkpoint of base, as stored between
+// two collections.
+func walkFrom(t *testing.T, r *git.Repository, base *Checkpoint, now time.Time) *Repo {
+	data, err := base.Encode()
+	require.NoError(t, err)
+	cp, err := DecodeCheckpoint(data)
+	require.NoError(t, err)
+
+	repo := NewRepo()
+	require.NoError(t, repo.walkLog(r, cp, now))
+	return &repo
+}
+
+func TestWalkLogFromCheckpoint(t *testing.T) {
+	now := time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	r := newTestRepository(t, now, nil, []testCommit{
+		{"alice", "alice@a.org", 1000 * day},
+		{"bob", "bob@b.org", 400 * day},
+		{"bob", "bob@b.org", 100 * day},
+		{"carol", "carol@c.org", 20 * day},
+	})
+	master, err := r.Reference(plumbing.NewBranchReferenceName("master"), false)
+	require.NoError(t, err)
+	tip := master.Hash()
+
+	first := NewRepo()
+	require.NoError(t, first.walkLog(r, nil, now))
+
+	// a month later, a commit on master and a branch from an old commit
+	later := now.Add(30 * day)
+	c, err := r.CommitObject(tip)
+	require.NoError(t, err)
+	old := c.ParentHashes[0]
+	commitOn(t, r, "master", tip, testCommit{"eve", "eve@e.org", 5 * day}, later)
+	commitOn(t, r, "feature", old, testCommit{"frank", "frank@f.org", 3 * day}, later)
+
+	commits, err := newCommits(r.Storer, first.Checkpoint.Refs, mustRefTips(t, r))
+	require.NoError(t, err)
+	require.Len(t, commits, 2)
+
+	full := NewRepo()
+	require.NoError(t, full.walkLog(r, nil, later))
+	incremental := walkFrom(t, r, first.Checkpoint, later)
+	requireSameLog(t, &full, incremental)
+	require.Equal(t, 6, incremental.Activity[WindowLifetime].Commits)
+
+	// nothing new a year later, the commits leave the windows
+	nextYear := later.Add(365 * day)
+	full = NewRepo()
+	require.NoError(t, full.walkLog(r, nil, nextYear))
+	requireSameLog(t, &full, walkFrom(t, r, incremental.Checkpoint, nextYear))
+}
+
+func TestWalkLogFromRewrittenHistory(t *testing.T) {
+	now := time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	r := newTestRepository(t, now, nil, []testCommit{
+		{"alice", "alice@a.org", 100 * day},
+		{"bob", "bob@b.org", 50 * day},
+		{"bob", "bob@b.org", 10 * day},
+	})
+	first := NewRepo()
+	require.NoError(t, first.walkLog(r, nil, now))
+
+	// force push of master, dropping the last commit of bob
+	master, err := r.Reference(plumbing.NewBranchReferenceName("master"), false)
+	require.NoError(t, err)
+	c, err := r.CommitObject(master.Hash())
+	require.NoError(t, err)
+	commitOn(t, r, "master", c.ParentHashes[0], testCommit{"carol", "carol@c.org", 1 * day}, now)
+
+	_, err = newCommits(r.Storer, first.Checkpoint.Refs, mustRefTips(t, r))
+	require.ErrorIs(t, err, errHistoryRewritten)
+
+	full := NewRepo()
+	require.NoError(t, full.walkLog(r, nil, now))
+	got := walkFrom(t, r, first.Checkpoint, now)
+	requireSameLog(t, &full, got)
+	require.Equal(t, 3, got.Activity[WindowLifetime].Commits)
+}
+
+func TestWalkLogFromShallowMirror(t *testing.T) {
+	now := time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	r := newTestRepository(t, now, nil, []testCommit{
+		{"alice", "alice@a.org", 100 * day},
+		{"bob", "bob@b.org", 50 * day},
+		{"bob", "bob@b.org", 10 * day},
+	})
+	first := NewRepo()
+	require.NoError(t, first.walkLog(r, nil, now))
+
+	// pruned to the tip of master, its parents are missing
+	master, err := r.Reference(plumbing.NewBranchReferenceName("master"), false)
+	require.NoError(t, err)
+	tip := master.Hash()
+	c, err := r.CommitObject(tip)
+	require.NoError(t, err)
+	objects := r.Storer.(*memory.Storage).ObjectStorage
+	for hash := c.ParentHashes[0]; !hash.IsZero(); {
+		p, err := r.CommitObject(hash)
+		require.NoError(t, err)
+		delete(objects.Objects, hash)
+		delete(objects.Commits, hash)
+		hash = plumbing.ZeroHash
+		if len(p.ParentHashes) > 0 {
+			hash = p.ParentHashes[0]
+		}
+	}
+
+	// a branch from the tip committed before it, the tip is walked first
+	commitOn(t, r, "feature", tip, testCommit{"carol", "carol@c.org", 20 * day}, now)
+	commits, err := newCommits(r.Storer, first.Checkpoint.Refs, mustRefTips(t, r))
+	require.NoError(t, err)
+	require.Len(t, commits, 1)
+
+	got := walkFrom(t, r, first.Checkpoint, now)
+	require.Equal(t, 4, got.Activity[WindowLifetime].Commits)
+}
+
+func mustRefTips(t *testing.T, r *git.Repository) map[string]plumbing.Hash {
+	tips, err := refTips(r)
+	require.NoError(t, err)
+	return tips
+}
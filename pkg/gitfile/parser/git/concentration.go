@@ -0,0 +1,120 @@
+package git
+
+import (
+	"sort"
+	"time"
+
+	parser "github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/repository"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/sqlutil"
+)
+
+// Concentration is how much the maintenance of a repository depends on few
+// contributors and organizations. Except TopMaintainersLastCommit, it is
+// computed on the commits of the last parser.BUS_FACTOR_MONTHS months.
+type Concentration struct {
+	// BusFactor50 and BusFactor80 are the minimum number of contributors
+	// authoring 50% and 80% of the commits
+	BusFactor50 int
+	BusFactor80 int
+	// CommitGini is the gini coefficient of the commits of the contributors,
+	// from 0 when every contributor has as many commits to 1
+	CommitGini float64
+	// TopOrgShare is the share of the commits authored by the organization
+	// with the most commits
+	TopOrgShare float64
+	// TopMaintainersLastCommit is the time of the last commit of any of the
+	// parser.TOP_MAINTAINERS contributors with the most commits of the
+	// whole history
+	TopMaintainersLastCommit time.Time
+}
+
+// newConcentration computes the concentration of the commits of window, and
+// of the top maintainers of lifetime. last is the time of the last commit of
+// every author.
+func newConcentration(window, lifetime *activityCounter, last map[Identity]time.Time, resolver *IdentityResolver) Concentration {
+	var ret Concentration
+
+	commits := sortedCommits(window.contributors(resolver))
+	ret.BusFactor50 = busFactor(commits, window.commits, 0.5)
+	ret.BusFactor80 = busFactor(commits, window.commits, 0.8)
+	ret.CommitGini = gini(commits)
+	if window.commits > 0 {
+		orgCommits := sortedCommits(window.orgs(resolver))
+		if len(orgCommits) > 0 {
+			ret.TopOrgShare = float64(orgCommits[0]) / float64(window.commits)
+		}
+	}
+
+	contributors := lifetime.contributors(resolver)
+	keys := make([]string, 0, len(contributors))
+	for key := range contributors {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if contributors[keys[i]] != contributors[keys[j]] {
+			return contributors[keys[i]] > contributors[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	top := make(map[string]bool)
+	for _, key := range keys[:min(parser.TOP_MAINTAINERS, len(keys))] {
+		top[key] = true
+	}
+	for author, when := range last {
+		if top[resolver.Contributor(author)] && when.After(ret.TopMaintainersLastCommit) {
+			ret.TopMaintainersLastCommit = when
+		}
+	}
+	return ret
+}
+
+// sortedCommits returns the values of commits in descending order.
+func sortedCommits(commits map[string]int) []int {
+	ret := make([]int, 0, len(commits))
+	for _, c := range commits {
+		ret = append(ret, c)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(ret)))
+	return ret
+}
+
+// busFactor returns the minimum number of contributors authoring share of
+// the total commits, commits is sorted in descending order.
+func busFactor(commits []int, total int, share float64) int {
+	covered := 0
+	for i, c := range commits {
+		covered += c
+		if float64(covered) >= share*float64(total) {
+			return i + 1
+		}
+	}
+	return len(commits)
+}
+
+// gini returns the gini coefficient of values, sorted in descending order.
+func gini(values []int) float64 {
+	n := len(values)
+	var sum, weighted float64
+	for i, v := range values {
+		sum += float64(v)
+		// rank in ascending order, from 1
+		weighted += float64(n-i) * float64(v)
+	}
+	if sum == 0 {
+		return 0
+	}
+	return 2*weighted/(float64(n)*sum) - float64(n+1)/float64(n)
+}
+
+// SetConcentrationMetrics sets the concentration columns of m.
+func (repo *Repo) SetConcentrationMetrics(m *repository.GitMetric) {
+	c := repo.Concentration
+	m.BusFactor50 = sqlutil.ToNullable(c.BusFactor50)
+	m.BusFactor80 = sqlutil.ToNullable(c.BusFactor80)
+	m.CommitGini = sqlutil.ToNullable(c.CommitGini)
+	m.TopOrgShare = sqlutil.ToNullable(c.TopOrgShare)
+	if !c.TopMaintainersLastCommit.IsZero() {
+		m.TopMaintainersLastCommit = sqlutil.ToNullable(c.TopMaintainersLastCommit)
+	}
+}
@@ -0,0 +1,59 @@
+package git
+
+import (
+	"testing"
+	"time"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/repository"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBusFactor(t *testing.T) {
+	commits := []int{5, 3, 1, 1}
+	require.Equal(t, 1, busFactor(commits, 10, 0.5))
+	require.Equal(t, 2, busFactor(commits, 10, 0.8))
+	require.Equal(t, 4, busFactor(commits, 10, 1))
+	require.Equal(t, 0, busFactor(nil, 0, 0.5))
+}
+
+func TestGini(t *testing.T) {
+	require.InDelta(t, 0, gini(nil), 1e-9)
+	require.InDelta(t, 0, gini([]int{7}), 1e-9)
+	require.InDelta(t, 0, gini([]int{2, 2, 2, 2}), 1e-9)
+	require.InDelta(t, 0.75, gini([]int{4, 0, 0, 0}), 1e-9)
+	require.InDelta(t, 1.0/3, gini([]int{4, 1, 1}), 1e-9)
+}
+
+func TestWalkLogConcentration(t *testing.T) {
+	now := time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	r := newTestRepository(t, now, nil, []testCommit{
+		{"alice", "alice@a.org", 1000 * day},
+		{"alice", "alice@a.org", 800 * day},
+		{"bob", "bob@b.org", 200 * day},
+		{"bob", "bob@b.org", 60 * day},
+		{"bob", "bob@b.org", 50 * day},
+		{"carol", "carol@c.org", 20 * day},
+		{"bob", "bob@b.org", 10 * day},
+		{"alice", "alice@a.org", 1 * day},
+		{"dave", "dave@d.org", 12 * time.Hour},
+	})
+
+	repo := NewRepo()
+	require.NoError(t, repo.walkLog(r, nil, now))
+
+	// bob 4, alice 1, carol 1 and dave 1 in the last 12 months
+	c := repo.Concentration
+	require.Equal(t, 1, c.BusFactor50)
+	require.Equal(t, 3, c.BusFactor80)
+	require.InDelta(t, 9.0/28, c.CommitGini, 1e-9)
+	require.InDelta(t, 4.0/7, c.TopOrgShare, 1e-9)
+	// bob, alice and carol are the top maintainers, not dave
+	require.Equal(t, now.Add(-1*day), c.TopMaintainersLastCommit.UTC())
+
+	m := &repository.GitMetric{}
+	repo.SetConcentrationMetrics(m)
+	require.Equal(t, 1, **m.BusFactor50)
+	require.Equal(t, 3, **m.BusFactor80)
+	require.Equal(t, now.Add(-1*day), (**m.TopMaintainersLastCommit).UTC())
+}
@@ -0,0 +1,137 @@
+package git
+
+import (
+	_ "embed"
+	"os"
+	"strings"
+
+	"golang.org/x/net/publicsuffix"
+	"gopkg.in/yaml.v3"
+)
+
+// DomainClass is the kind of an email domain.
+type DomainClass int
+
+const (
+	// DomainCorporate is the domain of an organization, e.g. redhat.com
+	DomainCorporate DomainClass = iota
+	// DomainPersonal is a free-mail provider, e.g. gmail.com
+	DomainPersonal
+	// DomainNoreply is a placeholder of a git hosting platform, e.g.
+	// users.noreply.github.com
+	DomainNoreply
+	// DomainInvalid is a local or example host, e.g. localhost
+	DomainInvalid
+)
+
+func (c DomainClass) String() string {
+	switch c {
+	case DomainCorporate:
+		return "corporate"
+	case DomainPersonal:
+		return "personal"
+	case DomainNoreply:
+		return "noreply"
+	default:
+		return "invalid"
+	}
+}
+
+//go:embed email_domains.yaml
+var bundledEmailDomains []byte
+
+// EmailDomains is a list of email domains which do not identify an
+// organization, see email_domains.yaml.
+type EmailDomains struct {
+	Personal []string `yaml:"personal"`
+	Noreply  []string `yaml:"noreply"`
+	Invalid  []string `yaml:"invalid"`
+
+	classes map[string]DomainClass
+}
+
+var emailDomains = mustParseEmailDomains(bundledEmailDomains)
+
+// ParseEmailDomains parses a list of email domains in yaml format.
+func ParseEmailDomains(data []byte) (*EmailDomains, error) {
+	var d EmailDomains
+	if err := yaml.Unmarshal(data, &d); err != nil {
+		return nil, err
+	}
+	d.classes = make(map[string]DomainClass)
+	for class, domains := range map[DomainClass][]string{
+		DomainPersonal: d.Personal,
+		DomainNoreply:  d.Noreply,
+		DomainInvalid:  d.Invalid,
+	} {
+		for _, domain := range domains {
+			d.classes[strings.ToLower(strings.TrimSpace(domain))] = class
+		}
+	}
+	return &d, nil
+}
+
+func mustParseEmailDomains(data []byte) *EmailDomains {
+	d, err := ParseEmailDomains(data)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// LoadEmailDomains replaces the bundled list of email domains with the list
+// in the file at path. It must be called before the repositories are parsed.
+func LoadEmailDomains(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	d, err := ParseEmailDomains(data)
+	if err != nil {
+		return err
+	}
+	emailDomains = d
+	return nil
+}
+
+// Classify returns the class of domain. A listed domain also classifies its
+// subdomains, and a domain without a dot is invalid.
+func (d *EmailDomains) Classify(domain string) DomainClass {
+	domain = strings.Trim(strings.ToLower(domain), ". ")
+	if !strings.Contains(domain, ".") {
+		return DomainInvalid
+	}
+	for s := domain; ; {
+		if class, ok := d.classes[s]; ok {
+			return class
+		}
+		_, parent, ok := strings.Cut(s, ".")
+		if !ok {
+			return DomainCorporate
+		}
+		s = parent
+	}
+}
+
+// Organization returns the organization of an email address, the registered
+// domain of a corporate domain, e.g. redhat.com for us.redhat.com, and false
+// for any other domain.
+func (d *EmailDomains) Organization(email string) (string, bool) {
+	domain, ok := emailDomain(email)
+	if !ok || d.Classify(domain) != DomainCorporate {
+		return "", false
+	}
+	if org, err := publicsuffix.EffectiveTLDPlusOne(domain); err == nil {
+		return org, true
+	}
+	return domain, true
+}
+
+// emailDomain returns the lower case domain of an email address.
+func emailDomain(email string) (string, bool) {
+	idx := strings.LastIndex(email, "@")
+	if idx == -1 {
+		return "", false
+	}
+	return strings.ToLower(strings.TrimSpace(email[idx+1:])), true
+}
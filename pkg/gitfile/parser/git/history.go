@@ -0,0 +1,141 @@
+package git
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// SketchSize is the number of commit hashes kept in the sketch of a history.
+const SketchSize = 256
+
+var (
+	// CopyContainment is the fraction of the commits of a history in another
+	// above which they are copies of the same project, see History.Relate
+	CopyContainment = 0.5
+	// MirrorContainment is the fraction of the commits of each history in
+	// the other above which they are mirrors
+	MirrorContainment = 0.9
+)
+
+// History identifies the commits of a repository, so that mirrors and forks
+// of the same project are found without opening both: they have the same
+// root commits and share most of their commits.
+//
+// Sketch is the bottom-k sketch of the commit hashes, the SketchSize lowest
+// ones. As hashes are uniformly distributed, the commits of a sketch are a
+// uniform sample of the history, and the sketches of two histories tell
+// what fraction of the commits of one is in the other, see Containment.
+type History struct {
+	// Roots are the commits without parents, sorted
+	Roots []plumbing.Hash
+	// Sketch is sorted
+	Sketch  []plumbing.Hash
+	Commits int
+}
+
+// HashStrings returns the hex strings of hashes, as they are stored.
+func HashStrings(hashes []plumbing.Hash) []string {
+	ret := make([]string, len(hashes))
+	for i, hash := range hashes {
+		ret[i] = hash.String()
+	}
+	return ret
+}
+
+// ParseHashes parses the hex strings of hashes returned by HashStrings.
+func ParseHashes(hashes []string) []plumbing.Hash {
+	ret := make([]plumbing.Hash, len(hashes))
+	for i, hash := range hashes {
+		ret[i] = plumbing.NewHash(hash)
+	}
+	return ret
+}
+
+func insertHash(hashes []plumbing.Hash, hash plumbing.Hash) ([]plumbing.Hash, bool) {
+	i := sort.Search(len(hashes), func(i int) bool {
+		return bytes.Compare(hashes[i][:], hash[:]) >= 0
+	})
+	if i < len(hashes) && hashes[i] == hash {
+		return hashes, false
+	}
+	hashes = append(hashes, plumbing.ZeroHash)
+	copy(hashes[i+1:], hashes[i:])
+	hashes[i] = hash
+	return hashes, true
+}
+
+// add adds a commit walked once.
+func (h *History) add(c *object.Commit) {
+	h.Commits++
+	if len(c.ParentHashes) == 0 {
+		h.Roots, _ = insertHash(h.Roots, c.Hash)
+	}
+	if len(h.Sketch) == SketchSize && bytes.Compare(c.Hash[:], h.Sketch[SketchSize-1][:]) >= 0 {
+		return
+	}
+	h.Sketch, _ = insertHash(h.Sketch, c.Hash)
+	if len(h.Sketch) > SketchSize {
+		h.Sketch = h.Sketch[:SketchSize]
+	}
+}
+
+// SharesRoot reports whether h and o have a root commit in common.
+func (h *History) SharesRoot(o *History) bool {
+	for _, root := range h.Roots {
+		i := sort.Search(len(o.Roots), func(i int) bool {
+			return bytes.Compare(o.Roots[i][:], root[:]) >= 0
+		})
+		if i < len(o.Roots) && o.Roots[i] == root {
+			return true
+		}
+	}
+	return false
+}
+
+// Containment returns the estimated fraction of the commits of h that are
+// in o, -1 if it cannot be estimated, e.g. if h is empty.
+//
+// Only the commits of the sketch of h below the highest hash of the sketch
+// of o are compared: o has such a commit if and only if it is in its sketch.
+// The whole sketch of o is the whole history of o if it is not full.
+func (h *History) Containment(o *History) float64 {
+	inRange := func(hash plumbing.Hash) bool {
+		return len(o.Sketch) < SketchSize || bytes.Compare(hash[:], o.Sketch[len(o.Sketch)-1][:]) <= 0
+	}
+	other := make(map[plumbing.Hash]bool, len(o.Sketch))
+	for _, hash := range o.Sketch {
+		other[hash] = true
+	}
+	var compared, shared int
+	for _, hash := range h.Sketch {
+		if !inRange(hash) {
+			break
+		}
+		compared++
+		if other[hash] {
+			shared++
+		}
+	}
+	if compared == 0 {
+		return -1
+	}
+	return float64(shared) / float64(compared)
+}
+
+// Relate reports whether h and o are copies of the same project: they share
+// a root commit and one has at least CopyContainment of the commits of the
+// other. Copies are mirrors if each has at least MirrorContainment of the
+// commits of the other, forks otherwise.
+func (h *History) Relate(o *History) (copies bool, mirrors bool) {
+	if !h.SharesRoot(o) {
+		return false, false
+	}
+	in, out := h.Containment(o), o.Containment(h)
+	if max(in, out) < CopyContainment {
+		return false, false
+	}
+	return true, min(in, out) >= MirrorContainment
+}
@@ -0,0 +1,71 @@
+package git
+
+import (
+	"crypto/sha1"
+	"strconv"
+	"testing"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/require"
+)
+
+// testHistory returns the history of the commits numbered from to to, the
+// first of them is a root.
+func testHistory(from, to int) *History {
+	h := &History{}
+	for i := from; i < to; i++ {
+		c := &object.Commit{Hash: plumbing.Hash(sha1.Sum([]byte(strconv.Itoa(i))))}
+		if i != from {
+			c.ParentHashes = []plumbing.Hash{plumbing.ZeroHash}
+		}
+		h.add(c)
+	}
+	return h
+}
+
+func TestHistoryContainment(t *testing.T) {
+	upstream := testHistory(0, 4000)
+	require.Equal(t, 4000, upstream.Commits)
+	require.Len(t, upstream.Sketch, SketchSize)
+	require.Len(t, upstream.Roots, 1)
+
+	// a mirror lagging behind, a fork of half of the history and a copy
+	// imported from scratch
+	mirror := testHistory(0, 3900)
+	fork := testHistory(0, 2000)
+	fork.add(&object.Commit{Hash: plumbing.Hash(sha1.Sum([]byte("fork"))), ParentHashes: []plumbing.Hash{plumbing.ZeroHash}})
+	imported := testHistory(4000, 6000)
+
+	require.True(t, mirror.SharesRoot(upstream))
+	require.True(t, fork.SharesRoot(upstream))
+	require.False(t, imported.SharesRoot(upstream))
+
+	require.InDelta(t, 1, mirror.Containment(upstream), 0.01)
+	require.InDelta(t, 0.97, upstream.Containment(mirror), 0.05)
+	require.InDelta(t, 1, fork.Containment(upstream), 0.01)
+	require.InDelta(t, 0.5, upstream.Containment(fork), 0.15)
+	require.Zero(t, imported.Containment(upstream))
+
+	// the whole history of a small repository is in its sketch
+	small := testHistory(0, 10)
+	require.Len(t, small.Sketch, 10)
+	require.Equal(t, 1.0, small.Containment(upstream))
+	require.InDelta(t, 10.0/4000, upstream.Containment(small), 0.01)
+	require.Equal(t, -1.0, (&History{}).Containment(upstream))
+
+	for _, tt := range []struct {
+		history         *History
+		copies, mirrors bool
+	}{
+		{mirror, true, true},
+		{fork, true, false},
+		{imported, false, false},
+		// a project started from the same template
+		{testHistory(0, 2), false, false},
+	} {
+		copies, mirrors := tt.history.Relate(upstream)
+		require.Equal(t, tt.copies, copies)
+		require.Equal(t, tt.mirrors, mirrors)
+	}
+}
@@ -0,0 +1,192 @@
+package git
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Identity is the name and the email address of a commit signature.
+type Identity struct {
+	Name  string
+	Email string
+}
+
+type mailmapEntry struct {
+	properName  string
+	properEmail string
+	commitName  string
+}
+
+// Mailmap maps the identities of commits to canonical identities, see
+// gitmailmap(5).
+type Mailmap struct {
+	// entries by lower case commit email
+	entries map[string][]mailmapEntry
+}
+
+// ParseMailmap parses the content of a .mailmap file, invalid lines are
+// ignored. The supported forms are:
+//
+//	Proper Name <commit@email>
+//	<proper@email> <commit@email>
+//	Proper Name <proper@email> <commit@email>
+//	Proper Name <proper@email> Commit Name <commit@email>
+func ParseMailmap(data string) *Mailmap {
+	m := &Mailmap{entries: make(map[string][]mailmapEntry)}
+	for _, line := range strings.Split(data, "\n") {
+		names, emails := parseMailmapLine(line)
+		var entry mailmapEntry
+		var commitEmail string
+		switch len(emails) {
+		case 1:
+			entry.properName = names[0]
+			commitEmail = emails[0]
+		case 2:
+			entry.properName, entry.properEmail = names[0], emails[0]
+			entry.commitName = names[1]
+			commitEmail = emails[1]
+		default:
+			continue
+		}
+		key := strings.ToLower(commitEmail)
+		m.entries[key] = append(m.entries[key], entry)
+	}
+	return m
+}
+
+// parseMailmapLine returns the names and the emails of a line, the name
+// before an email is empty if there is none.
+func parseMailmapLine(line string) ([]string, []string) {
+	names, emails := make([]string, 0, 2), make([]string, 0, 2)
+	rest := line
+	for {
+		open := strings.IndexAny(rest, "<#")
+		if open == -1 || rest[open] == '#' {
+			break
+		}
+		end := strings.Index(rest[open:], ">")
+		if end == -1 {
+			break
+		}
+		names = append(names, strings.TrimSpace(rest[:open]))
+		emails = append(emails, strings.TrimSpace(rest[open+1:open+end]))
+		rest = rest[open+end+1:]
+	}
+	return names, emails
+}
+
+// Resolve returns the canonical identity of id. An entry matching both the
+// email and the name of id is preferred to an entry matching the email only.
+func (m *Mailmap) Resolve(id Identity) Identity {
+	if m == nil {
+		return id
+	}
+	entries := m.entries[strings.ToLower(id.Email)]
+	var match *mailmapEntry
+	for i := range entries {
+		if entries[i].commitName == "" {
+			if match == nil {
+				match = &entries[i]
+			}
+		} else if strings.EqualFold(entries[i].commitName, id.Name) {
+			match = &entries[i]
+			break
+		}
+	}
+	if match == nil {
+		return id
+	}
+	if match.properName != "" {
+		id.Name = match.properName
+	}
+	if match.properEmail != "" {
+		id.Email = match.properEmail
+	}
+	return id
+}
+
+// githubNoreply matches the noreply addresses of GitHub users, with or
+// without the user id prefix.
+var githubNoreply = regexp.MustCompile(`^(?:\d+\+)?([^@]+)@users\.noreply\.github\.com$`)
+
+// IdentityResolver merges the identities of a contributor: the identities
+// mapped by the .mailmap of the repository, and the identities sharing a
+// normalized email address or a normalized full name.
+type IdentityResolver struct {
+	mailmap *Mailmap
+	domains *EmailDomains
+	// union-find forest of the keys of the identities
+	parent map[string]string
+}
+
+func NewIdentityResolver(mailmap *Mailmap, domains *EmailDomains) *IdentityResolver {
+	return &IdentityResolver{
+		mailmap: mailmap,
+		domains: domains,
+		parent:  make(map[string]string),
+	}
+}
+
+// keys returns the keys an identity is merged on. Emails of invalid
+// domains, e.g. root@localhost, and single word names are shared by unrelated
+// people, so they are not merged on.
+func (r *IdentityResolver) keys(id Identity) []string {
+	id = r.mailmap.Resolve(id)
+	ret := make([]string, 0, 2)
+
+	email := strings.ToLower(strings.TrimSpace(id.Email))
+	if match := githubNoreply.FindStringSubmatch(email); match != nil {
+		email = match[1] + "@users.noreply.github.com"
+	}
+	if domain, ok := emailDomain(email); ok && r.domains.Classify(domain) != DomainInvalid {
+		ret = append(ret, "email:"+email)
+	}
+
+	name := strings.Fields(strings.ToLower(id.Name))
+	if len(name) > 1 {
+		ret = append(ret, "name:"+strings.Join(name, " "))
+	}
+
+	if len(ret) == 0 {
+		ret = append(ret, "id:"+strings.ToLower(id.Name)+"<"+email+">")
+	}
+	return ret
+}
+
+func (r *IdentityResolver) find(key string) string {
+	for {
+		parent, ok := r.parent[key]
+		if !ok || parent == key {
+			return key
+		}
+		// path halving
+		if grandparent, ok := r.parent[parent]; ok {
+			r.parent[key] = grandparent
+		}
+		key = parent
+	}
+}
+
+// Add records id, so it is merged with the other identities of its
+// contributor.
+func (r *IdentityResolver) Add(id Identity) {
+	keys := r.keys(id)
+	root := r.find(keys[0])
+	r.parent[root] = root
+	for _, key := range keys[1:] {
+		if other := r.find(key); other != root {
+			r.parent[other] = root
+		}
+	}
+}
+
+// Contributor returns the key of the contributor of id, which is the same
+// for all the identities of the contributor once they are added.
+func (r *IdentityResolver) Contributor(id Identity) string {
+	return r.find(r.keys(id)[0])
+}
+
+// Organization returns the organization of id, see EmailDomains.Organization.
+func (r *IdentityResolver) Organization(id Identity) (string, bool) {
+	return r.domains.Organization(r.mailmap.Resolve(id).Email)
+}
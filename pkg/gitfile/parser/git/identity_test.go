@@ -0,0 +1,87 @@
+package git
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMailmap(t *testing.T) {
+	m := ParseMailmap(`# comment
+Proper Name <commit@example.org>
+<proper@redhat.com> <old@redhat.com>
+Jane Doe <jane@redhat.com> <jane@laptop>  # trailing comment
+Joe Dev <joe@redhat.com> joe <shared@host.org>
+invalid line
+`)
+
+	require.Equal(t, Identity{"Proper Name", "Commit@Example.org"},
+		m.Resolve(Identity{"whoever", "Commit@Example.org"}))
+	require.Equal(t, Identity{"old name", "proper@redhat.com"},
+		m.Resolve(Identity{"old name", "old@redhat.com"}))
+	require.Equal(t, Identity{"Jane Doe", "jane@redhat.com"},
+		m.Resolve(Identity{"jane", "jane@laptop"}))
+	require.Equal(t, Identity{"Joe Dev", "joe@redhat.com"},
+		m.Resolve(Identity{"Joe", "shared@host.org"}))
+	// the entry of shared@host.org only maps the name joe
+	require.Equal(t, Identity{"sam", "shared@host.org"},
+		m.Resolve(Identity{"sam", "shared@host.org"}))
+
+	var nilMailmap *Mailmap
+	require.Equal(t, Identity{"a", "a@b.c"}, nilMailmap.Resolve(Identity{"a", "a@b.c"}))
+}
+
+func TestIdentityResolver(t *testing.T) {
+	r := NewIdentityResolver(nil, emailDomains)
+	ids := []Identity{
+		{"Jane Doe", "jane@gmail.com"},
+		{"jane doe", "jane@redhat.com"},
+		{"jd", "JANE@redhat.com"},
+		{"root", "root@localhost"},
+		{"admin", "root@localhost"},
+		{"ci", "1+ci@users.noreply.github.com"},
+		{"ci", "ci@users.noreply.github.com"},
+	}
+	for _, id := range ids {
+		r.Add(id)
+	}
+
+	jane := r.Contributor(ids[0])
+	require.Equal(t, jane, r.Contributor(ids[1]))
+	require.Equal(t, jane, r.Contributor(ids[2]))
+	require.NotEqual(t, r.Contributor(ids[3]), r.Contributor(ids[4]))
+	require.Equal(t, r.Contributor(ids[5]), r.Contributor(ids[6]))
+	require.NotEqual(t, jane, r.Contributor(ids[5]))
+
+	org, ok := r.Organization(ids[1])
+	require.True(t, ok)
+	require.Equal(t, "redhat.com", org)
+	_, ok = r.Organization(ids[0])
+	require.False(t, ok)
+}
+
+func TestClassifyEmailDomain(t *testing.T) {
+	tests := map[string]DomainClass{
+		"redhat.com":               DomainCorporate,
+		"us.ibm.com":               DomainCorporate,
+		"gmail.com":                DomainPersonal,
+		"QQ.com":                   DomainPersonal,
+		"users.noreply.github.com": DomainNoreply,
+		"localhost":                DomainInvalid,
+		"build.localdomain":        DomainInvalid,
+		"example.com":              DomainInvalid,
+		"":                         DomainInvalid,
+	}
+	for domain, class := range tests {
+		require.Equal(t, class, emailDomains.Classify(domain), domain)
+	}
+
+	org, ok := emailDomains.Organization("carol@lab.cs.tsinghua.edu.cn")
+	require.True(t, ok)
+	require.Equal(t, "tsinghua.edu.cn", org)
+
+	d, err := ParseEmailDomains([]byte("personal: [redhat.com]\n"))
+	require.NoError(t, err)
+	require.Equal(t, DomainPersonal, d.Classify("redhat.com"))
+	require.Equal(t, DomainCorporate, d.Classify("gmail.com"))
+}
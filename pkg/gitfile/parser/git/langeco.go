@@ -1,23 +1,38 @@
 package git
 
 import (
+	"cmp"
 	"fmt"
+	"path"
 	"path/filepath"
+	"slices"
+	"strings"
 
 	parser "github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
 	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco/c/conan"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco/conda"
 	dotnet "github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco/dornet"
 	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco/dornet/nuget"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco/elixir"
 	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco/go/mod"
 	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco/go/sum"
 	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco/java/maven"
 	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco/nodejs/npm"
 	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco/nodejs/packagejson"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco/nodejs/pnpm"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco/nodejs/yarn"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco/php"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco/python/poetry"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco/python/pypi/pipenv"
 	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco/python/pypi/pyproject"
 	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco/python/pypi/requirements"
 	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco/python/pypi/setup"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco/ruby/bundler"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco/ruby/gem"
 	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco/rust/cargo"
 	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco/rust/lock"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco/swift"
 	"github.com/HUSTSecLab/OpenSift/pkg/logger"
 	"github.com/go-git/go-git/v5/plumbing/object"
 )
@@ -32,7 +47,10 @@ type LangEcoDeps struct {
 	languages    map[string]int64
 	ecosystems   map[string]int64
 	dependencies map[*langeco.Package]*langeco.Dependencies
-	config       LangEcoConfig
+	// workspaces are those declared at the root of the repository, see
+	// subproject
+	workspaces map[string]*langeco.Workspace
+	config     LangEcoConfig
 }
 
 func NewLangEcoDeps(r *Repo) LangEcoDeps {
@@ -40,14 +58,80 @@ func NewLangEcoDeps(r *Repo) LangEcoDeps {
 		languages:    map[string]int64{},
 		ecosystems:   map[string]int64{},
 		dependencies: map[*langeco.Package]*langeco.Dependencies{},
+		workspaces:   map[string]*langeco.Workspace{},
 		config: LangEcoConfig{
-			defaultName:    fmt.Sprintf("%s/%s/%s", r.Source, r.Owner, r.Name),
+			defaultName:    defaultPackageName(r),
 			defaultVersion: " ",
 			eco:            langeco.SUPPORTED_ECOS,
 		},
 	}
 }
 
+// defaultPackageName is the name of the packages of r whose manifest or lock
+// file does not name them.
+func defaultPackageName(r *Repo) string {
+	return fmt.Sprintf("%s/%s/%s", r.Source, r.Owner, r.Name)
+}
+
+// LangEcoParser parses the manifests or lock files of an ecosystem.
+type LangEcoParser struct {
+	// Pattern is matched, see path.Match, against as many trailing segments
+	// of the path of a file as it has, e.g. conda-meta/*.json
+	Pattern string
+	// Eco is the ecosystem of the packages parsed, enabled by SUPPORTED_ECOS
+	Eco   string
+	Parse func(content string) (*langeco.Package, *langeco.Dependencies, error)
+}
+
+// LangEcoParsers is the registry of the parsers of langeco, in the order
+// they are looked up.
+var LangEcoParsers = []LangEcoParser{
+	{langeco.PY_SETUP, parser.PYPI, setup.Parse},
+	{langeco.PY_PROJECT, parser.PYPI, pyproject.Parse},
+	{langeco.PY_REQUIREMENTS, parser.PYPI, requirements.Parse},
+	{langeco.PY_PIPFILE_LOCK, parser.PYPI, pipenv.Parse},
+	{langeco.POETRY_LOCK, parser.PYPI, poetry.Parse},
+	{langeco.NODEJS_PACKAGE_JSON, parser.NPM, packagejson.Parse},
+	{langeco.NPM_PACKAGE_LOCK, parser.NPM, npm.Parse},
+	{langeco.YARN_LOCK, parser.NPM, yarn.Parse},
+	{langeco.PNPM_LOCK, parser.NPM, pnpm.Parse},
+	{langeco.GO_MOD, parser.GO, mod.Parse},
+	{langeco.GO_SUM, parser.GO, sum.Parse},
+	{langeco.CARGO_TOML, parser.CARGO, cargo.Parse},
+	{langeco.CARGO_LOCK, parser.CARGO, lock.Parse},
+	{langeco.MAVEN_POM, parser.MAVEN, maven.Parse},
+	{langeco.DOT_NET, parser.DOTNET, dotnet.Parse},
+	{langeco.NUGET_CONFIG, parser.NUGET, nuget.Parse},
+	{langeco.COMPOSER_JSON, parser.COMPOSER, php.ParseManifest},
+	{langeco.COMPOSER_LOCK, parser.COMPOSER, php.Parse},
+	{langeco.GEMSPEC, parser.GEMS, gem.Parse},
+	{langeco.GEMFILE_LOCK, parser.GEMS, bundler.Parse},
+	{langeco.MIX_EXS, parser.ELIXIR, elixir.ParseManifest},
+	{langeco.MIX_LOCK, parser.ELIXIR, elixir.Parse},
+	{langeco.CONAN_RECIPE, parser.CONAN, conan.ParseRecipe},
+	{langeco.CONAN_LOCK, parser.CONAN, conan.Parse},
+	{langeco.CONDA_META, parser.CONDA, conda.ParseInstalled},
+	{langeco.CONDA_RECIPE, parser.CONDA, conda.ParseRecipe},
+	{langeco.SWIFT_RESOLVED, parser.SWIFT, swift.Parse},
+}
+
+// FindLangEcoParser returns the parser of the file at name, a slash
+// separated path, nil if there is none.
+func FindLangEcoParser(name string) *LangEcoParser {
+	segments := strings.Split(name, "/")
+	for i := range LangEcoParsers {
+		p := &LangEcoParsers[i]
+		n := strings.Count(p.Pattern, "/") + 1
+		if n > len(segments) {
+			continue
+		}
+		if ok, _ := path.Match(p.Pattern, strings.Join(segments[len(segments)-n:], "/")); ok {
+			return p
+		}
+	}
+	return nil
+}
+
 func (led *LangEcoDeps) Parse(f *object.File) error {
 	filename := filepath.Base(f.Name)
 	filesize := f.Size
@@ -63,67 +147,29 @@ func (led *LangEcoDeps) Parse(f *object.File) error {
 		}
 	}
 
-	//* Get Ecosystem and Dependency
+	//* Get Ecosystem
 	if v, ok := parser.ECOSYSTEM_MAP[filename]; ok {
 		led.ecosystems[v] += filesize
-		if t, ok := led.config.eco[v]; ok && t {
-			led.getDependencies(f)
+	}
+
+	//* Get Dependency of the subprojects
+	if p := FindLangEcoParser(f.Name); p != nil && led.subproject(f.Name, p.Eco) {
+		if t, ok := led.config.eco[p.Eco]; ok && t {
+			led.getDependencies(f, p)
 		}
 	}
 
 	return nil
 }
 
-func (led *LangEcoDeps) getDependencies(file *object.File) {
-	filename := filepath.Base(file.Name)
-
-	eco, ok := parser.ECOSYSTEM_MAP[filename]
-	if ok {
-		if v, ok := langeco.SUPPORTED_ECOS[eco]; !ok || !v {
-			return
-		}
-	} else {
-		return
-	}
-
+func (led *LangEcoDeps) getDependencies(file *object.File, p *LangEcoParser) {
 	content, err := file.Contents()
 	if err != nil {
 		logger.Error(err)
 		return
 	}
 
-	pkg := &langeco.Package{}
-	deps := &langeco.Dependencies{}
-
-	switch filename {
-	case langeco.PY_SETUP:
-		pkg, deps, err = setup.Parse(content) //* pip
-	case langeco.NODEJS_PACKAGE_JSON:
-		pkg, deps, err = packagejson.Parse(content) //* npm
-	case langeco.GO_MOD:
-		pkg, deps, err = mod.Parse(content) //* go
-	case langeco.GO_SUM:
-		pkg, deps, err = sum.Parse(content) //* go
-	case langeco.NPM_PACKAGE_LOCK:
-		pkg, deps, err = npm.Parse(content) //* npm
-	case langeco.CARGO_TOML:
-		pkg, deps, err = cargo.Parse(content) //* cargo
-	case langeco.CARGO_LOCK:
-		pkg, deps, err = lock.Parse(content) //* cargo
-	case langeco.PY_PROJECT:
-		pkg, deps, err = pyproject.Parse(content) //* pip
-	case langeco.MAVEN_POM:
-		pkg, deps, err = maven.Parse(content) //* maven
-	case langeco.PY_REQUIREMENTS:
-		pkg, deps, err = requirements.Parse(content) //* pip
-	case langeco.DOT_NET:
-		pkg, deps, err = dotnet.Parse(content) //* .NET
-	case langeco.NUGET_CONFIG:
-		pkg, deps, err = nuget.Parse(content) //* NuGet
-	default:
-		return
-	}
-
+	pkg, deps, err := p.Parse(content)
 	if err != nil {
 		logger.Error(err)
 		return
@@ -145,6 +191,47 @@ func (led *LangEcoDeps) getDependencies(file *object.File) {
 	}
 }
 
+// Packages returns the packages of EcoDeps named by their manifest or lock
+// file, and the other packages they depend on, each once per ecosystem and
+// name, sorted.
+func (repo *Repo) Packages() (packages []langeco.Package, dependencies []langeco.Package) {
+	type key struct{ eco, name string }
+	defaultName := defaultPackageName(repo)
+	declared := map[key]langeco.Package{}
+	for pkg := range repo.EcoDeps {
+		if pkg == nil || pkg.Name == "" || pkg.Name == defaultName {
+			continue
+		}
+		k := key{pkg.Eco, pkg.Name}
+		if p, ok := declared[k]; !ok || strings.TrimSpace(p.Version) == "" {
+			declared[k] = *pkg
+		}
+	}
+	depended := map[key]bool{}
+	for _, deps := range repo.EcoDeps {
+		if deps == nil {
+			continue
+		}
+		for _, dep := range *deps {
+			k := key{dep.Eco, dep.Name}
+			if _, ok := declared[k]; ok || dep.Name == "" || depended[k] {
+				continue
+			}
+			depended[k] = true
+			dependencies = append(dependencies, langeco.Package{Name: dep.Name, Eco: dep.Eco})
+		}
+	}
+	for _, pkg := range declared {
+		packages = append(packages, pkg)
+	}
+	less := func(a, b langeco.Package) int {
+		return cmp.Or(strings.Compare(a.Eco, b.Eco), strings.Compare(a.Name, b.Name))
+	}
+	slices.SortFunc(packages, less)
+	slices.SortFunc(dependencies, less)
+	return packages, dependencies
+}
+
 func (led *LangEcoDeps) Merge(r *Repo) {
 	if r.EcoDeps == nil {
 		return
@@ -6,10 +6,69 @@ import (
 	"testing"
 
 	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/collector"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
 	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco"
 	url "github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/url"
+	"github.com/stretchr/testify/require"
 )
 
+func TestFindLangEcoParser(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+	}{
+		{"go.mod", langeco.GO_MOD},
+		{"web/yarn.lock", langeco.YARN_LOCK},
+		{"pnpm-lock.yaml", langeco.PNPM_LOCK},
+		{"composer.json", langeco.COMPOSER_JSON},
+		{"rails.gemspec", langeco.GEMSPEC},
+		{"env/conda-meta/numpy-1.26.4-py312_0.json", langeco.CONDA_META},
+		{"Sources/Package.resolved", langeco.SWIFT_RESOLVED},
+		{"recipe/meta.yaml", langeco.CONDA_RECIPE},
+		{"conda-meta.json", ""},
+		{"meta.yaml", ""},
+		{"meta/numpy.json", ""},
+		{"README.md", ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p := FindLangEcoParser(test.name)
+			if test.pattern == "" {
+				require.Nil(t, p)
+				return
+			}
+			require.NotNil(t, p)
+			require.Equal(t, test.pattern, p.Pattern)
+		})
+	}
+}
+
+func TestPackages(t *testing.T) {
+	repo := NewRepo()
+	repo.Source, repo.Owner, repo.Name = "github.com", "owner", "repo"
+	repo.EcoDeps = map[*langeco.Package]*langeco.Dependencies{
+		{Name: "owner/app", Version: " ", Eco: parser.COMPOSER}: {
+			{Name: "monolog/monolog", Version: "^3.0", Eco: parser.COMPOSER},
+		},
+		{Name: "owner/app", Version: "1.2.0", Eco: parser.COMPOSER}: {
+			{Name: "monolog/monolog", Version: "3.5.0", Eco: parser.COMPOSER},
+			{Name: "owner/app", Version: "1.2.0", Eco: parser.COMPOSER},
+		},
+		{Name: "github.com/owner/repo", Version: " ", Eco: parser.GEMS}: {
+			{Name: "rack", Version: "3.0.8", Eco: parser.GEMS},
+		},
+	}
+
+	packages, dependencies := repo.Packages()
+	require.Equal(t, []langeco.Package{
+		{Name: "owner/app", Version: "1.2.0", Eco: parser.COMPOSER},
+	}, packages)
+	require.Equal(t, []langeco.Package{
+		{Name: "monolog/monolog", Eco: parser.COMPOSER},
+		{Name: "rack", Eco: parser.GEMS},
+	}, dependencies)
+}
+
 func TestEco(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -0,0 +1,454 @@
+package git
+
+import (
+	"errors"
+	"io"
+	"math"
+	"path"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	parser "github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
+	"github.com/HUSTSecLab/OpenSift/pkg/logger"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/repository"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/sqlutil"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/google/licensecheck"
+	"github.com/lib/pq"
+)
+
+// NoAssertion is the expression of the files whose license is unknown.
+const NoAssertion = "NOASSERTION"
+
+// families of licenses, see LicenseFamily
+const (
+	LicensePublicDomain = "public-domain"
+	LicensePermissive   = "permissive"
+	LicenseWeakCopyleft = "weak-copyleft"
+	LicenseCopyleft     = "copyleft"
+	LicenseOther        = "other"
+)
+
+var (
+	// LICENSE, COPYING.LESSER, UNLICENSE, LICENSE-MIT, MIT-LICENSE.txt, ...
+	licenseFileName = regexp.MustCompile(`(?i)^(?:(?:un)?licen[cs]e|copying)(?:[-._].*)?$|[-._](?:licen[cs]e|copying)(?:\.[a-z]+)?$`)
+	spdxHeader      = regexp.MustCompile(`SPDX-License-Identifier:[ \t]*([^\r\n]*)`)
+	spdxExpression  = regexp.MustCompile(`^[A-Za-z0-9.+:()\- ]+$`)
+	spdxToken       = regexp.MustCompile(`[A-Za-z0-9.+:\-]+`)
+	// ends of the comments an SPDX header may be written in
+	commentClosers = []string{"*/", "-->", "*)", "#}", "--}}"}
+)
+
+// licenseFamilies are the families of the license ids starting with a
+// prefix, the first matching prefix wins.
+var licenseFamilies = []struct {
+	prefix string
+	family string
+}{
+	{"AGPL-", LicenseCopyleft},
+	{"LGPL", LicenseWeakCopyleft},
+	{"GPL-", LicenseCopyleft},
+	{"GFDL-", LicenseCopyleft},
+	{"EUPL-", LicenseCopyleft},
+	{"OSL-", LicenseCopyleft},
+	{"SSPL-", LicenseCopyleft},
+	{"CC-BY-SA-", LicenseCopyleft},
+	{"CC-BY-NC", LicenseOther},
+	{"CC-BY-ND", LicenseOther},
+	{"CC-BY-", LicensePermissive},
+	{"MPL-", LicenseWeakCopyleft},
+	{"EPL-", LicenseWeakCopyleft},
+	{"CDDL-", LicenseWeakCopyleft},
+	{"CPL-", LicenseWeakCopyleft},
+	{"MS-RL", LicenseWeakCopyleft},
+	{"CC0-", LicensePublicDomain},
+	{"Unlicense", LicensePublicDomain},
+	{"WTFPL", LicensePublicDomain},
+	{"CC-PDDC", LicensePublicDomain},
+	{"0BSD", LicensePermissive},
+	{"BSD-", LicensePermissive},
+	{"MIT", LicensePermissive},
+	{"Apache-", LicensePermissive},
+	{"ISC", LicensePermissive},
+	{"Zlib", LicensePermissive},
+	{"BSL-", LicensePermissive},
+	{"PostgreSQL", LicensePermissive},
+	{"Python-", LicensePermissive},
+	{"PSF-", LicensePermissive},
+	{"X11", LicensePermissive},
+	{"NCSA", LicensePermissive},
+	{"Unicode-", LicensePermissive},
+	{"Artistic-2.0", LicensePermissive},
+	{"OpenSSL", LicensePermissive},
+	{"libpng", LicensePermissive},
+	{"BlueOak-", LicensePermissive},
+	{"MS-PL", LicensePermissive},
+}
+
+// LicenseFamily returns the family of an SPDX license id, LicenseOther if
+// it is unknown.
+func LicenseFamily(id string) string {
+	id = strings.ToLower(id)
+	for _, f := range licenseFamilies {
+		if strings.HasPrefix(id, strings.ToLower(f.prefix)) {
+			return f.family
+		}
+	}
+	return LicenseOther
+}
+
+// LicenseFile is a license file of a repository.
+type LicenseFile struct {
+	Path string
+	// Dir is the directory the file applies to, "" for the root. The files
+	// of a LICENSES directory apply to its parent.
+	Dir string
+	// IDs are the SPDX ids of the licenses matched in the file
+	IDs []string
+	// Percent is the percentage of the file covered by known licenses
+	Percent float64
+}
+
+// Licenses is the license inventory of a repository. Every source file is
+// under the expression of its SPDX-License-Identifier header or else under
+// the license files of its nearest directory having any: the licenses of a
+// file are combined with AND, and the files, e.g. LICENSE-MIT and
+// LICENSE-APACHE, with OR as the licenses offered to choose from. The files
+// without either are under NoAssertion.
+type Licenses struct {
+	Files []LicenseFile
+	// Sources is the number of source files
+	Sources int
+	// Headers is the number of source files with an SPDX header
+	Headers int
+	// Expression is the SPDX expression of the repository, the expressions
+	// of its files by descending coverage, combined with AND. It is empty if
+	// no license is found.
+	Expression string
+	// Coverage is the percentage of the source files under each expression,
+	// or of the license files if there is no source file
+	Coverage map[string]float64
+	// IDs are the SPDX ids of the licenses found, the ids of Expression
+	// first
+	IDs []string
+	// Families are the families of the licenses found
+	Families []string
+}
+
+// inLicensesDir reports whether the file at p is in a LICENSES directory,
+// where the texts of the licenses used in a repository are named by their id.
+func inLicensesDir(p string) bool {
+	return strings.EqualFold(path.Base(path.Dir(p)), "LICENSES")
+}
+
+// IsLicenseFile reports whether the file at p holds a license text, e.g.
+// LICENSE, COPYING, LICENSE-MIT or any file of a LICENSES directory.
+func IsLicenseFile(p string) bool {
+	if inLicensesDir(p) {
+		return true
+	}
+	name := path.Base(p)
+	if !licenseFileName.MatchString(name) {
+		return false
+	}
+	// license.go or license_test.py are sources
+	return !isSourceFile(name)
+}
+
+// isSourceFile reports whether the file at p is written in a programming
+// language.
+func isSourceFile(p string) bool {
+	lang, ok := parser.LANGUAGE_EXTENSIONS[path.Ext(p)]
+	return ok && !parser.NON_SOURCE_LANGUAGES[lang]
+}
+
+// licenseDir returns the directory the license file at p applies to.
+func licenseDir(p string) string {
+	dir := path.Dir(p)
+	if inLicensesDir(p) {
+		dir = path.Dir(dir)
+	}
+	if dir == "." {
+		return ""
+	}
+	return dir
+}
+
+// ScanLicenseFile returns the SPDX ids of the licenses matched in f, in the
+// order they appear, and the percentage of f they cover.
+func ScanLicenseFile(f *object.File) ([]string, float64, error) {
+	text, err := f.Contents()
+	if err != nil {
+		return nil, 0, err
+	}
+	cov := licensecheck.Scan([]byte(text))
+	var ids []string
+	for _, m := range cov.Match {
+		if !slices.Contains(ids, m.ID) {
+			ids = append(ids, m.ID)
+		}
+	}
+	return ids, cov.Percent, nil
+}
+
+// ParseSPDXHeader returns the expression of the first SPDX-License-Identifier
+// header of text, "" if there is none or it is malformed.
+func ParseSPDXHeader(text string) string {
+	match := spdxHeader.FindStringSubmatch(text)
+	if match == nil {
+		return ""
+	}
+	expr := match[1]
+	for _, closer := range commentClosers {
+		if i := strings.Index(expr, closer); i >= 0 {
+			expr = expr[:i]
+		}
+	}
+	expr = strings.Join(strings.Fields(expr), " ")
+	if !spdxExpression.MatchString(expr) {
+		return ""
+	}
+	return expr
+}
+
+// readHeader returns the first LICENSE_HEADER_BYTES bytes of f.
+func readHeader(f *object.File) (string, error) {
+	r, err := f.Reader()
+	if err != nil {
+		return "", err
+	}
+	defer r.Close()
+	buf := make([]byte, parser.LICENSE_HEADER_BYTES)
+	n, err := io.ReadFull(r, buf)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return "", err
+	}
+	return string(buf[:n]), nil
+}
+
+// licenseIDs returns the license ids of an SPDX expression, without the
+// exceptions.
+func licenseIDs(expr string) []string {
+	var ret []string
+	// the id following WITH is an exception, e.g. Linux-syscall-note
+	exception := false
+	for _, token := range spdxToken.FindAllString(expr, -1) {
+		switch strings.ToUpper(token) {
+		case "AND", "OR":
+			continue
+		case "WITH":
+			exception = true
+			continue
+		}
+		if exception {
+			exception = false
+			continue
+		}
+		if token != NoAssertion && !slices.Contains(ret, token) {
+			ret = append(ret, token)
+		}
+	}
+	return ret
+}
+
+// andExpression combines ids with AND in a stable order.
+func andExpression(ids []string) string {
+	if len(ids) == 0 {
+		return NoAssertion
+	}
+	sorted := append([]string(nil), ids...)
+	sort.Strings(sorted)
+	return strings.Join(sorted, " AND ")
+}
+
+// orExpression combines exprs with OR in a stable order.
+func orExpression(exprs []string) string {
+	if len(exprs) == 1 {
+		return exprs[0]
+	}
+	sorted := make([]string, len(exprs))
+	for i, expr := range exprs {
+		if strings.Contains(expr, " ") {
+			expr = "(" + expr + ")"
+		}
+		sorted[i] = expr
+	}
+	sort.Strings(sorted)
+	return strings.Join(sorted, " OR ")
+}
+
+type licenseSource struct {
+	dir    string
+	header string
+}
+
+// licenseScanner collects the license files and the SPDX headers of the
+// files of a tree.
+type licenseScanner struct {
+	files []LicenseFile
+	// dirs are the expressions of the license files of each directory
+	dirs    map[string][]string
+	sources []licenseSource
+}
+
+func newLicenseScanner() *licenseScanner {
+	return &licenseScanner{dirs: make(map[string][]string)}
+}
+
+func (ls *licenseScanner) Parse(f *object.File) {
+	if IsLicenseFile(f.Name) {
+		ids, percent, err := ScanLicenseFile(f)
+		if err != nil {
+			logger.Error(err)
+			return
+		}
+		ls.addFile(f.Name, ids, percent)
+	} else if isSourceFile(f.Name) {
+		header, err := readHeader(f)
+		if err != nil {
+			logger.Error(err)
+		}
+		ls.addSource(f.Name, ParseSPDXHeader(header))
+	}
+}
+
+func (ls *licenseScanner) addFile(p string, ids []string, percent float64) {
+	dir := licenseDir(p)
+	if len(ids) == 0 && inLicensesDir(p) {
+		ids = []string{strings.TrimSuffix(path.Base(p), path.Ext(p))}
+	}
+	ls.files = append(ls.files, LicenseFile{Path: p, Dir: dir, IDs: ids, Percent: percent})
+	if expr := andExpression(ids); len(ids) > 0 && !slices.Contains(ls.dirs[dir], expr) {
+		ls.dirs[dir] = append(ls.dirs[dir], expr)
+	}
+}
+
+func (ls *licenseScanner) addSource(p string, header string) {
+	dir := path.Dir(p)
+	if dir == "." {
+		dir = ""
+	}
+	ls.sources = append(ls.sources, licenseSource{dir: dir, header: header})
+}
+
+// dirExpression returns the expression of the license files of the nearest
+// directory of dir having any.
+func (ls *licenseScanner) dirExpression(dir string) string {
+	for {
+		if exprs := ls.dirs[dir]; len(exprs) > 0 {
+			return orExpression(exprs)
+		}
+		if dir == "" {
+			return NoAssertion
+		}
+		dir = path.Dir(dir)
+		if dir == "." {
+			dir = ""
+		}
+	}
+}
+
+func (ls *licenseScanner) Licenses() Licenses {
+	ret := Licenses{Files: ls.files, Sources: len(ls.sources)}
+
+	var units []string
+	for _, s := range ls.sources {
+		if s.header != "" {
+			ret.Headers++
+			units = append(units, s.header)
+		} else {
+			units = append(units, ls.dirExpression(s.dir))
+		}
+	}
+	if len(units) == 0 {
+		for _, f := range ls.files {
+			units = append(units, andExpression(f.IDs))
+		}
+	}
+	if len(units) == 0 {
+		return ret
+	}
+
+	counts := make(map[string]int)
+	for _, expr := range units {
+		counts[expr]++
+	}
+	ret.Coverage = make(map[string]float64, len(counts))
+	exprs := make([]string, 0, len(counts))
+	for expr, count := range counts {
+		ret.Coverage[expr] = math.Round(float64(count)/float64(len(units))*10000) / 100
+		if expr != NoAssertion {
+			exprs = append(exprs, expr)
+		}
+	}
+	sort.Slice(exprs, func(i, j int) bool {
+		if counts[exprs[i]] != counts[exprs[j]] {
+			return counts[exprs[i]] > counts[exprs[j]]
+		}
+		return exprs[i] < exprs[j]
+	})
+	if len(exprs) == 1 {
+		ret.Expression = exprs[0]
+	} else if len(exprs) > 1 {
+		parts := make([]string, len(exprs))
+		for i, expr := range exprs {
+			if strings.Contains(expr, " ") {
+				expr = "(" + expr + ")"
+			}
+			parts[i] = expr
+		}
+		ret.Expression = strings.Join(parts, " AND ")
+	}
+
+	ret.IDs = ls.inventory(exprs)
+	for _, id := range ret.IDs {
+		family := LicenseFamily(id)
+		if !slices.Contains(ret.Families, family) {
+			ret.Families = append(ret.Families, family)
+		}
+	}
+	sort.Strings(ret.Families)
+	return ret
+}
+
+// inventory returns the ids of exprs in order, followed by the ids of the
+// other license files in sorted order.
+func (ls *licenseScanner) inventory(exprs []string) []string {
+	var ret []string
+	for _, expr := range exprs {
+		for _, id := range licenseIDs(expr) {
+			if !slices.Contains(ret, id) {
+				ret = append(ret, id)
+			}
+		}
+	}
+	var others []string
+	for _, f := range ls.files {
+		for _, id := range f.IDs {
+			if !slices.Contains(ret, id) && !slices.Contains(others, id) {
+				others = append(others, id)
+			}
+		}
+	}
+	sort.Strings(others)
+	return append(ret, others...)
+}
+
+// SetLicenseMetrics sets the license columns of m.
+func (repo *Repo) SetLicenseMetrics(m *repository.GitMetric) {
+	l := repo.Licenses
+	if len(repo.License) > 0 {
+		m.License = sqlutil.ToNullable(pq.StringArray(repo.License))
+	}
+	if l.Expression != "" {
+		m.LicenseExpression = sqlutil.ToNullable(l.Expression)
+	}
+	if len(l.Coverage) > 0 {
+		m.LicenseCoverage = sqlutil.ToNullable(repository.LicenseCoverage(l.Coverage))
+	}
+	if len(l.Families) > 0 {
+		m.LicenseFamilies = sqlutil.ToNullable(pq.StringArray(l.Families))
+	}
+}
@@ -0,0 +1,176 @@
+package git
+
+import (
+	"testing"
+	"time"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/repository"
+	"github.com/stretchr/testify/require"
+)
+
+const mitLicense = `MIT License
+
+Copyright (c) 2024 Example
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+`
+
+func TestIsLicenseFile(t *testing.T) {
+	tests := []struct {
+		path    string
+		license bool
+	}{
+		{"LICENSE", true},
+		{"LICENSE.md", true},
+		{"licence.txt", true},
+		{"COPYING", true},
+		{"COPYING.LESSER", true},
+		{"UNLICENSE", true},
+		{"LICENSE-MIT", true},
+		{"MIT-LICENSE.txt", true},
+		{"packages/foo/LICENSE", true},
+		{"LICENSES/Apache-2.0.txt", true},
+		{"license.go", false},
+		{"pkg/license/license_test.py", false},
+		{"README.md", false},
+		{"licenses.json", false},
+	}
+	for _, tt := range tests {
+		require.Equal(t, tt.license, IsLicenseFile(tt.path), tt.path)
+	}
+}
+
+func TestParseSPDXHeader(t *testing.T) {
+	tests := []struct {
+		text string
+		expr string
+	}{
+		{"// SPDX-License-Identifier: MIT\npackage main\n", "MIT"},
+		{"/* SPDX-License-Identifier: GPL-2.0 WITH Linux-syscall-note */\n", "GPL-2.0 WITH Linux-syscall-note"},
+		{"#!/bin/sh\n# SPDX-License-Identifier:  Apache-2.0 OR  MIT\r\n", "Apache-2.0 OR MIT"},
+		{"<!-- SPDX-License-Identifier: (MIT OR BSD-3-Clause) -->", "(MIT OR BSD-3-Clause)"},
+		{"// SPDX-License-Identifier: see LICENSE file!", ""},
+		{"package main\n", ""},
+	}
+	for _, tt := range tests {
+		require.Equal(t, tt.expr, ParseSPDXHeader(tt.text), tt.text)
+	}
+}
+
+func TestLicenseFamily(t *testing.T) {
+	require.Equal(t, LicensePermissive, LicenseFamily("MIT"))
+	require.Equal(t, LicensePermissive, LicenseFamily("Apache-2.0"))
+	require.Equal(t, LicenseWeakCopyleft, LicenseFamily("LGPL-2.1-or-later"))
+	require.Equal(t, LicenseWeakCopyleft, LicenseFamily("MPL-2.0"))
+	require.Equal(t, LicenseCopyleft, LicenseFamily("GPL-2.0"))
+	require.Equal(t, LicenseCopyleft, LicenseFamily("agpl-3.0"))
+	require.Equal(t, LicensePublicDomain, LicenseFamily("CC0-1.0"))
+	require.Equal(t, LicenseOther, LicenseFamily("LicenseRef-proprietary"))
+}
+
+func TestLicenseScanner(t *testing.T) {
+	ls := newLicenseScanner()
+	ls.addFile("LICENSE", []string{"MIT"}, 100)
+	ls.addFile("packages/legacy/COPYING", []string{"GPL-2.0"}, 100)
+	ls.addFile("packages/dual/LICENSES/Apache-2.0.txt", nil, 0)
+	ls.addFile("packages/dual/LICENSES/MIT.txt", []string{"MIT"}, 100)
+	ls.addSource("main.go", "")
+	ls.addSource("cmd/tool/main.go", "")
+	ls.addSource("packages/legacy/a.c", "")
+	ls.addSource("packages/legacy/sub/b.c", "")
+	ls.addSource("packages/legacy/c.c", "GPL-2.0 WITH Linux-syscall-note")
+	ls.addSource("packages/dual/d.go", "")
+	ls.addSource("third_party/e.go", "LicenseRef-vendor")
+	ls.addSource("third_party/f.go", "")
+
+	l := ls.Licenses()
+	require.Equal(t, 8, l.Sources)
+	require.Equal(t, 2, l.Headers)
+	require.Equal(t, "packages/dual", l.Files[2].Dir)
+	require.Equal(t, []string{"Apache-2.0"}, l.Files[2].IDs)
+	require.Equal(t, map[string]float64{
+		"MIT":                             37.5,
+		"GPL-2.0":                         25,
+		"GPL-2.0 WITH Linux-syscall-note": 12.5,
+		"Apache-2.0 OR MIT":               12.5,
+		"LicenseRef-vendor":               12.5,
+	}, l.Coverage)
+	require.Equal(t, "MIT AND GPL-2.0 AND (Apache-2.0 OR MIT) AND (GPL-2.0 WITH Linux-syscall-note) AND LicenseRef-vendor", l.Expression)
+	require.Equal(t, []string{"MIT", "GPL-2.0", "Apache-2.0", "LicenseRef-vendor"}, l.IDs)
+	require.Equal(t, []string{LicenseCopyleft, LicenseOther, LicensePermissive}, l.Families)
+
+	// the licenses of a file apply together, the files are alternatives
+	ls = newLicenseScanner()
+	ls.addFile("LICENSE-MIT", []string{"MIT"}, 100)
+	ls.addFile("LICENSE-APACHE", []string{"Apache-2.0"}, 100)
+	ls.addFile("lib/COPYING", []string{"MIT", "BSD-3-Clause"}, 100)
+	ls.addFile("lib/COPYING.LESSER", []string{"LGPL-2.1"}, 100)
+	ls.addSource("main.rs", "")
+	ls.addSource("lib/lib.c", "")
+	l = ls.Licenses()
+	require.Equal(t, map[string]float64{
+		"Apache-2.0 OR MIT":                  50,
+		"(BSD-3-Clause AND MIT) OR LGPL-2.1": 50,
+	}, l.Coverage)
+
+	// without any license file, the files without header are not asserted
+	ls = newLicenseScanner()
+	ls.addSource("a.go", "Apache-2.0")
+	ls.addSource("b.go", "")
+	l = ls.Licenses()
+	require.Equal(t, map[string]float64{"Apache-2.0": 50, NoAssertion: 50}, l.Coverage)
+	require.Equal(t, "Apache-2.0", l.Expression)
+
+	// without any source file, the license files are counted
+	ls = newLicenseScanner()
+	ls.addFile("LICENSE", []string{"CC-BY-4.0"}, 100)
+	l = ls.Licenses()
+	require.Equal(t, map[string]float64{"CC-BY-4.0": 100}, l.Coverage)
+	require.Equal(t, "CC-BY-4.0", l.Expression)
+
+	require.Empty(t, newLicenseScanner().Licenses().Expression)
+}
+
+func TestWalkRepoLicenses(t *testing.T) {
+	now := time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)
+	r := newTestRepository(t, now, map[string]string{
+		"LICENSE":   mitLicense,
+		"main.go":   "package main\n",
+		"util.go":   "package main\n",
+		"vendor.go": "// SPDX-License-Identifier: BSD-3-Clause\npackage main\n",
+		"README.md": "# example\n",
+	}, []testCommit{{"alice", "alice@a.org", time.Hour}})
+
+	repo := NewRepo()
+	require.NoError(t, repo.WalkRepo(r))
+	l := repo.Licenses
+	require.Len(t, l.Files, 1)
+	require.Equal(t, []string{"MIT"}, l.Files[0].IDs)
+	require.Equal(t, 3, l.Sources)
+	require.Equal(t, 1, l.Headers)
+	require.Equal(t, "MIT AND BSD-3-Clause", l.Expression)
+	require.Equal(t, []string{"MIT", "BSD-3-Clause"}, repo.License)
+	require.Equal(t, []string{LicensePermissive}, l.Families)
+
+	m := &repository.GitMetric{}
+	repo.SetLicenseMetrics(m)
+	require.Equal(t, "MIT AND BSD-3-Clause", **m.LicenseExpression)
+	require.InDelta(t, 66.67, (**m.LicenseCoverage)["MIT"], 1e-9)
+	require.Equal(t, []string{LicensePermissive}, []string(**m.LicenseFamilies))
+}
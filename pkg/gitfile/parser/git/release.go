@@ -0,0 +1,154 @@
+package git
+
+import (
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/repository"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/sqlutil"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+var (
+	// a version with an optional v and an optional prefix, e.g. v1.2.3,
+	// release-1.2, go1.21.0 or pkg/1.2.3-rc1
+	semverTag = regexp.MustCompile(`^(?:[A-Za-z][\w./-]*?[-_/]?)?[vV]?\d+\.\d+(?:\.\d+)?(?:[-+.]?([0-9A-Za-z][0-9A-Za-z.+-]*))?$`)
+	// suffixes of the versions not released to users
+	preRelease = regexp.MustCompile(`(?i)(alpha|beta|rc|pre|dev|preview|snapshot|nightly)`)
+)
+
+// IsSemverTag reports whether a tag name looks like a version, and whether
+// it is a pre-release, e.g. v1.2.0-rc1.
+func IsSemverTag(name string) (semver bool, pre bool) {
+	match := semverTag.FindStringSubmatch(name)
+	if match == nil {
+		return false, false
+	}
+	return true, preRelease.MatchString(match[1])
+}
+
+// Releases are the releases of a repository found from its tags, so no
+// platform api is needed. A release is a commit tagged with a version which
+// is not a pre-release, dated by the tagger of an annotated tag or by the
+// committer of the commit. Commits tagged more than once are one release.
+type Releases struct {
+	// SemverTags is the number of tags looking like a version, including
+	// pre-releases
+	SemverTags int
+	// Count is the number of releases
+	Count int
+	// LastYear is the number of releases of the last 365 days
+	LastYear int
+	// Last is the time of the last release, zero if there is none
+	Last time.Time
+	// MedianInterval is the median number of days between two consecutive
+	// releases, 0 if there are less than two releases
+	MedianInterval float64
+}
+
+func (repo *Repo) WalkTags(r *git.Repository) error {
+	return repo.walkTags(r, time.Now())
+}
+
+// walkTags finds the releases of r before now.
+func (repo *Repo) walkTags(r *git.Repository, now time.Time) error {
+	tags, err := r.Tags()
+	if err != nil {
+		return err
+	}
+
+	var ret Releases
+	dates := make(map[plumbing.Hash]time.Time)
+	err = tags.ForEach(func(ref *plumbing.Reference) error {
+		semver, pre := IsSemverTag(ref.Name().Short())
+		if !semver {
+			return nil
+		}
+		ret.SemverTags++
+		if pre {
+			return nil
+		}
+
+		var commit plumbing.Hash
+		var when time.Time
+		if tag, err := r.TagObject(ref.Hash()); err == nil {
+			c, err := tag.Commit()
+			if err != nil {
+				// a tag of a tree or a blob
+				return nil
+			}
+			commit, when = c.Hash, tag.Tagger.When
+		} else if c, err := r.CommitObject(ref.Hash()); err == nil {
+			commit, when = c.Hash, c.Committer.When
+		} else {
+			return nil
+		}
+		if when.After(now) {
+			return nil
+		}
+		if old, ok := dates[commit]; !ok || when.Before(old) {
+			dates[commit] = when
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	releases := make([]time.Time, 0, len(dates))
+	for _, when := range dates {
+		releases = append(releases, when)
+	}
+	sort.Slice(releases, func(i, j int) bool {
+		return releases[i].Before(releases[j])
+	})
+
+	ret.Count = len(releases)
+	lastYear := Window365Days.Since(now)
+	for _, when := range releases {
+		if !when.Before(lastYear) {
+			ret.LastYear++
+		}
+	}
+	if len(releases) > 0 {
+		ret.Last = releases[len(releases)-1]
+	}
+	ret.MedianInterval = medianInterval(releases)
+
+	repo.Releases = ret
+	return nil
+}
+
+// medianInterval returns the median number of days between two consecutive
+// times of releases, sorted in ascending order.
+func medianInterval(releases []time.Time) float64 {
+	if len(releases) < 2 {
+		return 0
+	}
+	intervals := make([]float64, 0, len(releases)-1)
+	for i := 1; i < len(releases); i++ {
+		intervals = append(intervals, releases[i].Sub(releases[i-1]).Hours()/24)
+	}
+	sort.Float64s(intervals)
+	n := len(intervals)
+	if n%2 == 1 {
+		return intervals[n/2]
+	}
+	return (intervals[n/2-1] + intervals[n/2]) / 2
+}
+
+// SetReleaseMetrics sets the release columns of m, the last release and the
+// median interval are left unset when unknown.
+func (repo *Repo) SetReleaseMetrics(m *repository.GitMetric) {
+	rel := repo.Releases
+	m.SemverTags = sqlutil.ToNullable(rel.SemverTags)
+	m.Releases365d = sqlutil.ToNullable(rel.LastYear)
+	if rel.Count > 0 {
+		m.LastRelease = sqlutil.ToNullable(rel.Last)
+	}
+	if rel.Count > 1 {
+		m.MedianReleaseInterval = sqlutil.ToNullable(rel.MedianInterval)
+	}
+}
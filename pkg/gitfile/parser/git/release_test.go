@@ -0,0 +1,115 @@
+package git
+
+import (
+	"testing"
+	"time"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/repository"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsSemverTag(t *testing.T) {
+	tests := []struct {
+		name   string
+		semver bool
+		pre    bool
+	}{
+		{"v1.2.3", true, false},
+		{"1.2", true, false},
+		{"release-1.2.0", true, false},
+		{"go1.21.0", true, false},
+		{"pkg/v0.3.1", true, false},
+		{"v2.0.0-rc1", true, true},
+		{"1.0.0-beta.2", true, true},
+		{"v3.1.0+build5", true, false},
+		{"nightly", false, false},
+		{"v1", false, false},
+		{"20230105", false, false},
+	}
+	for _, tt := range tests {
+		semver, pre := IsSemverTag(tt.name)
+		require.Equal(t, tt.semver, semver, tt.name)
+		require.Equal(t, tt.pre, pre, tt.name)
+	}
+}
+
+func TestWalkTags(t *testing.T) {
+	now := time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	r := newTestRepository(t, now, nil, []testCommit{
+		{"alice", "alice@a.org", 700 * day},
+		{"alice", "alice@a.org", 300 * day},
+		{"alice", "alice@a.org", 200 * day},
+		{"alice", "alice@a.org", 100 * day},
+		{"alice", "alice@a.org", 10 * day},
+	})
+	commits := logHashes(t, r)
+
+	// commits are listed from the newest
+	lightweightTag(t, r, "v1.0.0", commits[4])
+	annotatedTag(t, r, "v1.1.0", commits[3], now.Add(-290*day))
+	lightweightTag(t, r, "1.1.0", commits[3])
+	lightweightTag(t, r, "v1.2.0-rc1", commits[2])
+	lightweightTag(t, r, "v1.2.0", commits[1])
+	lightweightTag(t, r, "before-refactor", commits[0])
+
+	repo := NewRepo()
+	require.NoError(t, repo.walkTags(r, now))
+	rel := repo.Releases
+	require.Equal(t, 5, rel.SemverTags)
+	require.Equal(t, 3, rel.Count)
+	require.Equal(t, 2, rel.LastYear)
+	require.Equal(t, now.Add(-100*day), rel.Last.UTC())
+	// 400 days from v1.0.0 to v1.1.0, dated by its lightweight tag, then
+	// 200 days to v1.2.0
+	require.InDelta(t, 300, rel.MedianInterval, 1e-9)
+
+	m := &repository.GitMetric{}
+	repo.SetReleaseMetrics(m)
+	require.Equal(t, 2, **m.Releases365d)
+	require.Equal(t, rel.Last, **m.LastRelease)
+
+	repo = NewRepo()
+	require.NoError(t, repo.walkTags(newTestRepository(t, now, nil, []testCommit{{"bob", "bob@b.org", day}}), now))
+	m = &repository.GitMetric{}
+	repo.SetReleaseMetrics(m)
+	require.Equal(t, 0, **m.SemverTags)
+	require.Nil(t, m.LastRelease)
+	require.Nil(t, m.MedianReleaseInterval)
+}
+
+// logHashes returns the commits of master, from the newest.
+func logHashes(t *testing.T, r *git.Repository) []plumbing.Hash {
+	ref, err := r.Reference(plumbing.NewBranchReferenceName("master"), false)
+	require.NoError(t, err)
+	iter, err := r.Log(&git.LogOptions{From: ref.Hash()})
+	require.NoError(t, err)
+	ret := make([]plumbing.Hash, 0)
+	require.NoError(t, iter.ForEach(func(c *object.Commit) error {
+		ret = append(ret, c.Hash)
+		return nil
+	}))
+	return ret
+}
+
+func lightweightTag(t *testing.T, r *git.Repository, name string, commit plumbing.Hash) {
+	require.NoError(t, r.Storer.SetReference(plumbing.NewHashReference(plumbing.NewTagReferenceName(name), commit)))
+}
+
+func annotatedTag(t *testing.T, r *git.Repository, name string, commit plumbing.Hash, when time.Time) {
+	tag := &object.Tag{
+		Name:       name,
+		Tagger:     object.Signature{Name: "alice", Email: "alice@a.org", When: when},
+		Message:    name,
+		TargetType: plumbing.CommitObject,
+		Target:     commit,
+	}
+	obj := r.Storer.NewEncodedObject()
+	require.NoError(t, tag.Encode(obj))
+	hash, err := r.Storer.SetEncodedObject(obj)
+	require.NoError(t, err)
+	lightweightTag(t, r, name, hash)
+}
@@ -10,7 +10,6 @@ package git
 import (
 	"errors"
 	"fmt"
-	"path/filepath"
 	"sort"
 	"strings"
 	"time"
@@ -21,7 +20,6 @@ import (
 	"github.com/HUSTSecLab/OpenSift/pkg/logger"
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing/object"
-	"github.com/google/licensecheck"
 )
 
 var (
@@ -29,6 +27,7 @@ var (
 	errWalkRepoFailed   = errors.New("walk repo failed")
 	errWalkLogFailed    = errors.New("walk log failed")
 	errPathNameNotFound = errors.New("repo pathname not found")
+	errEmptyLog         = errors.New("no commit in log")
 )
 
 type Repo struct {
@@ -45,7 +44,17 @@ type Repo struct {
 	ContributorCount int
 	OrgCount         int
 	CommitFrequency  float64
-	EcoDeps          map[*langeco.Package]*langeco.Dependencies
+	// Activity is the activity of every window of Windows
+	Activity map[Window]Activity
+	EcoDeps  map[*langeco.Package]*langeco.Dependencies
+	// Concentration is computed with Activity by WalkLog
+	Concentration Concentration
+	// Checkpoint is the state of the last walk of the log, see WalkLogFrom
+	Checkpoint *Checkpoint
+	// Releases are found from the tags by WalkTags
+	Releases Releases
+	// Licenses are found from the files by WalkRepo, License are their ids
+	Licenses Licenses
 }
 
 func NewRepo() Repo {
@@ -65,19 +74,14 @@ func NewRepo() Repo {
 	}
 }
 
+// GetLicense returns the licenses matched in the license file f combined
+// with AND, "" if none is matched.
 func GetLicense(f *object.File) (string, error) {
-	text, err := f.Contents()
-	if err != nil {
+	ids, _, err := ScanLicenseFile(f)
+	if err != nil || len(ids) == 0 {
 		return "", err
 	}
-	cov := licensecheck.Scan([]byte(text))
-	if len(cov.Match) == 0 {
-		return "", nil
-	}
-
-	license := cov.Match[0].ID
-
-	return license, nil
+	return andExpression(ids), nil
 }
 
 func getTopNKeys(m map[string]int64) []string {
@@ -98,69 +102,90 @@ func getTopNKeys(m map[string]int64) []string {
 }
 
 func (repo *Repo) WalkLog(r *git.Repository) error {
-	cIter, err := r.Log(&git.LogOptions{
-		//* From:  ref.Hash(),
-		All:   true,
-		Since: &parser.BEGIN_TIME,
-		Until: &parser.END_TIME,
-		Order: git.LogOrderCommitterTime,
-	})
-	if err != nil {
-		return err
-	}
+	return repo.walkLog(r, nil, time.Now())
+}
 
-	contributors := make(map[string]int, 0)
-	orgs := make(map[string]int, 0)
-	var commit_count float64 = 0
+// WalkLogFrom walks the commits of r added since cp and merges them into cp,
+// then computes the metrics of the whole history from it. The whole log is
+// walked if cp is nil or cannot be updated, e.g. after a force push. The
+// updated checkpoint is set in repo.Checkpoint.
+func (repo *Repo) WalkLogFrom(r *git.Repository, cp *Checkpoint) error {
+	return repo.walkLog(r, cp, time.Now())
+}
 
-	latest_commit, err := cIter.Next()
-	if err != nil {
-		return err
+// walkLog walks the log of r, the activity windows end at now rather than
+// at the start of the process, which may run for days.
+func (repo *Repo) walkLog(r *git.Repository, cp *Checkpoint, now time.Time) error {
+	if cp != nil {
+		if err := cp.update(r, now); err != nil {
+			logger.Warnf("Walking the whole log of %s: %v", repo.URL, err)
+			cp = nil
+		}
 	}
-
-	author := fmt.Sprintf("%s(%s)", latest_commit.Author.Name, latest_commit.Author.Email)
-	e := strings.Split(latest_commit.Author.Email, "@")
-	org := e[len(e)-1]
-
-	repo.UpdatedSince = latest_commit.Committer.When
-	contributors[author]++
-	orgs[org]++
-
-	if latest_commit.Author.When.After(parser.LAST_YEAR) {
-		commit_count++
+	if cp == nil {
+		var err error
+		cp, err = fullCheckpoint(r, now)
+		if err != nil {
+			return err
+		}
+	}
+	if cp.Empty() {
+		return errEmptyLog
+	}
+	repo.Checkpoint = cp
+
+	resolver := NewIdentityResolver(readMailmap(r), emailDomains)
+	counters := newActivityCounters(now)
+	recent := newActivityCounter(now.AddDate(0, -parser.BUS_FACTOR_MONTHS, 0))
+	last := make(map[Identity]time.Time, len(cp.authors))
+	for _, author := range cp.sortedAuthors() {
+		h := cp.authors[author]
+		resolver.Add(author)
+		h.each(func(when time.Time, commits int) {
+			for _, counter := range counters {
+				counter.add(author, when, commits)
+			}
+			recent.add(author, when, commits)
+		})
+		last[author] = h.last
 	}
 
-	created_since := latest_commit.Committer.When
+	repo.Activity = make(map[Window]Activity, len(counters))
+	for w, counter := range counters {
+		repo.Activity[w] = counter.activity(resolver)
+	}
 
-	err = cIter.ForEach(func(c *object.Commit) error {
-		author := fmt.Sprintf("%s(%s)", c.Author.Name, c.Author.Email)
-		e = strings.Split(c.Author.Email, "@")
-		org := e[len(e)-1]
+	repo.Concentration = newConcentration(recent, counters[WindowLifetime], last, resolver)
 
-		//! It made sense that this `if` statement is not necessary but sometimes there are errors
-		if created_since.After(c.Committer.When) {
-			created_since = c.Committer.When
-		}
-		contributors[author]++
-		orgs[org]++
+	repo.CreatedSince = cp.CreatedSince
+	repo.UpdatedSince = cp.UpdatedSince
+	repo.ContributorCount = repo.Activity[WindowLifetime].Authors
+	repo.OrgCount = repo.Activity[WindowLifetime].Orgs
+	repo.CommitFrequency = float64(repo.Activity[Window365Days].Commits) / 52
 
-		if created_since.After(parser.LAST_YEAR) {
-			commit_count++
-		}
+	return nil
+}
 
+// readMailmap returns the .mailmap of the HEAD of r, nil if there is none.
+func readMailmap(r *git.Repository) *Mailmap {
+	ref, err := r.Head()
+	if err != nil {
 		return nil
-	})
-
+	}
+	commit, err := r.CommitObject(ref.Hash())
 	if err != nil {
-		return err
+		return nil
 	}
-
-	repo.CreatedSince = created_since
-	repo.ContributorCount = len(contributors)
-	repo.OrgCount = len(orgs)
-	repo.CommitFrequency = commit_count / 52
-
-	return nil
+	f, err := commit.File(".mailmap")
+	if err != nil {
+		return nil
+	}
+	content, err := f.Contents()
+	if err != nil {
+		logger.Error(err)
+		return nil
+	}
+	return ParseMailmap(content)
 }
 
 func (repo *Repo) WalkRepo(r *git.Repository) error {
@@ -182,20 +207,12 @@ func (repo *Repo) WalkRepo(r *git.Repository) error {
 
 	fIter := tree.Files()
 	led := NewLangEcoDeps(repo)
+	led.workspaces = readWorkspaces(tree)
+	ls := newLicenseScanner()
 
 	err = fIter.ForEach(func(f *object.File) error {
 		led.Parse(f)
-		filename := filepath.Base(f.Name)
-		if repo.License == nil {
-			if _, ok := parser.LICENSE_FILENAMES[filename]; ok {
-				license, err := GetLicense(f)
-				if err != nil {
-					logger.Error(err)
-				} else if license != "" {
-					repo.License = []string{license}
-				}
-			}
-		}
+		ls.Parse(f)
 		return nil
 	})
 
@@ -203,6 +220,9 @@ func (repo *Repo) WalkRepo(r *git.Repository) error {
 		return err
 	}
 
+	repo.Licenses = ls.Licenses()
+	repo.License = repo.Licenses.IDs
+
 	repo.Languages = getTopNKeys(led.languages)
 	repo.Ecosystems = getTopNKeys(led.ecosystems)
 	repo.EcoDeps = led.dependencies
@@ -234,9 +254,30 @@ func (repo *Repo) Show() {
 		"Organization Count", repo.OrgCount,
 		"Commit Frequency", repo.CommitFrequency,
 	)
+	for _, w := range Windows {
+		a := repo.Activity[w]
+		fmt.Printf("[Activity %v]: commits %v    authors %v    orgs %v    maintainers %v\n",
+			w, a.Commits, a.Authors, a.Orgs, a.Maintainers)
+	}
+	c := repo.Concentration
+	fmt.Printf("[Bus Factor]: 50%% %v    80%% %v    [Commit Gini]: %.3f    [Top Org Share]: %.3f\n",
+		c.BusFactor50, c.BusFactor80, c.CommitGini, c.TopOrgShare)
+	fmt.Printf("[Top Maintainers Last Commit]: %v\n", c.TopMaintainersLastCommit)
+	rel := repo.Releases
+	fmt.Printf("[Semver Tags]: %v    [Releases]: %v    [Releases Last Year]: %v\n", rel.SemverTags, rel.Count, rel.LastYear)
+	fmt.Printf("[Last Release]: %v    [Median Release Interval]: %.1f days\n", rel.Last, rel.MedianInterval)
+	l := repo.Licenses
+	fmt.Printf("[License Expression]: %v    [License Families]: %v\n", l.Expression, l.Families)
+	fmt.Printf("[License Coverage]: %v    [License Files]: %v    [SPDX Headers]: %v/%v\n", l.Coverage, len(l.Files), l.Headers, l.Sources)
 }
 
 func ParseRepo(r *git.Repository) (*Repo, error) {
+	return ParseRepoFrom(r, nil)
+}
+
+// ParseRepoFrom parses r, only walking the commits added since cp if it is
+// not nil, see Repo.WalkLogFrom.
+func ParseRepoFrom(r *git.Repository, cp *Checkpoint) (*Repo, error) {
 
 	repo := NewRepo()
 
@@ -272,11 +313,17 @@ func ParseRepo(r *git.Repository) (*Repo, error) {
 		return nil, errWalkRepoFailed
 	}
 
-	err = repo.WalkLog(r)
+	err = repo.WalkLogFrom(r, cp)
 	if err != nil {
 		logger.Errorf("Failed to Walk Log for %v", err)
 		return nil, errWalkLogFailed
 	}
 
+	// a repository without tags is still parsed
+	err = repo.WalkTags(r)
+	if err != nil {
+		logger.Errorf("Failed to Walk Tags for %v", err)
+	}
+
 	return &repo, nil
 }
@@ -0,0 +1,140 @@
+package git
+
+import (
+	"container/heap"
+	"errors"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+)
+
+var errHistoryRewritten = errors.New("history rewritten since the checkpoint")
+
+const (
+	// reachable from the current tips
+	flagNew uint8 = 1 << iota
+	// reachable from the tips of the checkpoint
+	flagOld
+)
+
+type commitQueue []*object.Commit
+
+func (q commitQueue) Len() int { return len(q) }
+func (q commitQueue) Less(i, j int) bool {
+	return q[i].Committer.When.After(q[j].Committer.When)
+}
+func (q commitQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+func (q *commitQueue) Push(x any)   { *q = append(*q, x.(*object.Commit)) }
+func (q *commitQueue) Pop() any {
+	old := *q
+	c := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return c
+}
+
+// newCommits returns the commits reachable from tips but not from old, like
+// git rev-list tips --not old. Both sides are walked together by descending
+// committer time, and the walk stops once every commit left is reachable
+// from old.
+//
+// It returns errHistoryRewritten if a commit of old whose ref moved or was
+// deleted is not reachable from tips anymore, e.g. after a force push, as
+// the commits counted from it may be gone. Refs not pointing to a commit,
+// such as annotated tags, are skipped as in a walk of the log.
+func newCommits(s storer.EncodedObjectStorer, old, tips map[string]plumbing.Hash) ([]*object.Commit, error) {
+	current := make(map[plumbing.Hash]bool, len(tips))
+	for _, hash := range tips {
+		current[hash] = true
+	}
+	// old tips not trivially in the history, they must be reached from tips
+	pending := make(map[plumbing.Hash]bool)
+	for name, hash := range old {
+		if tips[name] != hash && !current[hash] {
+			pending[hash] = true
+		}
+	}
+
+	flags := make(map[plumbing.Hash]uint8)
+	queued := make(map[plumbing.Hash]bool)
+	queue := make(commitQueue, 0)
+	push := func(c *object.Commit, flag uint8) {
+		if flags[c.Hash]|flag == flags[c.Hash] {
+			return
+		}
+		flags[c.Hash] |= flag
+		if !queued[c.Hash] {
+			queued[c.Hash] = true
+			heap.Push(&queue, c)
+		}
+	}
+
+	for hash := range current {
+		if c, err := object.GetCommit(s, hash); err == nil {
+			push(c, flagNew)
+		}
+	}
+	for _, hash := range old {
+		c, err := object.GetCommit(s, hash)
+		if err == plumbing.ErrObjectNotFound && pending[hash] {
+			return nil, errHistoryRewritten
+		}
+		if err != nil {
+			delete(pending, hash)
+			continue
+		}
+		push(c, flagOld)
+	}
+
+	found := make(map[plumbing.Hash]*object.Commit)
+	for queue.Len() > 0 && (len(pending) > 0 || !allFlagged(queue, flags, flagOld)) {
+		c := heap.Pop(&queue).(*object.Commit)
+		delete(queued, c.Hash)
+		f := flags[c.Hash]
+
+		if pending[c.Hash] {
+			if f&flagNew == 0 {
+				return nil, errHistoryRewritten
+			}
+			delete(pending, c.Hash)
+		}
+		// a commit may be reached from old after being found if committer
+		// times are skewed
+		if f&flagOld == 0 {
+			found[c.Hash] = c
+		} else {
+			delete(found, c.Hash)
+		}
+
+		for _, hash := range c.ParentHashes {
+			p, err := object.GetCommit(s, hash)
+			// the parents of the commits at the boundary of a shallow
+			// mirror are missing, they were counted before it was pruned
+			if err == plumbing.ErrObjectNotFound {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			push(p, f)
+		}
+	}
+	if len(pending) > 0 {
+		return nil, errHistoryRewritten
+	}
+
+	ret := make([]*object.Commit, 0, len(found))
+	for _, c := range found {
+		ret = append(ret, c)
+	}
+	return ret, nil
+}
+
+func allFlagged(queue commitQueue, flags map[plumbing.Hash]uint8, flag uint8) bool {
+	for _, c := range queue {
+		if flags[c.Hash]&flag == 0 {
+			return false
+		}
+	}
+	return true
+}
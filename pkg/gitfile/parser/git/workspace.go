@@ -0,0 +1,139 @@
+package git
+
+import (
+	"path"
+	"strings"
+
+	parser "github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco/go/mod"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco/java/maven"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco/nodejs/packagejson"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco/nodejs/pnpm"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco/rust/cargo"
+	"github.com/HUSTSecLab/OpenSift/pkg/logger"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+var (
+	// VendorDirs are the directories of the code of other projects, whose
+	// manifests and lock files are never those of a subproject.
+	VendorDirs = map[string]bool{
+		"vendor":           true,
+		"node_modules":     true,
+		"bower_components": true,
+		"third_party":      true,
+		"third-party":      true,
+		"3rdparty":         true,
+	}
+	// ExcludedDirs are the directories of tests, examples and fixtures,
+	// whose manifests and lock files are not those of a subproject unless a
+	// workspace declares them.
+	ExcludedDirs = map[string]bool{
+		"test":         true,
+		"tests":        true,
+		"testdata":     true,
+		"__tests__":    true,
+		"example":      true,
+		"examples":     true,
+		"sample":       true,
+		"samples":      true,
+		"fixture":      true,
+		"fixtures":     true,
+		"__fixtures__": true,
+	}
+)
+
+type workspaceParser struct {
+	filename string
+	eco      string
+	parse    func(content string) (*langeco.Workspace, error)
+}
+
+var workspaceParsers = []workspaceParser{
+	{langeco.CARGO_TOML, parser.CARGO, cargo.ParseWorkspace},
+	{langeco.NODEJS_PACKAGE_JSON, parser.NPM, packagejson.ParseWorkspace},
+	{langeco.PNPM_WORKSPACE, parser.NPM, pnpm.ParseWorkspace},
+	{langeco.GO_WORK, parser.GO, mod.ParseWork},
+	{langeco.MAVEN_POM, parser.MAVEN, maven.ParseModules},
+}
+
+// readWorkspaces reads the workspaces declared at the root of tree, by
+// ecosystem. The modules of a Maven module are members too.
+func readWorkspaces(tree *object.Tree) map[string]*langeco.Workspace {
+	workspaces := map[string]*langeco.Workspace{}
+	for _, p := range workspaceParsers {
+		w := &langeco.Workspace{}
+		readWorkspace(tree, ".", p, w, map[string]bool{})
+		if len(w.Members) == 0 {
+			continue
+		}
+		if existing, ok := workspaces[p.eco]; ok {
+			existing.Members = append(existing.Members, w.Members...)
+			existing.Exclude = append(existing.Exclude, w.Exclude...)
+		} else {
+			workspaces[p.eco] = w
+		}
+	}
+	return workspaces
+}
+
+func readWorkspace(tree *object.Tree, dir string, p workspaceParser, w *langeco.Workspace, visited map[string]bool) {
+	if visited[dir] {
+		return
+	}
+	visited[dir] = true
+	f, err := tree.File(path.Join(dir, p.filename))
+	if err != nil {
+		return
+	}
+	content, err := f.Contents()
+	if err != nil {
+		logger.Error(err)
+		return
+	}
+	lw, err := p.parse(content)
+	if err != nil {
+		logger.Warnf("Parsing workspace of %s failed: %v", f.Name, err)
+		return
+	}
+	if lw == nil {
+		return
+	}
+	for _, member := range lw.Members {
+		member = path.Join(dir, member)
+		w.Members = append(w.Members, member)
+		if p.eco == parser.MAVEN {
+			readWorkspace(tree, member, p, w, visited)
+		}
+	}
+	for _, exclude := range lw.Exclude {
+		w.Exclude = append(w.Exclude, path.Join(dir, exclude))
+	}
+}
+
+// subproject reports whether the manifest or lock file at name, of the
+// ecosystem eco, is that of a subproject of the repository: at its root, in
+// a member of the workspace of eco if the root declares one, or else out of
+// ExcludedDirs. It is never in VendorDirs.
+func (led *LangEcoDeps) subproject(name string, eco string) bool {
+	dir := path.Dir(name)
+	if dir == "." {
+		return true
+	}
+	segments := strings.Split(dir, "/")
+	for _, segment := range segments {
+		if VendorDirs[segment] {
+			return false
+		}
+	}
+	if w, ok := led.workspaces[eco]; ok {
+		return w.Contains(dir)
+	}
+	for _, segment := range segments {
+		if ExcludedDirs[segment] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,57 @@
+package git
+
+import (
+	"testing"
+	"time"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWalkRepoWorkspaces(t *testing.T) {
+	r := newTestRepository(t, time.Now(), map[string]string{
+		"Cargo.toml": `[workspace]
+members = ["crates/*"]
+exclude = ["crates/legacy"]
+`,
+		"crates/core/Cargo.toml": `[package]
+name = "mono-core"
+version = "0.1.0"
+
+[dependencies]
+serde = "1"
+`,
+		"crates/cli/Cargo.toml": `[package]
+name = "mono-cli"
+version = "0.1.0"
+
+[dependencies]
+mono-core = { path = "../core" }
+clap = "4"
+`,
+		"crates/legacy/Cargo.toml":                    "[package]\nname = \"mono-legacy\"\nversion = \"0.1.0\"\n",
+		"crates/core/examples/demo/Cargo.toml":        "[package]\nname = \"demo\"\nversion = \"0.1.0\"\n",
+		"xtask/Cargo.toml":                            "[package]\nname = \"xtask\"\nversion = \"0.1.0\"\n",
+		"package.json":                                `{"name": "mono", "private": true, "workspaces": ["packages/*"], "devDependencies": {"lerna": "^8.0.0"}}`,
+		"packages/ui/package.json":                    `{"name": "@mono/ui", "version": "1.0.0", "dependencies": {"react": "^18.0.0"}}`,
+		"packages/ui/node_modules/react/package.json": `{"name": "react", "version": "18.2.0", "dependencies": {"loose-envify": "^1.1.0"}}`,
+		"docs/package.json":                           `{"name": "mono-docs", "version": "1.0.0"}`,
+		"tools/go.mod":                                "module example.com/mono/tools\n\ngo 1.22\n\nrequire golang.org/x/mod v0.23.0\n",
+		"tools/testdata/go.mod":                       "module example.com/fixture\n\ngo 1.22\n",
+		"tools/vendor/golang.org/x/mod/go.mod":        "module golang.org/x/mod\n\ngo 1.22\n",
+	}, []testCommit{{"alice", "alice@a.org", time.Hour}})
+
+	repo := NewRepo()
+	require.NoError(t, repo.WalkRepo(r))
+
+	packages, dependencies := repo.Packages()
+	names := func(pkgs []langeco.Package) []string {
+		ret := make([]string, len(pkgs))
+		for i, pkg := range pkgs {
+			ret[i] = pkg.Eco + ":" + pkg.Name
+		}
+		return ret
+	}
+	require.Equal(t, []string{"cargo:mono-cli", "cargo:mono-core", "go:example.com/mono/tools", "npm:@mono/ui"}, names(packages))
+	require.Equal(t, []string{"cargo:clap", "cargo:serde", "go:golang.org/x/mod", "npm:lerna", "npm:react"}, names(dependencies))
+}
@@ -1,9 +1,10 @@
 package conan
 
 import (
-	"encoding/json"
+	"regexp"
 	"strings"
 
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
 	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco"
 	"github.com/liamg/jfather"
 	"golang.org/x/xerrors"
@@ -33,14 +34,40 @@ type Require struct {
 
 type Requires []Require
 
-// ToDo
+// parseV1 parses the graph lock of conan 1.x, the root node 0 is the
+// package of the conanfile, the dependencies are the other nodes.
 func parseV1(lock LockFile) (*langeco.Package, *langeco.Dependencies, error) {
-	return &langeco.Package{}, &langeco.Dependencies{}, nil
+	pkg := &langeco.Package{Eco: parser.CONAN}
+	deps := make(langeco.Dependencies, 0, len(lock.GraphLock.Nodes))
+	for id, node := range lock.GraphLock.Nodes {
+		if node.Ref == "" {
+			continue
+		}
+		name, version, err := ParsePackage(node.Ref)
+		if err != nil {
+			return nil, nil, err
+		}
+		if id == "0" {
+			pkg.Name, pkg.Version = name, version
+			continue
+		}
+		deps = append(deps, langeco.Package{Name: name, Version: version, Eco: parser.CONAN})
+	}
+	return pkg, &deps, nil
 }
 
-// ToDo
+// parseV2 parses the lockfile of conan 2.x, which only lists the
+// references of the requirements.
 func parseV2(lock LockFile) (*langeco.Package, *langeco.Dependencies, error) {
-	return &langeco.Package{}, &langeco.Dependencies{}, nil
+	deps := make(langeco.Dependencies, 0, len(lock.Requires))
+	for _, req := range lock.Requires {
+		name, version, err := ParsePackage(req.Dependency)
+		if err != nil {
+			return nil, nil, err
+		}
+		deps = append(deps, langeco.Package{Name: name, Version: version, Eco: parser.CONAN})
+	}
+	return &langeco.Package{Eco: parser.CONAN}, &deps, nil
 }
 
 func ParsePackage(text string) (string, string, error) {
@@ -81,7 +108,7 @@ func (r *Require) UnmarshalJSONWithMetadata(node jfather.Node) error {
 
 func Parse(content string) (*langeco.Package, *langeco.Dependencies, error) {
 	var lock LockFile
-	if err := json.Unmarshal([]byte(content), &lock); err != nil {
+	if err := jfather.Unmarshal([]byte(content), &lock); err != nil {
 		return nil, nil, err
 	}
 
@@ -93,3 +120,21 @@ func Parse(content string) (*langeco.Package, *langeco.Dependencies, error) {
 		return parseV2(lock)
 	}
 }
+
+var (
+	recipeNameRegexp    = regexp.MustCompile(`(?m)^\s+name\s*=\s*["']([^"']+)["']`)
+	recipeVersionRegexp = regexp.MustCompile(`(?m)^\s+version\s*=\s*["']([^"']+)["']`)
+)
+
+// ParseRecipe parses a conanfile.py for the name and version attributes of
+// the recipe. The dependencies are those of the lock.
+func ParseRecipe(content string) (*langeco.Package, *langeco.Dependencies, error) {
+	pkg := &langeco.Package{Eco: parser.CONAN}
+	if m := recipeNameRegexp.FindStringSubmatch(content); m != nil {
+		pkg.Name = m[1]
+	}
+	if m := recipeVersionRegexp.FindStringSubmatch(content); m != nil {
+		pkg.Version = m[1]
+	}
+	return pkg, nil, nil
+}
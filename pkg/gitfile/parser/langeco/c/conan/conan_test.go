@@ -1,21 +1,58 @@
 package conan
 
 import (
-	"fmt"
-	"io"
-	"os"
 	"testing"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco"
+	"github.com/stretchr/testify/require"
 )
 
 func TestParse(t *testing.T) {
-	file, _ := os.Open("")
-	defer file.Close()
-	data, _ := io.ReadAll(file)
-	t.Run("Parse Conan", func(t *testing.T) {
-		pkg, deps, _ := Parse(string(data))
-		fmt.Println(*pkg)
-		for index, dep := range *deps {
-			fmt.Println(index, dep)
-		}
+	t.Run("Parse Conan 1.x", func(t *testing.T) {
+		pkg, deps, err := Parse(`{
+ "graph_lock": {
+  "nodes": {
+   "0": {"ref": "app/1.0@user/stable", "requires": ["1"]},
+   "1": {"ref": "zlib/1.2.13#7dcb50c43a5a50d984c2e8fa5898bf18", "requires": []}
+  }
+ },
+ "version": "0.4"
+}`)
+		require.NoError(t, err)
+		require.Equal(t, langeco.Package{Name: "app", Version: "1.0", Eco: parser.CONAN}, *pkg)
+		require.Equal(t, langeco.Dependencies{{Name: "zlib", Version: "1.2.13", Eco: parser.CONAN}}, *deps)
+	})
+	t.Run("Parse Conan 2.x", func(t *testing.T) {
+		pkg, deps, err := Parse(`{
+ "version": "0.5",
+ "requires": [
+  "zlib/1.2.13#e377bee636333ae348d51ca90874e353%1676251613.389",
+  "openssl/3.1.0"
+ ]
+}`)
+		require.NoError(t, err)
+		require.Empty(t, pkg.Name)
+		require.Equal(t, langeco.Dependencies{
+			{Name: "zlib", Version: "1.2.13", Eco: parser.CONAN},
+			{Name: "openssl", Version: "3.1.0", Eco: parser.CONAN},
+		}, *deps)
 	})
 }
+
+func TestParseRecipe(t *testing.T) {
+	pkg, deps, err := ParseRecipe(`from conan import ConanFile
+
+
+class ZlibConan(ConanFile):
+    name = "zlib"
+    version = "1.3.1"
+    package_type = "library"
+
+    def requirements(self):
+        self.requires("openssl/3.1.0")
+`)
+	require.NoError(t, err)
+	require.Nil(t, deps)
+	require.Equal(t, &langeco.Package{Name: "zlib", Version: "1.3.1", Eco: parser.CONAN}, pkg)
+}
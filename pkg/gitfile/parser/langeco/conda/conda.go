@@ -38,3 +38,14 @@ func Parse(content string) (*langeco.Package, *langeco.Dependencies, error) {
 		Eco:     parser.CONDA,
 	}, &langeco.Dependencies{}, nil
 }
+
+// ParseInstalled parses the metadata of a package installed in an
+// environment committed to a repository, a dependency of the repository,
+// which the metadata does not name.
+func ParseInstalled(content string) (*langeco.Package, *langeco.Dependencies, error) {
+	pkg, _, err := Parse(content)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &langeco.Package{Eco: parser.CONDA}, &langeco.Dependencies{*pkg}, nil
+}
@@ -0,0 +1,40 @@
+package conda
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco"
+)
+
+// a recipe names its package in its package section, usually through jinja
+// variables, e.g.
+//
+//	{% set name = "numpy" %}
+//	package:
+//	  name: {{ name|lower }}
+var (
+	setNameRegexp        = regexp.MustCompile(`\{%-?\s*set\s+name\s*=\s*["']([^"']+)["']`)
+	setVersionRegexp     = regexp.MustCompile(`\{%-?\s*set\s+version\s*=\s*["']([^"']+)["']`)
+	packageNameRegexp    = regexp.MustCompile(`(?m)^package:\s*\n(?:[ \t]+.*\n)*?[ \t]+name:\s*["']?([^"'\s{}]+)["']?\s*$`)
+	packageVersionRegexp = regexp.MustCompile(`(?m)^package:\s*\n(?:[ \t]+.*\n)*?[ \t]+version:\s*["']?([^"'\s{}]+)["']?\s*$`)
+)
+
+// ParseRecipe parses the meta.yaml of a conda recipe for the name and
+// version of its package, conda names are lowercase. The requirements of the
+// recipe are not parsed.
+func ParseRecipe(content string) (*langeco.Package, *langeco.Dependencies, error) {
+	pkg := &langeco.Package{Eco: parser.CONDA}
+	if m := packageNameRegexp.FindStringSubmatch(content); m != nil {
+		pkg.Name = strings.ToLower(m[1])
+	} else if m := setNameRegexp.FindStringSubmatch(content); m != nil {
+		pkg.Name = strings.ToLower(m[1])
+	}
+	if m := packageVersionRegexp.FindStringSubmatch(content); m != nil {
+		pkg.Version = m[1]
+	} else if m := setVersionRegexp.FindStringSubmatch(content); m != nil {
+		pkg.Version = m[1]
+	}
+	return pkg, nil, nil
+}
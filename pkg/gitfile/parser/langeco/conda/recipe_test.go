@@ -0,0 +1,45 @@
+package conda
+
+import (
+	"testing"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRecipe(t *testing.T) {
+	t.Run("jinja", func(t *testing.T) {
+		pkg, _, err := ParseRecipe(`{% set name = "NumPy" %}
+{% set version = "1.26.4" %}
+
+package:
+  name: {{ name|lower }}
+  version: {{ version }}
+
+requirements:
+  host:
+    - python
+`)
+		require.NoError(t, err)
+		require.Equal(t, &langeco.Package{Name: "numpy", Version: "1.26.4", Eco: parser.CONDA}, pkg)
+	})
+	t.Run("literal", func(t *testing.T) {
+		pkg, _, err := ParseRecipe(`package:
+  name: tqdm
+  version: "4.66.1"
+
+source:
+  url: https://pypi.io/packages/source/t/tqdm/tqdm-4.66.1.tar.gz
+`)
+		require.NoError(t, err)
+		require.Equal(t, &langeco.Package{Name: "tqdm", Version: "4.66.1", Eco: parser.CONDA}, pkg)
+	})
+}
+
+func TestParseInstalled(t *testing.T) {
+	pkg, deps, err := ParseInstalled(`{"name": "numpy", "version": "1.21.5"}`)
+	require.NoError(t, err)
+	require.Equal(t, &langeco.Package{Eco: parser.CONDA}, pkg)
+	require.Equal(t, &langeco.Dependencies{{Name: "numpy", Version: "1.21.5", Eco: parser.CONDA}}, deps)
+}
@@ -1,21 +1,25 @@
 package dotnet
 
 import (
-	"fmt"
-	"io"
 	"os"
+	"path/filepath"
 	"testing"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco"
+	"github.com/stretchr/testify/require"
 )
 
 func TestParse(t *testing.T) {
-	file, _ := os.Open("")
-	defer file.Close()
-	data, _ := io.ReadAll(file)
-	t.Run("Parse .Net", func(t *testing.T) {
-		pkg, deps, _ := Parse(string(data))
-		fmt.Println(*pkg)
-		for index, dep := range *deps {
-			fmt.Println(index, dep)
-		}
-	})
+	data, err := os.ReadFile(filepath.Join("testdata", "app.deps.json"))
+	require.NoError(t, err)
+
+	pkg, deps, err := Parse(string(data))
+	require.NoError(t, err)
+	require.Equal(t, &langeco.Package{Eco: parser.DOTNET}, pkg)
+	// the project is not a package, and Microsoft.NETCore.Platforms has
+	// no runtime assets
+	require.Equal(t, &langeco.Dependencies{
+		{Name: "Newtonsoft.Json", Version: "13.0.3", Eco: parser.DOTNET},
+	}, deps)
 }
@@ -1,21 +1,25 @@
 package nuget
 
 import (
-	"fmt"
-	"io"
 	"os"
+	"path/filepath"
 	"testing"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco"
+	"github.com/stretchr/testify/require"
 )
 
 func TestParse(t *testing.T) {
-	file, _ := os.Open("packages.config")
-	defer file.Close()
-	data, _ := io.ReadAll(file)
-	t.Run("Parse NuGet", func(t *testing.T) {
-		pkg, deps, _ := Parse(string(data))
-		fmt.Println(*pkg)
-		for index, dep := range *deps {
-			fmt.Println(index, dep)
-		}
-	})
+	data, err := os.ReadFile(filepath.Join("testdata", "packages.config"))
+	require.NoError(t, err)
+
+	pkg, deps, err := Parse(string(data))
+	require.NoError(t, err)
+	require.Equal(t, &langeco.Package{Eco: parser.NUGET}, pkg)
+	// NUnit is a development dependency
+	require.Equal(t, &langeco.Dependencies{
+		{Name: "Newtonsoft.Json", Version: "13.0.3", Eco: parser.NUGET},
+		{Name: "Serilog", Version: "3.1.1", Eco: parser.NUGET},
+	}, deps)
 }
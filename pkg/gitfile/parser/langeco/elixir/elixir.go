@@ -1,7 +1,47 @@
 package elixir
 
-import "github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco"
+import (
+	"regexp"
+	"strings"
 
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco"
+)
+
+// a package from hex, e.g.
+// "plug_crypto": {:hex, :plug_crypto, "2.0.0", "77515cc1...", [:mix], [], "hexpm", "53695bae..."},
+// the name of the package on hex may differ from the name of the
+// application, the first one
+var hexRegexp = regexp.MustCompile(`^"[^"]+":\s*\{:hex,\s*:"?([^,"]+)"?,\s*"([^"]+)"`)
+
+// Parse parses a mix.lock, the packages from hex are the dependencies,
+// those from git or paths are skipped. The lock does not name the project.
 func Parse(content string) (*langeco.Package, *langeco.Dependencies, error) {
-	return &langeco.Package{}, &langeco.Dependencies{}, nil
+	deps := make(langeco.Dependencies, 0)
+	for _, line := range strings.Split(content, "\n") {
+		m := hexRegexp.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil {
+			continue
+		}
+		deps = append(deps, langeco.Package{Name: m[1], Version: m[2], Eco: parser.ELIXIR})
+	}
+	return &langeco.Package{Eco: parser.ELIXIR}, &deps, nil
+}
+
+var (
+	appRegexp     = regexp.MustCompile(`(?m)^\s*app:\s*:"?([^,"\s]+)"?`)
+	versionRegexp = regexp.MustCompile(`(?m)^\s*(?:@version\s+|version:\s*)"([^"]+)"`)
+)
+
+// ParseManifest parses a mix.exs for the name of the project, its
+// application, and its version. The dependencies are those of the lock.
+func ParseManifest(content string) (*langeco.Package, *langeco.Dependencies, error) {
+	pkg := &langeco.Package{Eco: parser.ELIXIR}
+	if m := appRegexp.FindStringSubmatch(content); m != nil {
+		pkg.Name = m[1]
+	}
+	if m := versionRegexp.FindStringSubmatch(content); m != nil {
+		pkg.Version = m[1]
+	}
+	return pkg, nil, nil
 }
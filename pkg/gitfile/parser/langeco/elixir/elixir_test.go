@@ -1 +1,43 @@
 package elixir
+
+import (
+	"testing"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	_, deps, err := Parse(`%{
+  "jason": {:hex, :jason, "1.4.1", "af1504e3", [:mix], [{:decimal, "~> 1.0 or ~> 2.0", [hex: :decimal, repo: "hexpm", optional: true]}], "hexpm", "fbb01ecd"},
+  "phoenix": {:git, "https://github.com/phoenixframework/phoenix.git", "8d3f1b1b", [branch: "main"]},
+  "plug_crypto": {:hex, :plug_crypto, "2.0.0", "77515cc1", [:mix], [], "hexpm", "53695bae"},
+}
+`)
+	require.NoError(t, err)
+	require.Equal(t, langeco.Dependencies{
+		{Name: "jason", Version: "1.4.1", Eco: parser.ELIXIR},
+		{Name: "plug_crypto", Version: "2.0.0", Eco: parser.ELIXIR},
+	}, *deps)
+}
+
+func TestParseManifest(t *testing.T) {
+	pkg, deps, err := ParseManifest(`defmodule PlugCrypto.MixProject do
+  use Mix.Project
+
+  @version "2.0.0"
+
+  def project do
+    [
+      app: :plug_crypto,
+      version: @version,
+      deps: deps()
+    ]
+  end
+end
+`)
+	require.NoError(t, err)
+	require.Nil(t, deps)
+	require.Equal(t, &langeco.Package{Name: "plug_crypto", Version: "2.0.0", Eco: parser.ELIXIR}, pkg)
+}
@@ -41,3 +41,16 @@ func Parse(content string) (*langeco.Package, *langeco.Dependencies, error) {
 
 	return &pkg, &deps, nil
 }
+
+// ParseWork parses a go.work, whose workspace is the modules it uses.
+func ParseWork(content string) (*langeco.Workspace, error) {
+	f, err := modfile.ParseWork("go.work", []byte(content), nil)
+	if err != nil {
+		return nil, ErrParsingFailed
+	}
+	w := &langeco.Workspace{}
+	for _, use := range f.Use {
+		w.Members = append(w.Members, use.Path)
+	}
+	return w, nil
+}
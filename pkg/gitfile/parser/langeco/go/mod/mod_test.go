@@ -111,7 +111,7 @@ func TestParse(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			path := filepath.Join("TestData", tt.filename)
+			path := filepath.Join("testdata", tt.filename)
 			data, err := os.ReadFile(path)
 			if err != nil {
 				t.Fatalf("Failed to read test file %s: %v", path, err)
@@ -135,3 +135,16 @@ func TestParse(t *testing.T) {
 		})
 	}
 }
+
+func TestParseWork(t *testing.T) {
+	w, err := ParseWork(`go 1.22
+
+use (
+	.
+	./tools
+	./cmd/server
+)
+`)
+	require.NoError(t, err)
+	require.Equal(t, &langeco.Workspace{Members: []string{".", "./tools", "./cmd/server"}}, w)
+}
@@ -9,6 +9,8 @@ import (
 
 func Parse(content string) (*langeco.Package, *langeco.Dependencies, error) {
 	var deps langeco.Dependencies
+	// the modules in the order of their first entry
+	var names []string
 	uniquePkgs := make(map[string]string)
 	lines := strings.Split(content, "\n")
 	for _, line := range lines {
@@ -17,15 +19,19 @@ func Parse(content string) (*langeco.Package, *langeco.Dependencies, error) {
 		if len(s) < 2 {
 			continue
 		}
+		if _, ok := uniquePkgs[s[0]]; !ok {
+			names = append(names, s[0])
+		}
 		// go.sum records and sorts all non-major versions
 		// with the latest version as last entry
 		uniquePkgs[s[0]] = strings.TrimSuffix(s[1], "/go.mod")
 	}
 
-	for k, v := range uniquePkgs {
+	for _, name := range names {
 		deps = append(deps, langeco.Package{
-			Name:    k,
-			Version: v,
+			Name:    name,
+			Version: uniquePkgs[name],
+			Eco:     parser.GO,
 		})
 	}
 
@@ -5,9 +5,9 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco"
 	"github.com/stretchr/testify/require"
-	"github.com/HUSTSecLab/criticality_score/pkg/gitfile/parser"
-	"github.com/HUSTSecLab/criticality_score/pkg/gitfile/parser/langeco"
 )
 
 func TestParse(t *testing.T) {
@@ -87,7 +87,7 @@ func TestParse(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			path := filepath.Join("TestData", tt.filename)
+			path := filepath.Join("testdata", tt.filename)
 			data, err := os.ReadFile(path)
 			require.NoError(t, err, "Failed to read test file")
 
@@ -439,9 +439,17 @@ func Parse(content string) (*langeco.Package, *langeco.Dependencies, error) {
 		return nil, nil, err
 	}
 
+	// a module inherits the group and version of its parent
+	groupId, version := pom.GroupId, pom.Version
+	if groupId == "" {
+		groupId = pom.Parent.GroupID
+	}
+	if version == "" {
+		version = pom.Parent.Version
+	}
 	pkg := langeco.Package{
-		Name:    fmt.Sprintf("%s.%s", pom.GroupId, pom.ArtifactId),
-		Version: pom.Version,
+		Name:    fmt.Sprintf("%s.%s", groupId, pom.ArtifactId),
+		Version: version,
 		Eco:     parser.MAVEN,
 	}
 
@@ -461,6 +469,18 @@ func Parse(content string) (*langeco.Package, *langeco.Dependencies, error) {
 	return &pkg, &deps, nil
 }
 
+// ParseModules parses the modules of a pom.xml, nil if it has none.
+func ParseModules(content string) (*langeco.Workspace, error) {
+	pom := Pom{}
+	if err := xml.Unmarshal([]byte(content), &pom); err != nil {
+		return nil, err
+	}
+	if len(pom.Modules.M) == 0 {
+		return nil, nil
+	}
+	return &langeco.Workspace{Members: pom.Modules.M}, nil
+}
+
 func checkMacro(p *Properties, s string) string {
 	if strings.Contains(s, "${") {
 		if v, ok := (*p)[s[2:len(s)-1]]; ok {
@@ -1,21 +1,51 @@
 package maven
 
 import (
-	"fmt"
-	"io"
 	"os"
+	"path/filepath"
 	"testing"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco"
+	"github.com/stretchr/testify/require"
 )
 
+func TestParseModule(t *testing.T) {
+	pkg, _, err := Parse(`<project>
+  <parent>
+    <groupId>org.apache.commons</groupId>
+    <artifactId>commons-parent</artifactId>
+    <version>1.0.0</version>
+  </parent>
+  <artifactId>commons-core</artifactId>
+</project>`)
+	require.NoError(t, err)
+	require.Equal(t, &langeco.Package{Name: "org.apache.commons.commons-core", Version: "1.0.0", Eco: parser.MAVEN}, pkg)
+}
+
+func TestParseModules(t *testing.T) {
+	w, err := ParseModules(`<project>
+  <groupId>org.apache.commons</groupId>
+  <artifactId>commons-parent</artifactId>
+  <packaging>pom</packaging>
+  <modules>
+    <module>core</module>
+    <module>extras/io</module>
+  </modules>
+</project>`)
+	require.NoError(t, err)
+	require.Equal(t, &langeco.Workspace{Members: []string{"core", "extras/io"}}, w)
+}
+
 func TestParse(t *testing.T) {
-	file, _ := os.Open("pom.xml")
-	defer file.Close()
-	data, _ := io.ReadAll(file)
-	t.Run("Parse Pom.xml", func(t *testing.T) {
-		pkg, deps, _ := Parse(string(data))
-		fmt.Println(*pkg)
-		for index, dep := range *deps {
-			fmt.Println(index, dep)
-		}
-	})
+	data, err := os.ReadFile(filepath.Join("testdata", "pom.xml"))
+	require.NoError(t, err)
+
+	pkg, deps, err := Parse(string(data))
+	require.NoError(t, err)
+	require.Equal(t, &langeco.Package{Name: "org.example.demo", Version: "1.2.0", Eco: parser.MAVEN}, pkg)
+	require.Equal(t, &langeco.Dependencies{
+		{Name: "com.google.guava.guava", Version: "33.0.0-jre", Eco: parser.MAVEN},
+		{Name: "junit.junit", Version: "4.13.2", Eco: parser.MAVEN},
+	}, deps)
 }
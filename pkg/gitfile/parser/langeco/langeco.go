@@ -1,17 +1,27 @@
 package langeco
 
 import (
+	"path"
+	"strings"
+
 	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
 )
 
 var (
 	SUPPORTED_ECOS = map[string]bool{
-		parser.NPM:   true,
-		parser.GO:    true,
-		parser.MAVEN: true,
-		parser.CARGO: true,
-		parser.PYPI:  true,
-		parser.NUGET: true,
+		parser.NPM:      true,
+		parser.GO:       true,
+		parser.MAVEN:    true,
+		parser.CARGO:    true,
+		parser.PYPI:     true,
+		parser.NUGET:    true,
+		parser.DOTNET:   true,
+		parser.COMPOSER: true,
+		parser.GEMS:     true,
+		parser.ELIXIR:   true,
+		parser.CONAN:    true,
+		parser.CONDA:    true,
+		parser.SWIFT:    true,
 	}
 
 /*
@@ -40,6 +50,23 @@ const (
 	PY_PROJECT          = "pyproject.toml"
 	PY_REQUIREMENTS     = "requirements.txt"
 	PY_SETUP            = "setup.py"
+	PY_PIPFILE_LOCK     = "Pipfile.lock"
+	POETRY_LOCK         = "poetry.lock"
+	YARN_LOCK           = "yarn.lock"
+	PNPM_LOCK           = "pnpm-lock.yaml"
+	PNPM_WORKSPACE      = "pnpm-workspace.yaml"
+	GO_WORK             = "go.work"
+	COMPOSER_JSON       = "composer.json"
+	COMPOSER_LOCK       = "composer.lock"
+	GEMFILE_LOCK        = "Gemfile.lock"
+	GEMSPEC             = "*.gemspec"
+	MIX_EXS             = "mix.exs"
+	MIX_LOCK            = "mix.lock"
+	CONAN_RECIPE        = "conanfile.py"
+	CONAN_LOCK          = "conan.lock"
+	CONDA_META          = "conda-meta/*.json"
+	CONDA_RECIPE        = "recipe/meta.yaml"
+	SWIFT_RESOLVED      = "Package.resolved"
 )
 
 type Package struct {
@@ -49,3 +76,59 @@ type Package struct {
 }
 
 type Dependencies []Package
+
+// Workspace is the subprojects declared by a manifest, e.g. the members of a
+// Cargo workspace, as glob patterns of their directories relative to it,
+// where ** matches any directories.
+type Workspace struct {
+	Members []string
+	Exclude []string
+}
+
+// Contains reports whether dir is the directory of a member.
+func (w *Workspace) Contains(dir string) bool {
+	matches := func(patterns []string) bool {
+		for _, pattern := range patterns {
+			if matchDir(strings.Split(pattern, "/"), strings.Split(dir, "/")) {
+				return true
+			}
+		}
+		return false
+	}
+	return matches(w.Members) && !matches(w.Exclude)
+}
+
+func matchDir(pattern []string, dir []string) bool {
+	if len(pattern) == 0 {
+		return len(dir) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(dir); i++ {
+			if matchDir(pattern[1:], dir[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(dir) == 0 {
+		return false
+	}
+	if ok, _ := path.Match(pattern[0], dir[0]); !ok {
+		return false
+	}
+	return matchDir(pattern[1:], dir[1:])
+}
+
+// NewWorkspace returns the workspace of patterns, those negated with ! are
+// excluded.
+func NewWorkspace(patterns []string) *Workspace {
+	w := &Workspace{}
+	for _, pattern := range patterns {
+		if exclude, ok := strings.CutPrefix(pattern, "!"); ok {
+			w.Exclude = append(w.Exclude, exclude)
+		} else {
+			w.Members = append(w.Members, pattern)
+		}
+	}
+	return w
+}
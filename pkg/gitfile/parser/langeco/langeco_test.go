@@ -0,0 +1,30 @@
+package langeco
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkspaceContains(t *testing.T) {
+	w := &Workspace{
+		Members: []string{"crates/*", "tools", "components/**"},
+		Exclude: []string{"crates/legacy", "**/test/**"},
+	}
+	tests := []struct {
+		dir      string
+		contains bool
+	}{
+		{"crates/core", true},
+		{"crates/legacy", false},
+		{"crates/core/benches", false},
+		{"tools", true},
+		{"components", true},
+		{"components/button/icon", true},
+		{"components/button/test/app", false},
+		{"docs", false},
+	}
+	for _, test := range tests {
+		require.Equal(t, test.contains, w.Contains(test.dir), test.dir)
+	}
+}
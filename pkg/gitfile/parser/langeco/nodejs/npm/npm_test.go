@@ -1,21 +1,24 @@
 package npm
 
 import (
-	"fmt"
-	"io"
 	"os"
+	"path/filepath"
 	"testing"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco"
+	"github.com/stretchr/testify/require"
 )
 
 func TestParse(t *testing.T) {
-	file, _ := os.Open("package-lock.json")
-	defer file.Close()
-	data, _ := io.ReadAll(file)
-	t.Run("Parse Package-lock.json", func(t *testing.T) {
-		pkg, deps, _ := Parse(string(data))
-		fmt.Println(*pkg)
-		for index, dep := range *deps {
-			fmt.Println(index, dep)
-		}
-	})
+	data, err := os.ReadFile(filepath.Join("testdata", "package-lock.json"))
+	require.NoError(t, err)
+
+	pkg, deps, err := Parse(string(data))
+	require.NoError(t, err)
+	require.Equal(t, &langeco.Package{Name: "demo", Version: "1.0.0", Eco: parser.NPM}, pkg)
+	require.ElementsMatch(t, langeco.Dependencies{
+		{Name: "left-pad", Version: "1.3.0", Eco: parser.NPM},
+		{Name: "@babel/core", Version: "7.24.0", Eco: parser.NPM},
+	}, *deps)
 }
@@ -20,7 +20,14 @@ type packageJSON struct {
 	Dependencies         map[string]string `json:"dependencies"`
 	OptionalDependencies map[string]string `json:"optionalDependencies"`
 	DevDependencies      map[string]string `json:"devDependencies"`
-	//*	Workspaces           []string          `json:"workspaces"`
+	Private              bool              `json:"private"`
+	// Workspaces is either the patterns of the workspaces, or an object
+	// with them in packages, see workspacesJSON
+	Workspaces json.RawMessage `json:"workspaces"`
+}
+
+type workspacesJSON struct {
+	Packages []string `json:"packages"`
 }
 
 func Parse(content string) (*langeco.Package, *langeco.Dependencies, error) {
@@ -31,7 +38,9 @@ func Parse(content string) (*langeco.Package, *langeco.Dependencies, error) {
 
 	// Name and version fields are optional
 	// https://docs.npmjs.com/cli/v9/configuring-npm/package-json#name
-	if pkgJSON.Name == "" || pkgJSON.Version == "" {
+	// a private package is not published, e.g. the root of a monorepo, so
+	// it is not named
+	if !pkgJSON.Private && (pkgJSON.Name == "" || pkgJSON.Version == "") {
 		return nil, nil, ErrFieldMissed
 	}
 
@@ -40,6 +49,9 @@ func Parse(content string) (*langeco.Package, *langeco.Dependencies, error) {
 		Version: pkgJSON.Version,
 		Eco:     parser.NPM,
 	}
+	if pkgJSON.Private {
+		pkg.Name = ""
+	}
 
 	deps := make(langeco.Dependencies, 0)
 
@@ -69,3 +81,24 @@ func Parse(content string) (*langeco.Package, *langeco.Dependencies, error) {
 
 	return &pkg, &deps, nil
 }
+
+// ParseWorkspace parses the workspaces of a package.json, as listed by npm
+// and yarn, nil if it has none. Patterns negated with ! are excluded.
+func ParseWorkspace(content string) (*langeco.Workspace, error) {
+	var pkgJSON packageJSON
+	if err := json.Unmarshal([]byte(content), &pkgJSON); err != nil {
+		return nil, ErrDecodingFailed
+	}
+	if len(pkgJSON.Workspaces) == 0 {
+		return nil, nil
+	}
+	var patterns []string
+	if err := json.Unmarshal(pkgJSON.Workspaces, &patterns); err != nil {
+		var workspaces workspacesJSON
+		if err := json.Unmarshal(pkgJSON.Workspaces, &workspaces); err != nil {
+			return nil, ErrDecodingFailed
+		}
+		patterns = workspaces.Packages
+	}
+	return langeco.NewWorkspace(patterns), nil
+}
@@ -1,21 +1,46 @@
 package packagejson
 
 import (
-	"fmt"
-	"io"
 	"os"
+	"path/filepath"
 	"testing"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco"
+	"github.com/stretchr/testify/require"
 )
 
+func TestParsePrivate(t *testing.T) {
+	pkg, deps, err := Parse(`{"name": "monorepo", "private": true, "devDependencies": {"lerna": "^8.0.0"}}`)
+	require.NoError(t, err)
+	require.Empty(t, pkg.Name)
+	require.Len(t, *deps, 1)
+}
+
+func TestParseWorkspace(t *testing.T) {
+	w, err := ParseWorkspace(`{"name": "monorepo", "workspaces": ["packages/*", "!packages/internal"]}`)
+	require.NoError(t, err)
+	require.Equal(t, &langeco.Workspace{Members: []string{"packages/*"}, Exclude: []string{"packages/internal"}}, w)
+
+	w, err = ParseWorkspace(`{"name": "monorepo", "workspaces": {"packages": ["apps/*"], "nohoist": ["**/react"]}}`)
+	require.NoError(t, err)
+	require.Equal(t, &langeco.Workspace{Members: []string{"apps/*"}}, w)
+
+	w, err = ParseWorkspace(`{"name": "left-pad", "version": "1.3.0"}`)
+	require.NoError(t, err)
+	require.Nil(t, w)
+}
+
 func TestParse(t *testing.T) {
-	file, _ := os.Open("package.json")
-	defer file.Close()
-	data, _ := io.ReadAll(file)
-	t.Run("Parse Package.json", func(t *testing.T) {
-		pkg, deps, _ := Parse(string(data))
-		fmt.Println(*pkg)
-		for index, dep := range *deps {
-			fmt.Println(index, dep)
-		}
-	})
+	data, err := os.ReadFile(filepath.Join("testdata", "package.json"))
+	require.NoError(t, err)
+
+	pkg, deps, err := Parse(string(data))
+	require.NoError(t, err)
+	require.Equal(t, &langeco.Package{Name: "demo", Version: "1.0.0", Eco: parser.NPM}, pkg)
+	require.ElementsMatch(t, langeco.Dependencies{
+		{Name: "express", Version: "^4.19.2", Eco: parser.NPM},
+		{Name: "typescript", Version: "^5.4.0", Eco: parser.NPM},
+		{Name: "fsevents", Version: "^2.3.3", Eco: parser.NPM},
+	}, *deps)
 }
@@ -2,7 +2,10 @@ package pnpm
 
 import (
 	"errors"
+	"sort"
+	"strings"
 
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
 	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco"
 	"gopkg.in/yaml.v3"
 )
@@ -32,12 +35,66 @@ type LockFile struct {
 	Packages        map[string]PackageInfo `yaml:"packages,omitempty"`
 }
 
+// parsePackageKey returns the name and the version of a key of the packages
+// of a lock, such as /lodash/4.17.21 in lock version 5, /lodash@4.17.21 in
+// lock version 6 or lodash@4.17.21 in lock version 9. Peer dependency
+// suffixes, such as _react@18.2.0 or (react@18.2.0), are dropped.
+func parsePackageKey(key string) (string, string) {
+	key = strings.TrimPrefix(key, "/")
+	if i := strings.Index(key, "("); i > 0 {
+		key = key[:i]
+	}
+	nameSegments := 1
+	if strings.HasPrefix(key, "@") {
+		nameSegments = 2
+	}
+	// name/version in lock version 5
+	if segments := strings.Split(key, "/"); len(segments) == nameSegments+1 {
+		version, _, _ := strings.Cut(segments[nameSegments], "_")
+		return strings.Join(segments[:nameSegments], "/"), version
+	}
+	// the @ of a scope is at 0
+	if at := strings.LastIndex(key, "@"); at > 0 {
+		return key[:at], key[at+1:]
+	}
+	return key, ""
+}
+
+// Parse parses a pnpm-lock.yaml, the packages locked are the dependencies.
+// The lock does not name the project.
 func Parse(content string) (*langeco.Package, *langeco.Dependencies, error) {
 	var lockFile LockFile
 	if err := yaml.Unmarshal([]byte(content), &lockFile); err != nil {
 		return nil, nil, ErrDecodingFailed
 	}
 
-	// ToDo
-	return &langeco.Package{}, &langeco.Dependencies{}, nil
+	deps := make(langeco.Dependencies, 0, len(lockFile.Packages))
+	for key, info := range lockFile.Packages {
+		name, version := parsePackageKey(key)
+		if info.Name != "" {
+			name, version = info.Name, info.Version
+		}
+		deps = append(deps, langeco.Package{Name: name, Version: version, Eco: parser.NPM})
+	}
+	sort.Slice(deps, func(i, j int) bool {
+		if deps[i].Name != deps[j].Name {
+			return deps[i].Name < deps[j].Name
+		}
+		return deps[i].Version < deps[j].Version
+	})
+	return &langeco.Package{Eco: parser.NPM}, &deps, nil
+}
+
+type workspaceFile struct {
+	Packages []string `yaml:"packages"`
+}
+
+// ParseWorkspace parses a pnpm-workspace.yaml. Patterns negated with ! are
+// excluded.
+func ParseWorkspace(content string) (*langeco.Workspace, error) {
+	var w workspaceFile
+	if err := yaml.Unmarshal([]byte(content), &w); err != nil {
+		return nil, ErrDecodingFailed
+	}
+	return langeco.NewWorkspace(w.Packages), nil
 }
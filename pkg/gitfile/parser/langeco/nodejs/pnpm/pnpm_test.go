@@ -1 +1,66 @@
 package pnpm
+
+import (
+	"testing"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePackageKey(t *testing.T) {
+	tests := []struct {
+		key, name, version string
+	}{
+		{"/lodash/4.17.21", "lodash", "4.17.21"},
+		{"/@babel/core/7.23.0", "@babel/core", "7.23.0"},
+		{"/react-dom/18.2.0_react@18.2.0", "react-dom", "18.2.0"},
+		{"/lodash@4.17.21", "lodash", "4.17.21"},
+		{"/@babel/core@7.23.0", "@babel/core", "7.23.0"},
+		{"/react-dom@18.2.0(react@18.2.0)", "react-dom", "18.2.0"},
+		{"@babel/core@7.23.0", "@babel/core", "7.23.0"},
+	}
+	for _, tt := range tests {
+		name, version := parsePackageKey(tt.key)
+		require.Equal(t, tt.name, name, tt.key)
+		require.Equal(t, tt.version, version, tt.key)
+	}
+}
+
+func TestParse(t *testing.T) {
+	_, deps, err := Parse(`lockfileVersion: '6.0'
+
+dependencies:
+  lodash:
+    specifier: ^4.17.21
+    version: 4.17.21
+
+packages:
+
+  /lodash@4.17.21:
+    resolution: {integrity: sha512-v2kDE}
+    dev: false
+
+  /@babel/core@7.23.0:
+    resolution: {integrity: sha512-97z/j}
+    dev: true
+`)
+	require.NoError(t, err)
+	require.Equal(t, langeco.Dependencies{
+		{Name: "@babel/core", Version: "7.23.0", Eco: parser.NPM},
+		{Name: "lodash", Version: "4.17.21", Eco: parser.NPM},
+	}, *deps)
+}
+
+func TestParseWorkspace(t *testing.T) {
+	w, err := ParseWorkspace(`packages:
+  - "packages/*"
+  - "components/**"
+  - "!**/test/**"
+`)
+	require.NoError(t, err)
+	require.Equal(t, &langeco.Workspace{
+		Members: []string{"packages/*", "components/**"},
+		Exclude: []string{"**/test/**"},
+	}, w)
+}
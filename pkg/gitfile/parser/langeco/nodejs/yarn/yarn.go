@@ -2,7 +2,9 @@ package yarn
 
 import (
 	"errors"
+	"strings"
 
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
 	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco"
 )
 
@@ -25,7 +27,48 @@ type Dependency struct {
 	Name    string
 }
 
+// packageName returns the name of the package of a pattern such as
+// "@babel/core@^7.0.0" or "lodash@npm:^4.17.21", empty for the packages of
+// the workspace.
+func packageName(pattern string) string {
+	pattern = strings.Trim(strings.TrimSpace(pattern), `"`)
+	at := strings.LastIndex(pattern, "@")
+	if at <= 0 || strings.HasPrefix(pattern[at+1:], "workspace:") {
+		return ""
+	}
+	return pattern[:at]
+}
+
+// Parse parses a yarn.lock of yarn classic or yarn berry, the packages
+// locked are the dependencies. The lock does not name the project.
 func Parse(content string) (*langeco.Package, *langeco.Dependencies, error) {
-	// ToDo
-	return &langeco.Package{}, &langeco.Dependencies{}, nil
+	deps := make(langeco.Dependencies, 0)
+	var lib *Library
+	flush := func() {
+		if lib != nil && lib.Name != "" && lib.Version != "" {
+			deps = append(deps, langeco.Package{Name: lib.Name, Version: lib.Version, Eco: parser.NPM})
+		}
+		lib = nil
+	}
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimRight(line, "\r")
+		switch {
+		case line == "" || strings.HasPrefix(line, "#"):
+		case !strings.HasPrefix(line, " "):
+			// a new entry, e.g. "lodash@^4.17.20", lodash@^4.17.21:
+			flush()
+			if !strings.HasSuffix(line, ":") {
+				return nil, nil, ErrParsingFailed
+			}
+			patterns := strings.Split(strings.TrimSuffix(line, ":"), ",")
+			lib = &Library{Patterns: patterns, Name: packageName(patterns[0])}
+		case lib != nil && strings.HasPrefix(line, "  version"):
+			// version "1.2.3" in yarn classic, version: 1.2.3 in yarn berry
+			version := strings.TrimPrefix(strings.TrimSpace(line), "version")
+			version = strings.TrimPrefix(version, ":")
+			lib.Version = strings.Trim(strings.TrimSpace(version), `"`)
+		}
+	}
+	flush()
+	return &langeco.Package{Eco: parser.NPM}, &deps, nil
 }
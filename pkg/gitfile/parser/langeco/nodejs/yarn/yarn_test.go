@@ -1 +1,57 @@
 package yarn
+
+import (
+	"testing"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	want := langeco.Dependencies{
+		{Name: "@babel/code-frame", Version: "7.12.13", Eco: parser.NPM},
+		{Name: "lodash", Version: "4.17.21", Eco: parser.NPM},
+	}
+
+	t.Run("Parse yarn classic", func(t *testing.T) {
+		_, deps, err := Parse(`# THIS IS AN AUTOGENERATED FILE. DO NOT EDIT THIS FILE DIRECTLY.
+# yarn lockfile v1
+
+
+"@babel/code-frame@^7.0.0", "@babel/code-frame@^7.10.4":
+  version "7.12.13"
+  resolved "https://registry.yarnpkg.com/@babel/code-frame/-/code-frame-7.12.13.tgz"
+  dependencies:
+    "@babel/highlight" "^7.12.13"
+
+lodash@^4.17.21:
+  version "4.17.21"
+`)
+		require.NoError(t, err)
+		require.Equal(t, want, *deps)
+	})
+
+	t.Run("Parse yarn berry", func(t *testing.T) {
+		_, deps, err := Parse(`__metadata:
+  version: 6
+  cacheKey: 8
+
+"@babel/code-frame@npm:^7.0.0, @babel/code-frame@npm:^7.10.4":
+  version: 7.12.13
+  resolution: "@babel/code-frame@npm:7.12.13"
+  dependencies:
+    "@babel/highlight": ^7.12.13
+
+"app@workspace:.":
+  version: 0.0.0-use.local
+  resolution: "app@workspace:."
+
+"lodash@npm:^4.17.21":
+  version: 4.17.21
+  resolution: "lodash@npm:4.17.21"
+`)
+		require.NoError(t, err)
+		require.Equal(t, want, *deps)
+	})
+}
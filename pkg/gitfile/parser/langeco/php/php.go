@@ -2,7 +2,9 @@ package php
 
 import (
 	"errors"
+	"strings"
 
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
 	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco"
 	"github.com/liamg/jfather"
 )
@@ -24,14 +26,52 @@ type packageInfo struct {
 	EndLine   int
 }
 
-// ToDo
+type manifest struct {
+	Name    string            `json:"name"`
+	Version string            `json:"version"`
+	Require map[string]string `json:"require"`
+}
+
+// Parse parses a composer.lock, the packages installed are the
+// dependencies.
 func Parse(content string) (*langeco.Package, *langeco.Dependencies, error) {
 	var lockFile lockFile
 	if err := jfather.Unmarshal([]byte(content), &lockFile); err != nil {
 		return nil, nil, ErrDecodingFailed
 	}
 
-	return &langeco.Package{}, &langeco.Dependencies{}, nil
+	deps := make(langeco.Dependencies, 0, len(lockFile.Packages))
+	for _, p := range lockFile.Packages {
+		deps = append(deps, langeco.Package{
+			Name:    p.Name,
+			Version: p.Version,
+			Eco:     parser.COMPOSER,
+		})
+	}
+	return &langeco.Package{Eco: parser.COMPOSER}, &deps, nil
+}
+
+// ParseManifest parses a composer.json, the package it declares and the
+// packages it requires. Platform requirements such as php or ext-json are
+// skipped.
+func ParseManifest(content string) (*langeco.Package, *langeco.Dependencies, error) {
+	var m manifest
+	if err := jfather.Unmarshal([]byte(content), &m); err != nil {
+		return nil, nil, ErrDecodingFailed
+	}
+
+	deps := make(langeco.Dependencies, 0, len(m.Require))
+	for name, version := range m.Require {
+		if !strings.Contains(name, "/") {
+			continue
+		}
+		deps = append(deps, langeco.Package{
+			Name:    name,
+			Version: version,
+			Eco:     parser.COMPOSER,
+		})
+	}
+	return &langeco.Package{Name: m.Name, Version: m.Version, Eco: parser.COMPOSER}, &deps, nil
 }
 
 // UnmarshalJSONWithMetadata needed to detect start and end lines of deps
@@ -1 +1,38 @@
 package php
+
+import (
+	"testing"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	pkg, deps, err := Parse(`{
+    "packages": [
+        {"name": "monolog/monolog", "version": "3.5.0", "require": {"php": ">=8.1", "psr/log": "^2.0 || ^3.0"}},
+        {"name": "psr/log", "version": "3.0.0"}
+    ],
+    "packages-dev": []
+}`)
+	require.NoError(t, err)
+	require.Empty(t, pkg.Name)
+	require.Equal(t, langeco.Dependencies{
+		{Name: "monolog/monolog", Version: "3.5.0", Eco: parser.COMPOSER},
+		{Name: "psr/log", Version: "3.0.0", Eco: parser.COMPOSER},
+	}, *deps)
+
+	_, _, err = Parse("not json")
+	require.ErrorIs(t, err, ErrDecodingFailed)
+}
+
+func TestParseManifest(t *testing.T) {
+	pkg, deps, err := ParseManifest(`{
+    "name": "laravel/framework",
+    "require": {"php": "^8.2", "ext-json": "*", "monolog/monolog": "^3.0"}
+}`)
+	require.NoError(t, err)
+	require.Equal(t, langeco.Package{Name: "laravel/framework", Eco: parser.COMPOSER}, *pkg)
+	require.Equal(t, langeco.Dependencies{{Name: "monolog/monolog", Version: "^3.0", Eco: parser.COMPOSER}}, *deps)
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 
 	"github.com/BurntSushi/toml"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
 	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco"
 )
 
@@ -25,12 +26,21 @@ type Lockfile struct {
 	} `toml:"package"`
 }
 
+// Parse parses a poetry.lock, the packages locked are the dependencies. The
+// lock does not name the project.
 func Parse(content string) (*langeco.Package, *langeco.Dependencies, error) {
 	var lockfile Lockfile
 	if _, err := toml.Decode(content, &lockfile); err != nil {
 		return nil, nil, ErrDecodingFailed
 	}
 
-	// ToDo
-	return nil, nil, nil
+	deps := make(langeco.Dependencies, 0, len(lockfile.Packages))
+	for _, p := range lockfile.Packages {
+		deps = append(deps, langeco.Package{
+			Name:    p.Name,
+			Version: p.Version,
+			Eco:     parser.PYPI,
+		})
+	}
+	return &langeco.Package{Eco: parser.PYPI}, &deps, nil
 }
@@ -1 +1,44 @@
 package poetry
+
+import (
+	"testing"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	pkg, deps, err := Parse(`
+[[package]]
+name = "certifi"
+version = "2023.11.17"
+description = "Python package for providing Mozilla's CA Bundle."
+optional = false
+python-versions = ">=3.6"
+
+[[package]]
+name = "requests"
+version = "2.31.0"
+description = "Python HTTP for Humans."
+optional = false
+python-versions = ">=3.7"
+
+[package.dependencies]
+certifi = ">=2017.4.17"
+
+[metadata]
+lock-version = "2.0"
+python-versions = "^3.9"
+content-hash = "0000"
+`)
+	require.NoError(t, err)
+	require.Empty(t, pkg.Name)
+	require.Equal(t, langeco.Dependencies{
+		{Name: "certifi", Version: "2023.11.17", Eco: parser.PYPI},
+		{Name: "requests", Version: "2.31.0", Eco: parser.PYPI},
+	}, *deps)
+
+	_, _, err = Parse("[[package]")
+	require.ErrorIs(t, err, ErrDecodingFailed)
+}
@@ -2,7 +2,10 @@ package pipenv
 
 import (
 	"errors"
+	"sort"
+	"strings"
 
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
 	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco"
 	"github.com/liamg/jfather"
 )
@@ -24,15 +27,22 @@ type dependency struct {
 	Version string `json:"version"`
 }
 
+// Parse parses a Pipfile.lock, the default packages are the dependencies,
+// the develop ones are skipped. The lock does not name the project.
 func Parse(content string) (*langeco.Package, *langeco.Dependencies, error) {
 	var lockFile lockFile
 	if err := jfather.Unmarshal([]byte(content), &lockFile); err != nil {
 		return nil, nil, ErrDecodingFailed
 	}
 
-	pkg := langeco.Package{
-		Name: lockFile.Meta["sources"][0].Name,
+	deps := make(langeco.Dependencies, 0, len(lockFile.Default))
+	for name, dep := range lockFile.Default {
+		deps = append(deps, langeco.Package{
+			Name:    name,
+			Version: strings.TrimPrefix(dep.Version, "=="),
+			Eco:     parser.PYPI,
+		})
 	}
-	// ToDo
-	return &pkg, nil, nil
+	sort.Slice(deps, func(i, j int) bool { return deps[i].Name < deps[j].Name })
+	return &langeco.Package{Eco: parser.PYPI}, &deps, nil
 }
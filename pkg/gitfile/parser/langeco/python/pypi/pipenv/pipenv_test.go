@@ -1 +1,28 @@
 package pipenv
+
+import (
+	"testing"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	pkg, deps, err := Parse(`{
+    "_meta": {"sources": [{"name": "pypi", "url": "https://pypi.org/simple", "verify_ssl": true}]},
+    "default": {
+        "urllib3": {"hashes": [], "version": "==2.0.7"},
+        "requests": {"hashes": [], "version": "==2.31.0"}
+    },
+    "develop": {
+        "pytest": {"hashes": [], "version": "==7.4.3"}
+    }
+}`)
+	require.NoError(t, err)
+	require.Empty(t, pkg.Name)
+	require.Equal(t, langeco.Dependencies{
+		{Name: "requests", Version: "2.31.0", Eco: parser.PYPI},
+		{Name: "urllib3", Version: "2.0.7", Eco: parser.PYPI},
+	}, *deps)
+}
@@ -1,10 +1,47 @@
 package bundler
 
 import (
+	"regexp"
+	"strings"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
 	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco"
 )
 
+// a gem of the specs of a source, e.g. "    rake (13.0.6)", the gems it
+// depends on are indented further
+var specRegexp = regexp.MustCompile(`^    ([^ ]+) \(([^)]+)\)$`)
+
+// Parse parses a Gemfile.lock, the gems of the specs of every source are the
+// dependencies. The gem of the repository itself, in a PATH source with
+// the remote ".", is the package.
 func Parse(content string) (*langeco.Package, *langeco.Dependencies, error) {
-	// ToDo
-	return &langeco.Package{}, &langeco.Dependencies{}, nil
+	pkg := &langeco.Package{Eco: parser.GEMS}
+	deps := make(langeco.Dependencies, 0)
+
+	var section, remote string
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if line != "" && !strings.HasPrefix(line, " ") {
+			section, remote = line, ""
+			continue
+		}
+		if r, ok := strings.CutPrefix(line, "  remote: "); ok {
+			remote = r
+			continue
+		}
+		if section != "GEM" && section != "GIT" && section != "PATH" {
+			continue
+		}
+		m := specRegexp.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		if section == "PATH" && remote == "." && pkg.Name == "" {
+			pkg.Name, pkg.Version = m[1], m[2]
+			continue
+		}
+		deps = append(deps, langeco.Package{Name: m[1], Version: m[2], Eco: parser.GEMS})
+	}
+	return pkg, &deps, nil
 }
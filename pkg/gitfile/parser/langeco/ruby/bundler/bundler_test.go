@@ -1 +1,48 @@
 package bundler
+
+import (
+	"testing"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	pkg, deps, err := Parse(`PATH
+  remote: .
+  specs:
+    mygem (0.1.0)
+      rake (>= 12)
+
+GIT
+  remote: https://github.com/rails/rails.git
+  revision: 0123456789abcdef
+  specs:
+    rails (7.1.0.alpha)
+
+GEM
+  remote: https://rubygems.org/
+  specs:
+    nokogiri (1.15.4-x86_64-linux)
+      racc (~> 1.4)
+    rake (13.0.6)
+
+PLATFORMS
+  x86_64-linux
+
+DEPENDENCIES
+  mygem!
+  rake (~> 13.0)
+
+BUNDLED WITH
+   2.4.10
+`)
+	require.NoError(t, err)
+	require.Equal(t, langeco.Package{Name: "mygem", Version: "0.1.0", Eco: parser.GEMS}, *pkg)
+	require.Equal(t, langeco.Dependencies{
+		{Name: "rails", Version: "7.1.0.alpha", Eco: parser.GEMS},
+		{Name: "nokogiri", Version: "1.15.4-x86_64-linux", Eco: parser.GEMS},
+		{Name: "rake", Version: "13.0.6", Eco: parser.GEMS},
+	}, *deps)
+}
@@ -1 +1,21 @@
 package gem
+
+import (
+	"testing"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	pkg, _, err := Parse(`# -*- encoding: utf-8 -*-
+Gem::Specification.new do |s|
+  s.name = "async".freeze
+  s.version = "1.2.3"
+  s.authors = ["Samuel Williams".freeze]
+end
+`)
+	require.NoError(t, err)
+	require.Equal(t, langeco.Package{Name: "async", Version: "1.2.3", Eco: parser.GEMS}, *pkg)
+}
@@ -24,6 +24,14 @@ type cargoPkg struct {
 	Repository    interface{} `toml:"repository,omitempty"`
 	License       interface{} `toml:"license,omitempty"`
 	LicenseFile   interface{} `toml:"license-file,omitempty"`
+	// Publish is false, or the registries the package may be published
+	// to, none if it is empty
+	Publish interface{} `toml:"publish,omitempty"`
+}
+
+type cargoWorkspace struct {
+	Members []string `toml:"members"`
+	Exclude []string `toml:"exclude"`
 }
 
 type cargoFile struct {
@@ -31,6 +39,7 @@ type cargoFile struct {
 	Dependencies      map[string]interface{} `toml:"dependencies"`
 	DevDependencies   map[string]interface{} `toml:"dev-dependencies,omitempty"`
 	BuildDependencies map[string]interface{} `toml:"build-dependencies,omitempty"`
+	Workspace         *cargoWorkspace        `toml:"workspace,omitempty"`
 }
 
 // * Parse Cargo.toml File
@@ -46,6 +55,17 @@ func Parse(contents string) (*langeco.Package, *langeco.Dependencies, error) {
 		Version: cargoFile.Package.Version,
 		Eco:     parser.CARGO,
 	}
+	// a package which cannot be published is not named
+	switch publish := cargoFile.Package.Publish.(type) {
+	case bool:
+		if !publish {
+			pkg.Name = ""
+		}
+	case []interface{}:
+		if len(publish) == 0 {
+			pkg.Name = ""
+		}
+	}
 
 	deps := make(langeco.Dependencies, 0)
 	deps = append(deps, *exactDependencies(cargoFile.Dependencies)...)
@@ -76,3 +96,18 @@ func exactDependencies(dependencies map[string]interface{}) *langeco.Dependencie
 	}
 	return &deps
 }
+
+// ParseWorkspace parses the workspace of a Cargo.toml, nil if it has none.
+func ParseWorkspace(contents string) (*langeco.Workspace, error) {
+	var cargoFile cargoFile
+	if _, err := toml.Decode(contents, &cargoFile); err != nil {
+		return nil, ErrDecodingFailed
+	}
+	if cargoFile.Workspace == nil {
+		return nil, nil
+	}
+	return &langeco.Workspace{
+		Members: cargoFile.Workspace.Members,
+		Exclude: cargoFile.Workspace.Exclude,
+	}, nil
+}
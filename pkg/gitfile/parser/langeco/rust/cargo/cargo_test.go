@@ -6,8 +6,8 @@ import (
 	"sort"
 	"testing"
 
-	"github.com/HUSTSecLab/criticality_score/pkg/gitfile/parser"
-	"github.com/HUSTSecLab/criticality_score/pkg/gitfile/parser/langeco"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco"
 	"github.com/stretchr/testify/require"
 )
 
@@ -136,3 +136,49 @@ func TestParse(t *testing.T) {
 		})
 	}
 }
+
+func TestParseUnpublished(t *testing.T) {
+	pkg, _, err := Parse(`[package]
+name = "xtask"
+version = "0.1.0"
+publish = false
+`)
+	require.NoError(t, err)
+	require.Empty(t, pkg.Name)
+
+	pkg, _, err = Parse(`[package]
+name = "internal"
+version = "0.1.0"
+publish = []
+`)
+	require.NoError(t, err)
+	require.Empty(t, pkg.Name)
+
+	pkg, _, err = Parse(`[package]
+name = "serde"
+version = "1.0.0"
+publish = ["crates-io"]
+`)
+	require.NoError(t, err)
+	require.Equal(t, "serde", pkg.Name)
+}
+
+func TestParseWorkspace(t *testing.T) {
+	w, err := ParseWorkspace(`[workspace]
+members = ["crates/*", "xtask"]
+exclude = ["crates/legacy"]
+resolver = "2"
+`)
+	require.NoError(t, err)
+	require.Equal(t, &langeco.Workspace{
+		Members: []string{"crates/*", "xtask"},
+		Exclude: []string{"crates/legacy"},
+	}, w)
+
+	w, err = ParseWorkspace(`[package]
+name = "serde"
+version = "1.0.0"
+`)
+	require.NoError(t, err)
+	require.Nil(t, w)
+}
@@ -1,7 +1,63 @@
 package swift
 
-import "github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco"
+import (
+	"errors"
 
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco"
+	"github.com/liamg/jfather"
+)
+
+var (
+	ErrDecodingFailed = errors.New("decoding swift package resolved file failed")
+)
+
+type pin struct {
+	// Package and RepositoryURL are set in version 1
+	Package       string `json:"package"`
+	RepositoryURL string `json:"repositoryURL"`
+	// Identity and Location are set from version 2
+	Identity string `json:"identity"`
+	Location string `json:"location"`
+	State    struct {
+		Branch   string `json:"branch"`
+		Revision string `json:"revision"`
+		Version  string `json:"version"`
+	} `json:"state"`
+}
+
+type resolvedFile struct {
+	Object struct {
+		Pins []pin `json:"pins"`
+	} `json:"object"`
+	Pins    []pin `json:"pins"`
+	Version int   `json:"version"`
+}
+
+// Parse parses a Package.resolved, the packages pinned are the dependencies.
+// Swift packages have no registry, they are named by the url of their
+// repository. The file does not name the project.
 func Parse(contents string) (*langeco.Package, *langeco.Dependencies, error) {
-	return &langeco.Package{}, &langeco.Dependencies{}, nil
+	var resolved resolvedFile
+	if err := jfather.Unmarshal([]byte(contents), &resolved); err != nil {
+		return nil, nil, ErrDecodingFailed
+	}
+
+	pins := resolved.Pins
+	if resolved.Version == 1 {
+		pins = resolved.Object.Pins
+	}
+	deps := make(langeco.Dependencies, 0, len(pins))
+	for _, p := range pins {
+		name := p.Location
+		if name == "" {
+			name = p.RepositoryURL
+		}
+		version := p.State.Version
+		if version == "" {
+			version = p.State.Revision
+		}
+		deps = append(deps, langeco.Package{Name: name, Version: version, Eco: parser.SWIFT})
+	}
+	return &langeco.Package{Eco: parser.SWIFT}, &deps, nil
 }
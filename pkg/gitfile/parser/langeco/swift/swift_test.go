@@ -1 +1,46 @@
 package swift
+
+import (
+	"testing"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	want := langeco.Dependencies{
+		{Name: "https://github.com/Alamofire/Alamofire.git", Version: "5.6.4", Eco: parser.SWIFT},
+		{Name: "https://github.com/apple/swift-log.git", Version: "173f567a2dfec11d74588eea82cecea555bdc0bc", Eco: parser.SWIFT},
+	}
+
+	t.Run("Parse version 1", func(t *testing.T) {
+		_, deps, err := Parse(`{
+  "object": {
+    "pins": [
+      {"package": "Alamofire", "repositoryURL": "https://github.com/Alamofire/Alamofire.git",
+       "state": {"branch": null, "revision": "78424be314842833c04bc3bef5b72e85fff99204", "version": "5.6.4"}},
+      {"package": "swift-log", "repositoryURL": "https://github.com/apple/swift-log.git",
+       "state": {"branch": "main", "revision": "173f567a2dfec11d74588eea82cecea555bdc0bc", "version": null}}
+    ]
+  },
+  "version": 1
+}`)
+		require.NoError(t, err)
+		require.Equal(t, want, *deps)
+	})
+
+	t.Run("Parse version 2", func(t *testing.T) {
+		_, deps, err := Parse(`{
+  "pins": [
+    {"identity": "alamofire", "kind": "remoteSourceControl", "location": "https://github.com/Alamofire/Alamofire.git",
+     "state": {"revision": "78424be314842833c04bc3bef5b72e85fff99204", "version": "5.6.4"}},
+    {"identity": "swift-log", "kind": "remoteSourceControl", "location": "https://github.com/apple/swift-log.git",
+     "state": {"branch": "main", "revision": "173f567a2dfec11d74588eea82cecea555bdc0bc"}}
+  ],
+  "version": 2
+}`)
+		require.NoError(t, err)
+		require.Equal(t, want, *deps)
+	})
+}
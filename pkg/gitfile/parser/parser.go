@@ -24,6 +24,21 @@ const (
 	LANGUAGE_THRESHOLD  int = 0
 	ECOSYSTEM_THRESHOLD int = 0
 	TOP_N               int = 5
+
+	// authors of at least MAINTAINER_MIN_COMMITS commits in a window are
+	// counted as its maintainers
+	MAINTAINER_MIN_COMMITS int = 3
+
+	// months of history the bus factor, the commit gini coefficient and the
+	// share of the top organization are computed on
+	BUS_FACTOR_MONTHS int = 12
+	// number of top contributors of the whole history whose last commit is
+	// recorded
+	TOP_MAINTAINERS int = 3
+
+	// number of bytes at the beginning of a source file searched for an
+	// SPDX-License-Identifier header
+	LICENSE_HEADER_BYTES int = 1024
 )
 
 var (
@@ -36,11 +51,24 @@ var (
 	LAST_90_DAYS = NOW.AddDate(0, 0, -90)
 )
 
-// ToDo Try to cover more
-var LICENSE_FILENAMES = map[string]bool{
-	"LICENSE":     true,
-	"LICENSE.md":  true,
-	"LICENSE.txt": true,
+// languages of documents and data rather than sources, license files may be
+// written in them and their files are not counted in the license coverage
+var NON_SOURCE_LANGUAGES = map[string]bool{
+	"CSV":              true,
+	"HTML":             true,
+	"INI":              true,
+	"JSON":             true,
+	"Markdown":         true,
+	"Org":              true,
+	"RDoc":             true,
+	"Rich Text Format": true,
+	"SVG":              true,
+	"TOML":             true,
+	"Text":             true,
+	"Textile":          true,
+	"XML":              true,
+	"YAML":             true,
+	"reStructuredText": true,
 }
 
 // * https://github.com/github-linguist/linguist/blob/master/lib/linguist/languages.yml
@@ -1493,10 +1521,13 @@ const (
 	NUGET    = "nuget"
 	COMPOSER = "COMPOSER"
 	BUNDLER  = "bundler"
+	SWIFT    = "swift"
 )
 
 var ECOSYSTEM_MAP = map[string]string{
-	"conanfile.py": CONAN,
+	"conanfile.py":  CONAN,
+	"conanfile.txt": CONAN,
+	"conan.lock":    CONAN,
 	//* "environment.yaml":  "conda",
 	//* "environment.yml":   "conda",
 	//* packagename.json: "conda"
@@ -1505,12 +1536,14 @@ var ECOSYSTEM_MAP = map[string]string{
 	"deps.json":                DOTNET,
 	"gradle.lockfile":          GRADLE,
 	"mix.lock":                 ELIXIR,
+	"mix.exs":                  ELIXIR,
 	"Manifest.toml":            JULIA,
 	"package.json":             NPM,
 	"package-lock.json":        NPM,
 	".npmrc":                   NPM,
 	"node_modules":             NPM,
 	"yarn.lock":                YARN,
+	"pnpm-lock.yaml":           NPM,
 	"packages.config":          NUGET,
 	"package.lock.json":        NUGET,
 	"Directory.Build.props":    NUGET,
@@ -1522,6 +1555,7 @@ var ECOSYSTEM_MAP = map[string]string{
 	"setup.py":                 PYPI,
 	"Pipfile":                  PYPI,
 	"Pipfile.lock":             PYPI,
+	"poetry.lock":              PYPI,
 	"pyproject.toml":           PYPI,
 	"requirements.txt":         PYPI,
 	"Cargo.toml":               CARGO,
@@ -1533,4 +1567,6 @@ var ECOSYSTEM_MAP = map[string]string{
 	"go.work.sum":              GO,
 	"go.mod":                   GO,
 	"go.work":                  GO,
+	"Package.swift":            SWIFT,
+	"Package.resolved":         SWIFT,
 }
@@ -0,0 +1,135 @@
+package url
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// HostRule is how the links of a git hosting service are canonicalized.
+// The links of a host with a rule are https links, without www. nor .git.
+type HostRule struct {
+	// Depth is the number of segments of the path naming a repository, e.g.
+	// 2 for owner/repo, the rest such as /issues is dropped. 0 keeps every
+	// segment, for hosts with nested groups.
+	Depth int
+	// CaseInsensitive hosts serve a repository whatever the case of its
+	// path, the path is lowercased.
+	CaseInsensitive bool
+	// Forge hosts are open to anyone, the projects hosted elsewhere are
+	// often mirrored there.
+	Forge bool
+}
+
+// HostRules are the rules of the hosts.
+var HostRules = map[string]HostRule{
+	"github.com":             {Depth: 2, CaseInsensitive: true, Forge: true},
+	"gitlab.com":             {CaseInsensitive: true, Forge: true},
+	"bitbucket.org":          {Depth: 2, CaseInsensitive: true, Forge: true},
+	"codeberg.org":           {Depth: 2, CaseInsensitive: true, Forge: true},
+	"gitee.com":              {Depth: 2, Forge: true},
+	"salsa.debian.org":       {},
+	"gitlab.gnome.org":       {},
+	"gitlab.freedesktop.org": {},
+	"invent.kde.org":         {},
+}
+
+// the path of a repository ends before these segments, e.g. in
+// https://github.com/owner/repo/tree/master or on GitLab and cgit
+var pathSuffixes = []string{"/-/", "/tree/", "/blob/", "/commits/", "/releases/"}
+
+var defaultPorts = map[string]int{"http": 80, "https": 443, "git": 9418}
+
+// hostRule returns the rule of host and the host of the rule, www.github.com
+// is github.com.
+func hostRule(host string) (HostRule, string, bool) {
+	host = strings.TrimPrefix(host, "www.")
+	rule, ok := HostRules[host]
+	return rule, host, ok
+}
+
+// Canonicalize returns the canonical spelling of a git link, so that a
+// repository linked as git@github.com:Owner/Repo.git,
+// http://www.github.com/owner/repo/tree/master or github.com/owner/repo is
+// collected and scored once, as https://github.com/owner/repo.
+//
+// The links of a host without rule keep their scheme and path, only their
+// credentials, query, fragment and trailing slash are dropped and their
+// host lowercased. Links over ssh become https links, as only those can
+// be cloned without an account.
+func Canonicalize(link string) (string, error) {
+	link = strings.TrimSpace(link)
+	if len(link) < 3 {
+		return "", fmt.Errorf("not a git link: %q", link)
+	}
+	if !strings.Contains(link, "://") && !IsSsh(link) {
+		host, _, _ := strings.Cut(link, "/")
+		if _, _, ok := hostRule(strings.ToLower(host)); !ok {
+			return "", fmt.Errorf("not a git link: %q", link)
+		}
+		link = "https://" + link
+	}
+	u, err := ParseURL(link)
+	if err != nil {
+		return "", err
+	}
+	if u.Protocol == "file" || u.Resource == "" {
+		return "", fmt.Errorf("not a git link: %q", link)
+	}
+
+	scheme := u.Protocol
+	switch scheme {
+	case "http", "https", "git":
+	case "ssh", "git+ssh", "ssh+git":
+		scheme = "https"
+	default:
+		return "", fmt.Errorf("unsupported scheme %q in %q", scheme, link)
+	}
+	host := strings.ToLower(u.Resource)
+	path := u.Pathname + "/"
+	for _, suffix := range pathSuffixes {
+		if i := strings.Index(path, suffix); i > 0 {
+			path = path[:i]
+		}
+	}
+	path = strings.TrimRight(path, "/")
+
+	rule, ruleHost, ok := hostRule(host)
+	if !ok {
+		if u.Port != nil && scheme == u.Protocol && *u.Port != defaultPorts[scheme] {
+			host += ":" + strconv.Itoa(*u.Port)
+		}
+		if path == "" {
+			return "", fmt.Errorf("no repository in %q", link)
+		}
+		return scheme + "://" + host + path, nil
+	}
+
+	segments := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	if rule.Depth > 0 {
+		if len(segments) < rule.Depth {
+			return "", fmt.Errorf("no repository in %q", link)
+		}
+		segments = segments[:rule.Depth]
+	}
+	last := len(segments) - 1
+	segments[last] = strings.TrimSuffix(segments[last], ".git")
+	if segments[last] == "" {
+		return "", fmt.Errorf("no repository in %q", link)
+	}
+	path = strings.Join(segments, "/")
+	if rule.CaseInsensitive {
+		path = strings.ToLower(path)
+	}
+	return "https://" + ruleHost + "/" + path, nil
+}
+
+// IsForge reports whether link is hosted on a forge, see HostRule.Forge.
+func IsForge(link string) bool {
+	u, err := ParseURL(link)
+	if err != nil {
+		return false
+	}
+	rule, _, ok := hostRule(strings.ToLower(u.Resource))
+	return ok && rule.Forge
+}
@@ -0,0 +1,77 @@
+package graph
+
+import (
+	"math/bits"
+	"runtime"
+	"sort"
+	"sync"
+)
+
+// AncestorCounts returns, for every node, the number of nodes from which it
+// is reachable, itself included. In a dependency graph this is the number of
+// packages depending on a package directly or transitively.
+//
+// The counts are computed on the condensation of the graph. The nodes are
+// split in blocks of 64 and, for every block, a bitset of the nodes of the
+// block reaching each component is propagated once along the condensation
+// in topological order, so the cost is O(V/64 * (V+E)) and no closure is
+// materialized. Blocks are spread over GOMAXPROCS goroutines.
+func (g *Graph) AncestorCounts(comps *Components) []int {
+	n := g.Len()
+	ret := make([]int, n)
+	if n == 0 {
+		return ret
+	}
+	succ := g.condense(comps)
+
+	// nodes in descending component order, so a block only reaches the
+	// components numbered below its first node
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return comps.Of[order[i]] > comps.Of[order[j]]
+	})
+
+	blocks := make(chan int)
+	counts := make([][]int, runtime.GOMAXPROCS(0))
+	var wg sync.WaitGroup
+	for w := range counts {
+		counts[w] = make([]int, comps.Len())
+		wg.Add(1)
+		go func(count []int) {
+			defer wg.Done()
+			mask := make([]uint64, comps.Len())
+			for start := range blocks {
+				end := min(start+64, n)
+				top := comps.Of[order[start]]
+				clear(mask[:top+1])
+				for i, v := range order[start:end] {
+					mask[comps.Of[v]] |= 1 << i
+				}
+				for c := top; c >= 0; c-- {
+					if mask[c] == 0 {
+						continue
+					}
+					for _, d := range succ[c] {
+						mask[d] |= mask[c]
+					}
+					count[c] += bits.OnesCount64(mask[c])
+				}
+			}
+		}(counts[w])
+	}
+	for start := 0; start < n; start += 64 {
+		blocks <- start
+	}
+	close(blocks)
+	wg.Wait()
+
+	for v := range ret {
+		for _, count := range counts {
+			ret[v] += count[comps.Of[v]]
+		}
+	}
+	return ret
+}
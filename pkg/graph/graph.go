@@ -0,0 +1,132 @@
+// Package graph implements the dependency graph analyses shared by the
+// collectors, on a compact adjacency in the compressed sparse row format.
+package graph
+
+import (
+	"sort"
+)
+
+// Graph is an immutable directed graph. Nodes are numbered from 0 in the
+// lexical order of their names, and the out-neighbours of node i are
+// targets[offsets[i]:offsets[i+1]], sorted and without duplicates.
+type Graph struct {
+	names   []string
+	index   map[string]int
+	offsets []int
+	targets []int
+}
+
+// Build builds the graph of nodes, where neighbours returns the names of the
+// out-neighbours of a node, e.g. the direct dependencies of a package. Edges
+// to names which are not nodes are dropped.
+func Build[V any](nodes map[string]V, neighbours func(name string, node V) []string) *Graph {
+	g := &Graph{
+		names:   make([]string, 0, len(nodes)),
+		index:   make(map[string]int, len(nodes)),
+		offsets: make([]int, 1, len(nodes)+1),
+		targets: make([]int, 0),
+	}
+	for name := range nodes {
+		g.names = append(g.names, name)
+	}
+	sort.Strings(g.names)
+	for i, name := range g.names {
+		g.index[name] = i
+	}
+
+	for _, name := range g.names {
+		start := len(g.targets)
+		for _, neighbour := range neighbours(name, nodes[name]) {
+			if j, ok := g.index[neighbour]; ok {
+				g.targets = append(g.targets, j)
+			}
+		}
+		row := g.targets[start:]
+		sort.Ints(row)
+		g.targets = g.targets[:start+len(compact(row))]
+		g.offsets = append(g.offsets, len(g.targets))
+	}
+	return g
+}
+
+// FromAdjacency builds the graph of an adjacency list, see Build.
+func FromAdjacency(adjacency map[string][]string) *Graph {
+	return Build(adjacency, func(_ string, neighbours []string) []string {
+		return neighbours
+	})
+}
+
+// compact removes the consecutive duplicates of a sorted slice in place.
+func compact(s []int) []int {
+	if len(s) == 0 {
+		return s
+	}
+	n := 1
+	for _, v := range s[1:] {
+		if v != s[n-1] {
+			s[n] = v
+			n++
+		}
+	}
+	return s[:n]
+}
+
+// Len returns the number of nodes.
+func (g *Graph) Len() int {
+	return len(g.names)
+}
+
+// Edges returns the number of edges.
+func (g *Graph) Edges() int {
+	return len(g.targets)
+}
+
+// Name returns the name of node i.
+func (g *Graph) Name(i int) string {
+	return g.names[i]
+}
+
+// Index returns the node named name, and false if there is none.
+func (g *Graph) Index(name string) (int, bool) {
+	i, ok := g.index[name]
+	return i, ok
+}
+
+// Neighbours returns the out-neighbours of node i. The slice is shared with
+// the graph and must not be modified.
+func (g *Graph) Neighbours(i int) []int {
+	return g.targets[g.offsets[i]:g.offsets[i+1]]
+}
+
+// OutDegree returns the number of out-neighbours of node i.
+func (g *Graph) OutDegree(i int) int {
+	return g.offsets[i+1] - g.offsets[i]
+}
+
+// Transpose returns the graph with every edge reversed, e.g. the reverse
+// dependencies of a dependency graph.
+func (g *Graph) Transpose() *Graph {
+	n := g.Len()
+	t := &Graph{
+		names:   g.names,
+		index:   g.index,
+		offsets: make([]int, n+1),
+		targets: make([]int, len(g.targets)),
+	}
+	for _, j := range g.targets {
+		t.offsets[j+1]++
+	}
+	for i := 0; i < n; i++ {
+		t.offsets[i+1] += t.offsets[i]
+	}
+	next := make([]int, n)
+	copy(next, t.offsets[:n])
+	// sources are visited in increasing order, so every row is sorted
+	for i := 0; i < n; i++ {
+		for _, j := range g.Neighbours(i) {
+			t.targets[next[j]] = i
+			next[j]++
+		}
+	}
+	return t
+}
@@ -0,0 +1,177 @@
+package graph
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuild(t *testing.T) {
+	g := FromAdjacency(map[string][]string{
+		"c": {"a", "a", "b", "missing"},
+		"b": {"a"},
+		"a": nil,
+	})
+
+	require.Equal(t, 3, g.Len())
+	require.Equal(t, 3, g.Edges())
+	require.Equal(t, "a", g.Name(0))
+	c, ok := g.Index("c")
+	require.True(t, ok)
+	require.Equal(t, []int{0, 1}, g.Neighbours(c))
+	require.Equal(t, 0, g.OutDegree(0))
+	_, ok = g.Index("missing")
+	require.False(t, ok)
+
+	rev := g.Transpose()
+	require.Equal(t, []int{1, 2}, rev.Neighbours(0))
+	require.Equal(t, []int{2}, rev.Neighbours(1))
+	require.Empty(t, rev.Neighbours(2))
+}
+
+func TestPageRank(t *testing.T) {
+	// b and c depend on a, a depends on nothing and is dangling
+	g := FromAdjacency(map[string][]string{
+		"a": nil,
+		"b": {"a"},
+		"c": {"a"},
+	})
+	result, err := g.PageRank(DefaultPageRankOptions())
+	require.NoError(t, err)
+	require.True(t, result.Converged)
+
+	ranks := result.Map(g)
+	require.InDelta(t, 1, ranks["a"]+ranks["b"]+ranks["c"], 1e-9)
+	require.Greater(t, ranks["a"], ranks["b"])
+	require.InDelta(t, ranks["b"], ranks["c"], 1e-12)
+	// r_b = 0.15/3 + 0.85*r_a/3 and r_a = 0.15/3 + 0.85*(r_a/3 + 2*r_b)
+	require.InDelta(t, 0.135/0.235, ranks["a"], 1e-9)
+}
+
+func TestPageRankCycle(t *testing.T) {
+	g := FromAdjacency(map[string][]string{
+		"a": {"b"},
+		"b": {"a"},
+	})
+	result, err := g.PageRank(DefaultPageRankOptions())
+	require.NoError(t, err)
+	require.InDelta(t, 0.5, result.Ranks[0], 1e-9)
+	require.InDelta(t, 0.5, result.Ranks[1], 1e-9)
+}
+
+func TestPageRankPersonalization(t *testing.T) {
+	g := FromAdjacency(map[string][]string{
+		"a": nil,
+		"b": nil,
+		"c": {"a"},
+	})
+	opts := DefaultPageRankOptions()
+	opts.Personalization = map[string]float64{"c": 2}
+	result, err := g.PageRank(opts)
+	require.NoError(t, err)
+
+	ranks := result.Map(g)
+	require.InDelta(t, 1, ranks["a"]+ranks["b"]+ranks["c"], 1e-9)
+	require.Zero(t, ranks["b"])
+	require.Greater(t, ranks["c"], ranks["a"])
+
+	opts.Personalization = map[string]float64{"missing": 1}
+	_, err = g.PageRank(opts)
+	require.Error(t, err)
+}
+
+func TestPageRankEmpty(t *testing.T) {
+	result, err := FromAdjacency(nil).PageRank(DefaultPageRankOptions())
+	require.NoError(t, err)
+	require.Empty(t, result.Ranks)
+}
+
+func TestStronglyConnectedComponents(t *testing.T) {
+	// a -> b -> c -> a is a cycle, d depends on it, e depends on itself
+	g := FromAdjacency(map[string][]string{
+		"a": {"b"},
+		"b": {"c"},
+		"c": {"a"},
+		"d": {"a", "e"},
+		"e": {"e"},
+	})
+	comps := g.StronglyConnectedComponents()
+	require.Equal(t, 3, comps.Len())
+	require.Equal(t, comps.Of[0], comps.Of[1])
+	require.Equal(t, comps.Of[0], comps.Of[2])
+	// reverse topological order
+	require.Greater(t, comps.Of[3], comps.Of[0])
+	require.Greater(t, comps.Of[3], comps.Of[4])
+
+	require.Equal(t, [][]int{{0, 1, 2}, {4}}, g.Cycles(comps))
+	require.Equal(t, []int{4, 4, 4, 1, 2}, g.AncestorCounts(comps))
+}
+
+func TestAncestorCounts(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	adjacency := make(map[string][]string)
+	for i := 0; i < 300; i++ {
+		deps := make([]string, 0)
+		for j := rng.Intn(4); j > 0; j-- {
+			deps = append(deps, fmt.Sprint(rng.Intn(300)))
+		}
+		adjacency[fmt.Sprint(i)] = deps
+	}
+	g := FromAdjacency(adjacency)
+	counts := g.AncestorCounts(g.StronglyConnectedComponents())
+
+	// the number of nodes reaching every node, by a search from every node
+	want := make([]int, g.Len())
+	for s := 0; s < g.Len(); s++ {
+		visited := map[int]bool{s: true}
+		queue := []int{s}
+		for len(queue) > 0 {
+			v := queue[0]
+			queue = queue[1:]
+			want[v]++
+			for _, w := range g.Neighbours(v) {
+				if !visited[w] {
+					visited[w] = true
+					queue = append(queue, w)
+				}
+			}
+		}
+	}
+	require.Equal(t, want, counts)
+}
+
+func TestStronglyConnectedComponentsDeepChain(t *testing.T) {
+	adjacency := make(map[string][]string)
+	n := 200000
+	for i := 0; i < n; i++ {
+		adjacency[fmt.Sprintf("%06d", i)] = []string{fmt.Sprintf("%06d", i+1)}
+	}
+	g := FromAdjacency(adjacency)
+	comps := g.StronglyConnectedComponents()
+	require.Equal(t, n, comps.Len())
+	require.Empty(t, g.Cycles(comps))
+
+	counts := g.AncestorCounts(comps)
+	require.Equal(t, 1, counts[0])
+	require.Equal(t, n, counts[n-1])
+}
+
+func BenchmarkAncestorCounts(b *testing.B) {
+	rng := rand.New(rand.NewSource(1))
+	adjacency := make(map[string][]string)
+	n := 100000
+	for i := 0; i < n; i++ {
+		deps := make([]string, 0)
+		for j := rng.Intn(8); j > 0; j-- {
+			deps = append(deps, fmt.Sprint(rng.Intn(n)))
+		}
+		adjacency[fmt.Sprint(i)] = deps
+	}
+	g := FromAdjacency(adjacency)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		g.AncestorCounts(g.StronglyConnectedComponents())
+	}
+}
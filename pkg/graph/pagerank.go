@@ -0,0 +1,139 @@
+package graph
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// PageRankOptions configures PageRank.
+type PageRankOptions struct {
+	// Damping is the probability of following an edge, usually 0.85
+	Damping float64
+	// Tolerance stops the iterations once the L1 norm of the change of the
+	// ranks falls below it
+	Tolerance float64
+	// MaxIterations bounds the iterations when the ranks do not converge
+	MaxIterations int
+	// Personalization is the teleport weight of the nodes, which do not
+	// need to sum to 1. Nodes missing from it have a weight of 0. A nil
+	// personalization teleports uniformly.
+	Personalization map[string]float64
+}
+
+// DefaultPageRankOptions returns the options used by the collectors.
+func DefaultPageRankOptions() PageRankOptions {
+	return PageRankOptions{
+		Damping:       0.85,
+		Tolerance:     1e-9,
+		MaxIterations: 100,
+	}
+}
+
+// PageRankResult is the rank of every node, indexed as the nodes of the
+// graph, and the number of iterations run.
+type PageRankResult struct {
+	Ranks      []float64
+	Iterations int
+	Converged  bool
+}
+
+// Map returns the ranks by node name.
+func (r *PageRankResult) Map(g *Graph) map[string]float64 {
+	ret := make(map[string]float64, len(r.Ranks))
+	for i, rank := range r.Ranks {
+		ret[g.Name(i)] = rank
+	}
+	return ret
+}
+
+// PageRank computes the PageRank of every node by power iteration. The rank
+// of a dangling node, one without out-neighbours, is redistributed along the
+// personalization so the ranks always sum to 1.
+func (g *Graph) PageRank(opts PageRankOptions) (*PageRankResult, error) {
+	n := g.Len()
+	if opts.Damping < 0 || opts.Damping >= 1 {
+		return nil, fmt.Errorf("invalid damping factor %g", opts.Damping)
+	}
+	result := &PageRankResult{Ranks: make([]float64, n)}
+	if n == 0 {
+		result.Converged = true
+		return result, nil
+	}
+
+	teleport, err := g.teleport(opts.Personalization)
+	if err != nil {
+		return nil, err
+	}
+
+	ranks := result.Ranks
+	for i := range ranks {
+		ranks[i] = 1 / float64(n)
+	}
+	next := make([]float64, n)
+	d := opts.Damping
+
+	for result.Iterations < opts.MaxIterations {
+		result.Iterations++
+
+		var dangling float64
+		for i := range next {
+			next[i] = 0
+		}
+		for i := 0; i < n; i++ {
+			degree := g.OutDegree(i)
+			if degree == 0 {
+				dangling += ranks[i]
+				continue
+			}
+			share := ranks[i] / float64(degree)
+			for _, j := range g.Neighbours(i) {
+				next[j] += share
+			}
+		}
+
+		var delta float64
+		for i := range next {
+			next[i] = d*(next[i]+dangling*teleport[i]) + (1-d)*teleport[i]
+			delta += math.Abs(next[i] - ranks[i])
+		}
+		ranks, next = next, ranks
+
+		if delta < opts.Tolerance {
+			result.Converged = true
+			break
+		}
+	}
+	result.Ranks = ranks
+	return result, nil
+}
+
+// teleport returns the normalized personalization vector of the nodes.
+func (g *Graph) teleport(personalization map[string]float64) ([]float64, error) {
+	n := g.Len()
+	ret := make([]float64, n)
+	if personalization == nil {
+		for i := range ret {
+			ret[i] = 1 / float64(n)
+		}
+		return ret, nil
+	}
+
+	var sum float64
+	for name, weight := range personalization {
+		if weight < 0 || math.IsNaN(weight) || math.IsInf(weight, 0) {
+			return nil, fmt.Errorf("invalid personalization weight %g of %s", weight, name)
+		}
+		if i, ok := g.Index(name); ok {
+			ret[i] = weight
+			sum += weight
+		}
+	}
+	if sum == 0 {
+		return nil, errors.New("personalization has no positive weight on the graph")
+	}
+	for i := range ret {
+		ret[i] /= sum
+	}
+	return ret, nil
+}
@@ -0,0 +1,127 @@
+package graph
+
+import (
+	"sort"
+)
+
+// Components is the strongly connected components of a graph. Components
+// are numbered in reverse topological order: an edge between two components
+// always goes from a higher to a lower number.
+type Components struct {
+	// Of is the component of every node
+	Of []int
+	// Members is the nodes of every component
+	Members [][]int
+}
+
+// Len returns the number of components.
+func (c *Components) Len() int {
+	return len(c.Members)
+}
+
+// StronglyConnectedComponents returns the strongly connected components of
+// the graph with Tarjan's algorithm. The depth first search keeps its own
+// stack, so deep dependency chains do not grow the goroutine stack.
+func (g *Graph) StronglyConnectedComponents() *Components {
+	n := g.Len()
+	comps := &Components{Of: make([]int, n), Members: make([][]int, 0)}
+	// index is the 1-based discovery order of a node, 0 if not visited
+	index := make([]int, n)
+	low := make([]int, n)
+	onStack := make([]bool, n)
+	stack := make([]int, 0)
+
+	type frame struct {
+		node int
+		next int
+	}
+	calls := make([]frame, 0)
+	counter := 0
+	visit := func(v int) {
+		counter++
+		index[v], low[v] = counter, counter
+		stack = append(stack, v)
+		onStack[v] = true
+		calls = append(calls, frame{node: v})
+	}
+
+	for root := 0; root < n; root++ {
+		if index[root] != 0 {
+			continue
+		}
+		visit(root)
+		for len(calls) > 0 {
+			f := &calls[len(calls)-1]
+			v := f.node
+			if neighbours := g.Neighbours(v); f.next < len(neighbours) {
+				w := neighbours[f.next]
+				f.next++
+				if index[w] == 0 {
+					visit(w)
+				} else if onStack[w] {
+					low[v] = min(low[v], index[w])
+				}
+				continue
+			}
+
+			if low[v] == index[v] {
+				i := len(stack) - 1
+				for stack[i] != v {
+					i--
+				}
+				members := make([]int, len(stack)-i)
+				copy(members, stack[i:])
+				sort.Ints(members)
+				for _, w := range members {
+					onStack[w] = false
+					comps.Of[w] = len(comps.Members)
+				}
+				comps.Members = append(comps.Members, members)
+				stack = stack[:i]
+			}
+			calls = calls[:len(calls)-1]
+			if len(calls) > 0 {
+				u := calls[len(calls)-1].node
+				low[u] = min(low[u], low[v])
+			}
+		}
+	}
+	return comps
+}
+
+// Cycles returns the components of comps which are cycles: components of
+// several nodes, and nodes with an edge to themselves.
+func (g *Graph) Cycles(comps *Components) [][]int {
+	ret := make([][]int, 0)
+	for _, members := range comps.Members {
+		if len(members) > 1 || g.hasEdge(members[0], members[0]) {
+			ret = append(ret, members)
+		}
+	}
+	return ret
+}
+
+func (g *Graph) hasEdge(from, to int) bool {
+	neighbours := g.Neighbours(from)
+	i := sort.SearchInts(neighbours, to)
+	return i < len(neighbours) && neighbours[i] == to
+}
+
+// condense returns the out-neighbours of every component of comps in the
+// condensation of the graph, sorted and without duplicates.
+func (g *Graph) condense(comps *Components) [][]int {
+	ret := make([][]int, comps.Len())
+	for c, members := range comps.Members {
+		succ := make([]int, 0)
+		for _, v := range members {
+			for _, w := range g.Neighbours(v) {
+				if d := comps.Of[w]; d != c {
+					succ = append(succ, d)
+				}
+			}
+		}
+		sort.Ints(succ)
+		ret[c] = compact(succ)
+	}
+	return ret
+}
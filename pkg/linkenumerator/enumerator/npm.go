@@ -3,6 +3,8 @@ package enumerator
 import (
 	"encoding/json"
 
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
+	"github.com/HUSTSecLab/OpenSift/pkg/linkenumerator/writer"
 	"github.com/HUSTSecLab/OpenSift/pkg/logger"
 )
 
@@ -50,6 +52,16 @@ func (n *npmEnumerator) Enumerate() error {
 	for _, v := range distinctData {
 		n.writer.Write(v)
 	}
+	if pw, ok := n.writer.(writer.PackageWriter); ok {
+		for name, v := range data {
+			if v == nil || *v == "" {
+				continue
+			}
+			if err := pw.WritePackage(parser.NPM, name, *v); err != nil {
+				logger.Errorf("Npm package %s write failed: %v", name, err)
+			}
+		}
+	}
 	return nil
 }
 
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"sync"
 
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
+	"github.com/HUSTSecLab/OpenSift/pkg/linkenumerator/writer"
 	"github.com/HUSTSecLab/OpenSift/pkg/logger"
 	"github.com/bytedance/gopkg/util/gopool"
 )
@@ -210,6 +212,11 @@ func (p *pypiEnumerator) Enumerate() error {
 				logger.Errorf("Pypi package %s write failed: %v", project.Name, err)
 				return
 			}
+			if pw, ok := p.writer.(writer.PackageWriter); ok {
+				if err := pw.WritePackage(parser.PYPI, pkg.Info.Name, pkg.Info.ProjectUrls.Source); err != nil {
+					logger.Errorf("Pypi package %s write failed: %v", project.Name, err)
+				}
+			}
 			wg.Done()
 		})
 	}
@@ -1,6 +1,10 @@
 package writer
 
 import (
+	"errors"
+	"sync"
+
+	giturl "github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/url"
 	"github.com/HUSTSecLab/OpenSift/pkg/logger"
 	"github.com/HUSTSecLab/OpenSift/pkg/storage"
 	"github.com/HUSTSecLab/OpenSift/pkg/storage/repository"
@@ -13,6 +17,9 @@ type DatabaseWriter struct {
 
 	buffer     []string
 	bufferSize int
+
+	packagesMu sync.Mutex
+	packages   []*repository.RegistryPackage
 }
 
 func NewDatabaseWriter(ctx storage.AppDatabaseContext, tablePrefix string) *DatabaseWriter {
@@ -30,9 +37,15 @@ func (w *DatabaseWriter) Open() error {
 	return repo.BeginTemp()
 }
 
+// Close stores the links buffered, replacing the links of the platform, and
+// the packages buffered. The links are kept as they were if they could not
+// all be stored.
 func (w *DatabaseWriter) Close() error {
-	w.flush()
-	return w.repo.CommitTemp()
+	if err := w.flush(); err != nil {
+		return err
+	}
+	packagesErr := w.flushPackages()
+	return errors.Join(w.repo.CommitTemp(), packagesErr)
 }
 
 func (w *DatabaseWriter) flush() error {
@@ -45,7 +58,14 @@ func (w *DatabaseWriter) flush() error {
 	return nil
 }
 
+// Write buffers the canonical spelling of the link url, see
+// url.Canonicalize, or url itself if it is not a git link.
 func (w *DatabaseWriter) Write(url string) error {
+	if canonical, err := giturl.Canonicalize(url); err == nil {
+		url = canonical
+	} else {
+		logger.Warnf("Keeping link as is: %v", err)
+	}
 	w.buffer = append(w.buffer, url)
 
 	if len(w.buffer) >= w.bufferSize {
@@ -58,3 +78,45 @@ func (w *DatabaseWriter) Write(url string) error {
 
 	return nil
 }
+
+var _ PackageWriter = (*DatabaseWriter)(nil)
+
+// WritePackage buffers the canonical spelling of the link url of the
+// package name, to be stored in registry_packages. Links which are not git
+// links are kept as is, so that they are reviewed as declaring no
+// repository.
+func (w *DatabaseWriter) WritePackage(ecosystem string, name string, url string) error {
+	if canonical, err := giturl.Canonicalize(url); err == nil {
+		url = canonical
+	}
+	w.packagesMu.Lock()
+	defer w.packagesMu.Unlock()
+	w.packages = append(w.packages, &repository.RegistryPackage{
+		Ecosystem: &ecosystem,
+		Name:      &name,
+		GitLink:   &url,
+	})
+	if len(w.packages) >= w.bufferSize {
+		return w.flushPackagesLocked()
+	}
+	return nil
+}
+
+func (w *DatabaseWriter) flushPackages() error {
+	w.packagesMu.Lock()
+	defer w.packagesMu.Unlock()
+	return w.flushPackagesLocked()
+}
+
+func (w *DatabaseWriter) flushPackagesLocked() error {
+	if len(w.packages) == 0 {
+		return nil
+	}
+	err := repository.NewRegistryPackageRepository(w.dbCtx).BatchInsertOrUpdate(w.packages)
+	if err != nil {
+		logger.Errorf("Failed to insert packages: %v", err)
+		return err
+	}
+	w.packages = nil
+	return nil
+}
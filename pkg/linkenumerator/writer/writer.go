@@ -5,3 +5,10 @@ type Writer interface {
 	Close() error
 	Write(url string) error
 }
+
+// PackageWriter is implemented by the writers which record the repository
+// url a registry declares for the package name of ecosystem, named as in
+// the parsers.
+type PackageWriter interface {
+	WritePackage(ecosystem string, name string, url string) error
+}
@@ -10,6 +10,7 @@ import (
 	"strings"
 	"time"
 
+	giturl "github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/url"
 	"github.com/HUSTSecLab/OpenSift/pkg/storage"
 )
 
@@ -54,6 +55,9 @@ func Home2git(ctx storage.AppDatabaseContext, repolist []string, url string, bat
 			if res == "" {
 				continue
 			}
+			if canonical, err := giturl.Canonicalize(res); err == nil {
+				res = canonical
+			}
 			resultMap[repo][packageName] = res
 			if err := writer.Write([]string{repo, packageName, res}); err != nil {
 				fmt.Println("Error writing to CSV:", err)
@@ -64,6 +68,9 @@ func Home2git(ctx storage.AppDatabaseContext, repolist []string, url string, bat
 	}
 	UpdateBatch(db, batchSize, resultMap)
 }
+
+// Check returns the link githubURL redirects to, e.g. after the repository
+// was renamed, empty if it is not found.
 func Check(githubURL string) string {
 	client := &http.Client{
 		Timeout: 30 * time.Second,
@@ -75,7 +82,7 @@ func Check(githubURL string) string {
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode == http.StatusOK {
-		return githubURL
+		return resp.Request.URL.String()
 	}
 	return ""
 }
@@ -0,0 +1,121 @@
+package score
+
+import (
+	"fmt"
+
+	log "github.com/HUSTSecLab/OpenSift/pkg/logger"
+)
+
+// CalculateScores scores every link with profile.
+//
+// Population based normalizations need the statistics of the whole dataset,
+// so the scores are computed in passes: the raw metric values of all links
+// are collected first, then the dimension scores are computed, and finally
+// the dimension scores are combined into the score. Links missing from the
+// metric maps are scored with empty metrics, the maps and profile are not
+// modified.
+func CalculateScores(links []string,
+	gitMetrics map[string]*GitMetadata,
+	distMetrics map[string]*DistScore,
+	langEcoMetrics map[string]*LangEcoScore,
+	profile *Profile, round int) (map[string]*LinkScore, error) {
+
+	dists := make(map[string]*DistScore, len(links))
+	langEcos := make(map[string]*LangEcoScore, len(links))
+	gits := make(map[string]*GitMetadata, len(links))
+
+	for _, link := range links {
+		if d, ok := distMetrics[link]; ok {
+			dists[link] = d
+		} else {
+			dists[link] = NewDistScore()
+		}
+		if l, ok := langEcoMetrics[link]; ok {
+			langEcos[link] = l
+		} else {
+			langEcos[link] = NewLangEcoScore()
+		}
+		if g, ok := gitMetrics[link]; ok {
+			gits[link] = g
+		} else {
+			log.Debugf("No git metadata for %s", link)
+		}
+	}
+
+	// first pass: populations of the raw metrics
+	if profile.NeedsPopulation(DimensionDist) {
+		values := make([]map[string]float64, 0, len(dists))
+		for _, d := range dists {
+			values = append(values, d.MetricValues())
+		}
+		profile = fitMetrics(profile, DimensionDist, values)
+	}
+	if profile.NeedsPopulation(DimensionLangEco) {
+		values := make([]map[string]float64, 0, len(langEcos))
+		for _, l := range langEcos {
+			values = append(values, l.MetricValues())
+		}
+		profile = fitMetrics(profile, DimensionLangEco, values)
+	}
+	if profile.NeedsPopulation(DimensionGitMetadata) {
+		values := make([]map[string]float64, 0, len(gits))
+		for _, g := range gits {
+			values = append(values, g.MetricValues())
+		}
+		profile = fitMetrics(profile, DimensionGitMetadata, values)
+	}
+
+	// second pass: dimension scores
+	ret := make(map[string]*LinkScore, len(links))
+	for _, link := range links {
+		if err := dists[link].CalculateDistScore(profile); err != nil {
+			return nil, fmt.Errorf("failed to calculate dist score of %s: %w", link, err)
+		}
+		if err := langEcos[link].CalculateLangEcoScore(profile); err != nil {
+			return nil, fmt.Errorf("failed to calculate lang eco score of %s: %w", link, err)
+		}
+		gitMetadataScore := NewGitMetadataScore()
+		if g, ok := gits[link]; ok {
+			if err := gitMetadataScore.CalculateGitMetadataScore(g, profile); err != nil {
+				return nil, fmt.Errorf("failed to calculate git metadata score of %s: %w", link, err)
+			}
+		}
+		ret[link] = NewLinkScore(gitMetadataScore, dists[link], langEcos[link], round)
+	}
+
+	// population of the dimension scores
+	for dimension := range ProfileMetrics {
+		if !profile.NeedsPopulation(dimension) {
+			continue
+		}
+		values := make([]float64, 0, len(ret))
+		for _, linkScore := range ret {
+			values = append(values, linkScore.DimensionValues()[dimension])
+		}
+		profile = profile.Fit(dimension, dimension, NewPopulation(values))
+	}
+
+	// last pass: final scores
+	for link, linkScore := range ret {
+		if err := linkScore.CalculateScore(profile); err != nil {
+			return nil, fmt.Errorf("failed to calculate score of %s: %w", link, err)
+		}
+	}
+	return ret, nil
+}
+
+// fitMetrics returns profile fitted to the values of every metric of
+// dimension.
+func fitMetrics(profile *Profile, dimension string, values []map[string]float64) *Profile {
+	for _, metric := range ProfileMetrics[dimension] {
+		if !profile.HasMetric(dimension, metric) {
+			continue
+		}
+		population := make([]float64, 0, len(values))
+		for _, v := range values {
+			population = append(population, v[metric])
+		}
+		profile = profile.Fit(dimension, metric, NewPopulation(population))
+	}
+	return profile
+}
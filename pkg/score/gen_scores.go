@@ -1,6 +1,7 @@
 package score
 
 import (
+	"fmt"
 	"math"
 	"time"
 
@@ -20,6 +21,20 @@ type LinkScore struct {
 	DistScore DistScore
 	Score     float64
 	Round     int
+	// Profile is the scoring model used by the last CalculateScore call
+	Profile *Profile
+	// Contributions of every dimension to Score
+	Contributions []*MetricContribution
+}
+
+// MetricContribution records how a single metric contributes to a score.
+type MetricContribution struct {
+	Dimension    string
+	Metric       string
+	Value        float64
+	Normalized   float64
+	Weight       float64
+	Contribution float64
 }
 
 type GitMetadata struct {
@@ -29,11 +44,18 @@ type GitMetadata struct {
 	ContributorCount int
 	CommitFrequency  float64
 	Org_Count        int
+	// Activity holds the activity metrics collected, see ActivityMetrics
+	Activity map[string]int
+	// Releases holds the release metrics collected, see ReleaseMetrics
+	Releases map[string]float64
+	// LastRelease is the time of the last release, zero if there is none
+	LastRelease time.Time
 }
 
 type GitMetadataScore struct {
 	GitMetrics       []*repository.GitMetric
 	GitMetadataScore float64
+	Contributions    []*MetricContribution
 }
 
 type DistMetadata struct {
@@ -59,6 +81,7 @@ type DistScore struct {
 	downloads_3m     int
 	DistPageRank     float64
 	DistScore        float64
+	Contributions    []*MetricContribution
 }
 
 type LangEcoScore struct {
@@ -66,77 +89,11 @@ type LangEcoScore struct {
 	LangEcoImpact   float64
 	LangEcoPageRank float64
 	LangEcoScore    float64
+	Contributions   []*MetricContribution
 }
 
 var SigmoidWeight = 1.2
 
-// Define weights (αi) and max thresholds (Ti)
-var weights = map[string]map[string]float64{
-	"gitMetadataScore": {
-		"created_since":     1,
-		"updated_since":     -1,
-		"contributor_count": 2,
-		"commit_frequency":  1,
-		"org_count":         1,
-		"gitMetadataScore":  0.2,
-	},
-	"distScore": {
-		"dist_impact":   1,
-		"dist_pagerank": 1,
-		"downloads_3m":  0.5,
-		"distScore":     0.5,
-	},
-	"langEcoScore": {
-		"lang_eco_impact":   1,
-		"lang_eco_pagerank": 1,
-		"langEcoScore":      0.3,
-	},
-}
-
-var thresholds = map[string]map[string]map[string]float64{
-	"log": {
-		"gitMetadataScore": {
-			"created_since":     120,
-			"updated_since":     120,
-			"contributor_count": 40000,
-			"commit_frequency":  1000,
-			"org_count":         8400,
-			"gitMetadataScore":  5,
-		},
-		"distScore": {
-			"dist_impact":   22,
-			"dist_pagerank": 3,
-			"distScore":     1.5,
-		},
-		"langEcoScore": {
-			"lang_eco_impact":   1,
-			"lang_eco_pagerank": 0.0002,
-			"langEcoScore":      1.3,
-		},
-	},
-	"sigmoid": {
-		"gitMetadataScore": {
-			"created_since":     120,
-			"updated_since":     120,
-			"contributor_count": 10000,
-			"commit_frequency":  1000,
-			"org_count":         5000,
-			"gitMetadataScore":  5,
-		},
-		"distScore": {
-			"dist_impact":   6,
-			"dist_pagerank": 0.5,
-			"downloads_3m":  1700000,
-			"distScore":     1.5,
-		},
-		"langEcoScore": {
-			"lang_eco_impact":   0.1,
-			"lang_eco_pagerank": 0.0001,
-			"langEcoScore":      1.3,
-		},
-	},
-}
-
 var PackageList = map[repository.DistType]int{
 	repository.Debian:   0,
 	repository.Arch:     0,
@@ -158,6 +115,13 @@ var PackageWeight = map[repository.LangEcosystemType]float64{
 	repository.Pypi:  1,
 	repository.NuGet: 1,
 	repository.Cargo: 1,
+
+	repository.Packagist: 1,
+	repository.RubyGems:  1,
+	repository.Hex:       1,
+	repository.Conan:     1,
+	repository.Conda:     1,
+	repository.SwiftPM:   1,
 }
 
 func (langEcoMetadata *LangEcoMetadata) ParseLangEcoMetadata(langEcosystem *repository.LangEcosystem) {
@@ -194,63 +158,177 @@ func (gitMetadata *GitMetadata) ParseMetadata(gitMetic *repository.GitMetric) {
 	if !sqlutil.IsNull(gitMetic.OrgCount) {
 		gitMetadata.Org_Count = **gitMetic.OrgCount
 	}
+	activity := map[string]**int{
+		"commits_30d":          gitMetic.Commits30d,
+		"authors_30d":          gitMetic.Authors30d,
+		"orgs_30d":             gitMetic.Orgs30d,
+		"maintainers_30d":      gitMetic.Maintainers30d,
+		"commits_90d":          gitMetic.Commits90d,
+		"authors_90d":          gitMetic.Authors90d,
+		"orgs_90d":             gitMetic.Orgs90d,
+		"maintainers_90d":      gitMetic.Maintainers90d,
+		"commits_365d":         gitMetic.Commits365d,
+		"authors_365d":         gitMetic.Authors365d,
+		"orgs_365d":            gitMetic.Orgs365d,
+		"maintainers_365d":     gitMetic.Maintainers365d,
+		"commits_lifetime":     gitMetic.CommitsLifetime,
+		"authors_lifetime":     gitMetic.AuthorsLifetime,
+		"orgs_lifetime":        gitMetic.OrgsLifetime,
+		"maintainers_lifetime": gitMetic.MaintainersLifetime,
+	}
+	gitMetadata.Activity = make(map[string]int)
+	for metric, value := range activity {
+		if !sqlutil.IsNull(value) {
+			gitMetadata.Activity[metric] = **value
+		}
+	}
+	gitMetadata.Releases = make(map[string]float64)
+	for metric, value := range map[string]**int{
+		MetricSemverTags:   gitMetic.SemverTags,
+		MetricReleases365d: gitMetic.Releases365d,
+	} {
+		if !sqlutil.IsNull(value) {
+			gitMetadata.Releases[metric] = float64(**value)
+		}
+	}
+	if !sqlutil.IsNull(gitMetic.MedianReleaseInterval) {
+		gitMetadata.Releases[MetricMedianReleaseInterval] = **gitMetic.MedianReleaseInterval
+	}
+	if !sqlutil.IsNull(gitMetic.LastRelease) {
+		gitMetadata.LastRelease = **gitMetic.LastRelease
+	}
+}
+
+// MetricValues returns the raw value of every metric of the dimension.
+func (langEcoScore *LangEcoScore) MetricValues() map[string]float64 {
+	return map[string]float64{
+		MetricLangEcoImpact:   langEcoScore.LangEcoImpact,
+		MetricLangEcoPageRank: langEcoScore.LangEcoPageRank,
+	}
 }
 
-func (langEcoScore *LangEcoScore) CalculateLangEcoScore(normalization string) {
-	langEcoScore.LangEcoScore = weights["langEcoScore"]["lang_eco_impact"]*PerformOperation(normalization, langEcoScore.LangEcoImpact, thresholds[normalization]["langEcoScore"]["lang_eco_impact"]) + weights["langEcoScore"]["lang_eco_pagerank"]*PerformOperation(normalization, langEcoScore.LangEcoPageRank, thresholds[normalization]["langEcoScore"]["lang_eco_pagerank"])
+func (langEcoScore *LangEcoScore) CalculateLangEcoScore(profile *Profile) error {
+	contributions, err := profile.ExplainMetrics(DimensionLangEco, langEcoScore.MetricValues())
+	if err != nil {
+		return err
+	}
+	langEcoScore.Contributions = contributions
+	langEcoScore.LangEcoScore = sumContributions(contributions)
+	return nil
 }
 
 func NewLangEcoScore() *LangEcoScore {
 	return &LangEcoScore{}
 }
 
-func (gitMetadataScore *GitMetadataScore) CalculateGitMetadataScore(gitMetadata *GitMetadata, normalization string) {
-	var score float64
-	var createdSinceScore, updatedSinceScore, contributorCountScore, commitFrequencyScore, orgCountScore float64
-
-	monthsSinceCreation := time.Since(gitMetadata.CreatedSince).Hours() / (24 * 30)
-	createdSinceScore = weights["gitMetadataScore"]["created_since"] * PerformOperation(normalization, monthsSinceCreation, thresholds[normalization]["gitMetadataScore"]["created_since"])
-	score += createdSinceScore
-
-	monthsSinceUpdate := time.Since(gitMetadata.UpdatedSince).Hours() / (24 * 30)
-	updatedSinceScore = weights["gitMetadataScore"]["updated_since"] * PerformOperation(normalization, monthsSinceUpdate, thresholds[normalization]["gitMetadataScore"]["updated_since"])
-	score += updatedSinceScore
-
-	contributorCountScore = weights["gitMetadataScore"]["contributor_count"] * PerformOperation(normalization, float64(gitMetadata.ContributorCount), thresholds[normalization]["gitMetadataScore"]["contributor_count"])
-	score += contributorCountScore
-
-	commitFrequencyScore = weights["gitMetadataScore"]["commit_frequency"] * PerformOperation(normalization, gitMetadata.CommitFrequency, thresholds[normalization]["gitMetadataScore"]["commit_frequency"])
-	score += commitFrequencyScore
+// MetricValues returns the raw value of every metric of the dimension,
+// the ages are measured in months and the release intervals in days.
+func (gitMetadata *GitMetadata) MetricValues() map[string]float64 {
+	ret := map[string]float64{
+		MetricCreatedSince:     time.Since(gitMetadata.CreatedSince).Hours() / (24 * 30),
+		MetricUpdatedSince:     time.Since(gitMetadata.UpdatedSince).Hours() / (24 * 30),
+		MetricContributorCount: float64(gitMetadata.ContributorCount),
+		MetricCommitFrequency:  gitMetadata.CommitFrequency,
+		MetricOrgCount:         float64(gitMetadata.Org_Count),
+	}
+	for metric, value := range gitMetadata.Activity {
+		ret[metric] = float64(value)
+	}
+	if _, ok := gitMetadata.Releases[MetricSemverTags]; ok {
+		// a repository never released, or released once, is scored as if
+		// it was created by its last release
+		age := time.Since(gitMetadata.CreatedSince).Hours() / 24
+		ret[MetricDaysSinceLastRelease] = age
+		ret[MetricMedianReleaseInterval] = age
+		for metric, value := range gitMetadata.Releases {
+			ret[metric] = value
+		}
+		if !gitMetadata.LastRelease.IsZero() {
+			ret[MetricDaysSinceLastRelease] = time.Since(gitMetadata.LastRelease).Hours() / 24
+		}
+	}
+	return ret
+}
 
-	orgCountScore = weights["gitMetadataScore"]["org_count"] * PerformOperation(normalization, float64(gitMetadata.Org_Count), thresholds[normalization]["gitMetadataScore"]["org_count"])
-	score += orgCountScore
+func (gitMetadataScore *GitMetadataScore) CalculateGitMetadataScore(gitMetadata *GitMetadata, profile *Profile) error {
+	contributions, err := profile.ExplainMetrics(DimensionGitMetadata, gitMetadata.MetricValues())
+	if err != nil {
+		return err
+	}
 
-	gitMetadataScore.GitMetadataScore = score
+	gitMetadataScore.Contributions = contributions
+	gitMetadataScore.GitMetadataScore = sumContributions(contributions)
 	gitMetadataScore.GitMetrics = []*repository.GitMetric{
 		{
 			ID: sqlutil.ToData(gitMetadata.Id),
 		},
 	}
+	return nil
 }
 
 func NewGitMetadata() *GitMetadata {
 	return &GitMetadata{}
 }
 
-func (distScore *DistScore) CalculateDistScore(normalization string) {
-	distScore.DistScore = weights["distScore"]["dist_impact"]*PerformOperation(normalization, distScore.DistImpact, thresholds[normalization]["distScore"]["dist_impact"]) + weights["distScore"]["dist_pagerank"]*PerformOperation(normalization, distScore.DistPageRank, thresholds[normalization]["distScore"]["dist_pagerank"])
+// MetricValues returns the raw value of every metric of the dimension.
+func (distScore *DistScore) MetricValues() map[string]float64 {
+	return map[string]float64{
+		MetricDistImpact:   distScore.DistImpact,
+		MetricDistPageRank: distScore.DistPageRank,
+	}
 }
 
-func (linkScore *LinkScore) CalculateScore(normalization string) {
-	score := 0.0
-
-	score += weights["gitMetadataScore"]["gitMetadataScore"] * PerformOperation(normalization, linkScore.GitMetadataScore.GitMetadataScore, thresholds[normalization]["gitMetadataScore"]["gitMetadataScore"]) * 100
-
-	score += weights["langEcoScore"]["langEcoScore"] * PerformOperation(normalization, linkScore.LangEcoScore.LangEcoScore, thresholds[normalization]["langEcoScore"]["langEcoScore"]) * 100
+func (distScore *DistScore) CalculateDistScore(profile *Profile) error {
+	contributions, err := profile.ExplainMetrics(DimensionDist, distScore.MetricValues())
+	if err != nil {
+		return err
+	}
+	distScore.Contributions = contributions
+	distScore.DistScore = sumContributions(contributions)
+	return nil
+}
 
-	score += weights["distScore"]["distScore"] * PerformOperation(normalization, linkScore.DistScore.DistScore, thresholds[normalization]["distScore"]["distScore"]) * 100
+// DimensionValues returns the aggregated score of every dimension.
+func (linkScore *LinkScore) DimensionValues() map[string]float64 {
+	return map[string]float64{
+		DimensionGitMetadata: linkScore.GitMetadataScore.GitMetadataScore,
+		DimensionLangEco:     linkScore.LangEcoScore.LangEcoScore,
+		DimensionDist:        linkScore.DistScore.DistScore,
+	}
+}
 
-	linkScore.Score = score
+func (linkScore *LinkScore) CalculateScore(profile *Profile) error {
+	values := linkScore.DimensionValues()
+	linkScore.Contributions = make([]*MetricContribution, 0, len(values))
+	for _, dimension := range []string{DimensionGitMetadata, DimensionLangEco, DimensionDist} {
+		c, err := profile.ExplainDimension(dimension, values[dimension])
+		if err != nil {
+			return err
+		}
+		linkScore.Contributions = append(linkScore.Contributions, c)
+	}
+	linkScore.Score = sumContributions(linkScore.Contributions)
+	linkScore.Profile = profile
+	return nil
+}
+
+// Breakdown returns the contributions of every metric and every dimension
+// to the score, in the order they are computed.
+func (linkScore *LinkScore) Breakdown() []*MetricContribution {
+	ret := make([]*MetricContribution, 0)
+	ret = append(ret, linkScore.GitMetadataScore.Contributions...)
+	ret = append(ret, linkScore.DistScore.Contributions...)
+	ret = append(ret, linkScore.LangEcoScore.Contributions...)
+	ret = append(ret, linkScore.Contributions...)
+	return ret
+}
+
+func sumContributions(contributions []*MetricContribution) float64 {
+	sum := 0.0
+	for _, c := range contributions {
+		sum += c.Contribution
+	}
+	return sum
 }
 
 func NewGitMetadataScore() *GitMetadataScore {
@@ -286,19 +364,35 @@ func Sigmoid(value, threshold float64) float64 {
 	return 1 / (1 + SigmoidWeight*math.Exp(-1*(value-threshold)))
 }
 
-func PerformOperation(flag string, value, threshold float64) float64 {
+// IsSupportedNormalization reports whether flag is a known normalization,
+// either handled by PerformOperation or population based.
+func IsSupportedNormalization(flag string) bool {
+	switch flag {
+	case NormalizationLog, NormalizationSigmoid:
+		return true
+	default:
+		return IsPopulationNormalization(flag)
+	}
+}
+
+// PerformOperation normalizes value with the normalizations which do not
+// depend on the population, see NormalizeWithPopulation for the others.
+func PerformOperation(flag string, value, threshold float64) (float64, error) {
 	switch flag {
-	case "log":
-		return LogNormalize(value, threshold)
-	case "sigmoid":
-		return Sigmoid(value, threshold)
+	case NormalizationLog:
+		return LogNormalize(value, threshold), nil
+	case NormalizationSigmoid:
+		return Sigmoid(value, threshold), nil
 	default:
-		log.Fatalf("Unknown flag: %s", flag)
-		return 0
+		if IsPopulationNormalization(flag) {
+			return 0, fmt.Errorf("normalization %s requires population statistics", flag)
+		}
+		return 0, fmt.Errorf("unknown normalization: %s", flag)
 	}
 }
 
 func FetchGitMetrics(ac storage.AppDatabaseContext) map[string]*GitMetadata {
+	aliases := FetchGitLinkAliases(ac)
 	repo := repository.NewGitMetricsRepository(ac)
 	linksIter, err := repo.Query()
 	linksMap := make(map[string]*GitMetadata)
@@ -308,12 +402,56 @@ func FetchGitMetrics(ac storage.AppDatabaseContext) map[string]*GitMetadata {
 	for link := range linksIter {
 		gitMetadata := NewGitMetadata()
 		gitMetadata.ParseMetadata(link)
-		linksMap[*link.GitLink] = gitMetadata
+		gitLink := resolveGitLink(aliases, *link.GitLink)
+		// the metrics collected under the canonical link win over those
+		// collected under an alias before it was known
+		if _, ok := linksMap[gitLink]; ok && gitLink != *link.GitLink {
+			continue
+		}
+		linksMap[gitLink] = gitMetadata
 	}
 	return linksMap
 }
 
+// FetchGitLinkAliases returns the canonical link of every alias and of every
+// copy scored as it, so that the metadata of a repository linked under
+// several links, or of its mirrors and confirmed forks, is scored under one.
+func FetchGitLinkAliases(ac storage.AppDatabaseContext) map[string]string {
+	aliases, err := repository.NewGitLinkAliasRepository(ac).QueryAll()
+	if err != nil {
+		log.Fatalf("Failed to fetch git link aliases: %v", err)
+	}
+	copies := FetchGitLinkCopies(ac)
+	for alias, link := range aliases {
+		if canonical, ok := copies[link]; ok {
+			aliases[alias] = canonical
+		}
+	}
+	for link, canonical := range copies {
+		aliases[link] = canonical
+	}
+	return aliases
+}
+
+// FetchGitLinkCopies returns the canonical link of every mirror and of every
+// fork confirmed by a labeler. The other forks are scored on their own.
+func FetchGitLinkCopies(ac storage.AppDatabaseContext) map[string]string {
+	copies, err := repository.NewGitLinkCopyRepository(ac).QueryScored()
+	if err != nil {
+		log.Fatalf("Failed to fetch git link copies: %v", err)
+	}
+	return copies
+}
+
+func resolveGitLink(aliases map[string]string, link string) string {
+	if canonical, ok := aliases[link]; ok {
+		return canonical
+	}
+	return link
+}
+
 func FetchLangEcoMetadata(ac storage.AppDatabaseContext) map[string]*LangEcoScore {
+	aliases := FetchGitLinkAliases(ac)
 	repo := repository.NewLangEcoLinkRepository(ac)
 	LangEcoMap := make(map[string]*LangEcoScore)
 	linksIter, err := repo.Query()
@@ -321,20 +459,22 @@ func FetchLangEcoMetadata(ac storage.AppDatabaseContext) map[string]*LangEcoScor
 		log.Fatalf("Failed to fetch lang eco links: %v", err)
 	}
 	for link := range linksIter {
+		gitLink := resolveGitLink(aliases, *link.GitLink)
 		langEcoMetadata := NewLangEcoMetadata()
 		langEcoMetadata.ParseLangEcoMetadata(link)
-		if exists, ok := LangEcoMap[*link.GitLink]; ok && exists != nil {
-			LangEcoMap[*link.GitLink].LangEcosystems = append(LangEcoMap[*link.GitLink].LangEcosystems, link)
-			LangEcoMap[*link.GitLink].LangEcoImpact += langEcoMetadata.LangEcoImpact * PackageWeight[langEcoMetadata.Type]
-			LangEcoMap[*link.GitLink].LangEcoPageRank += langEcoMetadata.LangEcoPageRank * PackageWeight[langEcoMetadata.Type]
+		if exists, ok := LangEcoMap[gitLink]; ok && exists != nil {
+			LangEcoMap[gitLink].LangEcosystems = append(LangEcoMap[gitLink].LangEcosystems, link)
+			LangEcoMap[gitLink].LangEcoImpact += langEcoMetadata.LangEcoImpact * PackageWeight[langEcoMetadata.Type]
+			LangEcoMap[gitLink].LangEcoPageRank += langEcoMetadata.LangEcoPageRank * PackageWeight[langEcoMetadata.Type]
 		} else {
-			LangEcoMap[*link.GitLink] = &LangEcoScore{LangEcosystems: []*repository.LangEcosystem{link}, LangEcoImpact: langEcoMetadata.LangEcoImpact * PackageWeight[langEcoMetadata.Type], LangEcoPageRank: langEcoMetadata.LangEcoPageRank * PackageWeight[langEcoMetadata.Type]}
+			LangEcoMap[gitLink] = &LangEcoScore{LangEcosystems: []*repository.LangEcosystem{link}, LangEcoImpact: langEcoMetadata.LangEcoImpact * PackageWeight[langEcoMetadata.Type], LangEcoPageRank: langEcoMetadata.LangEcoPageRank * PackageWeight[langEcoMetadata.Type]}
 		}
 	}
 	return LangEcoMap
 }
 
 func FetchDistMetadata(ac storage.AppDatabaseContext) map[string]*DistScore {
+	aliases := FetchGitLinkAliases(ac)
 	repo := repository.NewDistDependencyRepository(ac)
 	distMap := make(map[string]*DistScore)
 	linksIter, err := repo.Query()
@@ -342,21 +482,26 @@ func FetchDistMetadata(ac storage.AppDatabaseContext) map[string]*DistScore {
 		log.Fatalf("Failed to fetch dist links: %v", err)
 	}
 	for link := range linksIter {
+		gitLink := resolveGitLink(aliases, *link.GitLink)
 		distMetadata := NewDistMetadata()
 		distMetadata.PraseDistMetadata(link)
 		coefficient := PackageList[distMetadata.Type] / PackageList[repository.Homebrew]
-		if exists, ok := distMap[*link.GitLink]; ok && exists != nil {
-			distMap[*link.GitLink].DistDependencies = append(distMap[*link.GitLink].DistDependencies, link)
-			distMap[*link.GitLink].DistImpact += float64(coefficient) * distMetadata.DepImpact
-			distMap[*link.GitLink].downloads_3m += distMetadata.downloads_3m
-			distMap[*link.GitLink].DistPageRank += float64(coefficient) * distMetadata.PageRank
+		if exists, ok := distMap[gitLink]; ok && exists != nil {
+			distMap[gitLink].DistDependencies = append(distMap[gitLink].DistDependencies, link)
+			distMap[gitLink].DistImpact += float64(coefficient) * distMetadata.DepImpact
+			distMap[gitLink].downloads_3m += distMetadata.downloads_3m
+			distMap[gitLink].DistPageRank += float64(coefficient) * distMetadata.PageRank
 		} else {
-			distMap[*link.GitLink] = &DistScore{DistDependencies: []*repository.DistDependency{link}, DistImpact: float64(coefficient) * distMetadata.DepImpact, DistPageRank: float64(coefficient) * distMetadata.PageRank, downloads_3m: distMetadata.downloads_3m}
+			distMap[gitLink] = &DistScore{DistDependencies: []*repository.DistDependency{link}, DistImpact: float64(coefficient) * distMetadata.DepImpact, DistPageRank: float64(coefficient) * distMetadata.PageRank, downloads_3m: distMetadata.downloads_3m}
 		}
 	}
 	return distMap
 }
+
+// FetchGitLink returns the links to score, the mirrors and confirmed forks
+// are scored as their canonical link.
 func FetchGitLink(ac storage.AppDatabaseContext) []string {
+	copies := FetchGitLinkCopies(ac)
 	repo := repository.NewAllGitLinkRepository(ac)
 	linksIter, err := repo.Query()
 	if err != nil {
@@ -364,6 +509,9 @@ func FetchGitLink(ac storage.AppDatabaseContext) []string {
 	}
 	links := []string{}
 	for link := range linksIter {
+		if _, ok := copies[link]; ok {
+			continue
+		}
 		links = append(links, link)
 	}
 	return links
@@ -384,6 +532,11 @@ func UpdateScore(ac storage.AppDatabaseContext, packageScore map[string]*LinkSco
 	repo := repository.NewScoreRepository(ac)
 	scores := []*repository.Score{}
 	for link, linkScore := range packageScore {
+		var profileName, profileHash **string
+		if linkScore.Profile != nil {
+			profileName = sqlutil.ToNullable(linkScore.Profile.Name)
+			profileHash = sqlutil.ToNullable(linkScore.Profile.Hash)
+		}
 		score := repository.Score{
 			Score:            &linkScore.Score,
 			GitLink:          &link,
@@ -394,6 +547,18 @@ func UpdateScore(ac storage.AppDatabaseContext, packageScore map[string]*LinkSco
 			LangScore:        &linkScore.LangEcoScore.LangEcoScore,
 			GitScore:         &linkScore.GitMetadataScore.GitMetadataScore,
 			Round:            &linkScore.Round,
+			ProfileName:      profileName,
+			ProfileHash:      profileHash,
+		}
+		for _, c := range linkScore.Breakdown() {
+			score.Breakdown = append(score.Breakdown, &repository.ScoreBreakdown{
+				Dimension:    sqlutil.ToData(c.Dimension),
+				Metric:       sqlutil.ToData(c.Metric),
+				Value:        sqlutil.ToData(c.Value),
+				Normalized:   sqlutil.ToData(c.Normalized),
+				Weight:       sqlutil.ToData(c.Weight),
+				Contribution: sqlutil.ToData(c.Contribution),
+			})
 		}
 		scores = append(scores, &score)
 	}
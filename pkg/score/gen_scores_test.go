@@ -3,16 +3,24 @@ package score
 import (
 	"math"
 	"testing"
+	"time"
 )
 
 func TestCalculateDistScore(t *testing.T) {
+	profile, err := BuiltinProfile("log")
+	if err != nil {
+		t.Fatal(err)
+	}
 	distScore := &DistScore{
 		DistImpact:   0.5,
 		DistPageRank: 0.5,
 	}
 
-	expectedScore := (weights["distScore"]["dist_impact"] * distScore.DistImpact) + (weights["distScore"]["dist_pagerank"] * distScore.DistPageRank)
-	distScore.CalculateDistScore("log")
+	expectedScore := profile.Weight(DimensionDist, MetricDistImpact)*LogNormalize(distScore.DistImpact, profile.Threshold(DimensionDist, MetricDistImpact)) +
+		profile.Weight(DimensionDist, MetricDistPageRank)*LogNormalize(distScore.DistPageRank, profile.Threshold(DimensionDist, MetricDistPageRank))
+	if err := distScore.CalculateDistScore(profile); err != nil {
+		t.Fatal(err)
+	}
 
 	if distScore.DistScore != expectedScore {
 		t.Errorf("Expected score %v, but got %v", expectedScore, distScore.DistScore)
@@ -30,3 +38,94 @@ func TestLogNormalize(t *testing.T) {
 		t.Errorf("Expected %v, but got %v", expected, actual)
 	}
 }
+
+func TestLinkScoreBreakdown(t *testing.T) {
+	profile, err := BuiltinProfile("log")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gitMetadataScore := NewGitMetadataScore()
+	err = gitMetadataScore.CalculateGitMetadataScore(&GitMetadata{
+		ContributorCount: 100,
+		CommitFrequency:  50,
+		Org_Count:        10,
+	}, profile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	distScore := &DistScore{DistImpact: 0.5, DistPageRank: 0.1}
+	if err := distScore.CalculateDistScore(profile); err != nil {
+		t.Fatal(err)
+	}
+	langEcoScore := &LangEcoScore{LangEcoImpact: 0.2, LangEcoPageRank: 0.0001}
+	if err := langEcoScore.CalculateLangEcoScore(profile); err != nil {
+		t.Fatal(err)
+	}
+
+	linkScore := NewLinkScore(gitMetadataScore, distScore, langEcoScore, 1)
+	if err := linkScore.CalculateScore(profile); err != nil {
+		t.Fatal(err)
+	}
+
+	breakdown := linkScore.Breakdown()
+	if len(breakdown) != 12 {
+		t.Fatalf("Expected 12 contributions, but got %d", len(breakdown))
+	}
+
+	dimensionSum := map[string]float64{}
+	total := 0.0
+	for _, c := range breakdown {
+		if c.Metric == c.Dimension {
+			total += c.Contribution
+			continue
+		}
+		if c.Contribution != c.Weight*c.Normalized {
+			t.Errorf("Contribution of %s is not weight * normalized", c.Metric)
+		}
+		dimensionSum[c.Dimension] += c.Contribution
+	}
+
+	if math.Abs(total-linkScore.Score) > 1e-9 {
+		t.Errorf("Expected dimension contributions to sum to %v, but got %v", linkScore.Score, total)
+	}
+	if math.Abs(dimensionSum[DimensionDist]-distScore.DistScore) > 1e-9 {
+		t.Errorf("Expected dist contributions to sum to %v, but got %v", distScore.DistScore, dimensionSum[DimensionDist])
+	}
+	if math.Abs(dimensionSum[DimensionGitMetadata]-gitMetadataScore.GitMetadataScore) > 1e-9 {
+		t.Errorf("Expected git contributions to sum to %v, but got %v", gitMetadataScore.GitMetadataScore, dimensionSum[DimensionGitMetadata])
+	}
+}
+
+func TestPerformOperationUnknown(t *testing.T) {
+	if _, err := PerformOperation("foo", 1, 1); err == nil {
+		t.Errorf("Expected error for unknown normalization")
+	}
+	if _, err := PerformOperation(NormalizationPercentile, 1, 1); err == nil {
+		t.Errorf("Expected error for population normalization without population")
+	}
+}
+
+func TestReleaseMetricValues(t *testing.T) {
+	created := time.Now().AddDate(0, 0, -100)
+	never := &GitMetadata{CreatedSince: created, Releases: map[string]float64{MetricSemverTags: 0, MetricReleases365d: 0}}
+	values := never.MetricValues()
+	if math.Abs(values[MetricDaysSinceLastRelease]-100) > 0.01 || math.Abs(values[MetricMedianReleaseInterval]-100) > 0.01 {
+		t.Errorf("a repository never released should be scored by its age: %v", values)
+	}
+
+	released := &GitMetadata{CreatedSince: created, LastRelease: time.Now().AddDate(0, 0, -20), Releases: map[string]float64{
+		MetricSemverTags:            12,
+		MetricReleases365d:          4,
+		MetricMedianReleaseInterval: 30.5,
+	}}
+	values = released.MetricValues()
+	if math.Abs(values[MetricDaysSinceLastRelease]-20) > 0.01 || values[MetricMedianReleaseInterval] != 30.5 || values[MetricReleases365d] != 4 {
+		t.Errorf("unexpected release metrics: %v", values)
+	}
+
+	// tags never walked
+	if _, ok := (&GitMetadata{CreatedSince: created}).MetricValues()[MetricDaysSinceLastRelease]; ok {
+		t.Errorf("release metrics set without tags walked")
+	}
+}
@@ -0,0 +1,123 @@
+package score
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+const (
+	NormalizationLog     = "log"
+	NormalizationSigmoid = "sigmoid"
+	// NormalizationPercentile maps a value to its percentile rank in the
+	// population, the threshold is ignored.
+	NormalizationPercentile = "percentile"
+	// NormalizationZScore maps the z-score of a value, clipped to
+	// [-threshold, threshold] standard deviations, linearly to [0, 1].
+	NormalizationZScore = "zscore"
+	// NormalizationMinMax maps the value linearly from [min, max] of the
+	// population to [0, 1], the threshold is ignored.
+	NormalizationMinMax = "minmax"
+)
+
+// IsPopulationNormalization reports whether flag needs the statistics of
+// the whole population of scored links.
+func IsPopulationNormalization(flag string) bool {
+	switch flag {
+	case NormalizationPercentile, NormalizationZScore, NormalizationMinMax:
+		return true
+	default:
+		return false
+	}
+}
+
+// Population holds the statistics of a metric over all scored links.
+type Population struct {
+	sorted []float64
+	Mean   float64
+	StdDev float64
+	Min    float64
+	Max    float64
+}
+
+func NewPopulation(values []float64) *Population {
+	p := &Population{
+		sorted: make([]float64, len(values)),
+	}
+	copy(p.sorted, values)
+	sort.Float64s(p.sorted)
+
+	if len(p.sorted) == 0 {
+		return p
+	}
+
+	p.Min = p.sorted[0]
+	p.Max = p.sorted[len(p.sorted)-1]
+
+	sum := 0.0
+	for _, v := range p.sorted {
+		sum += v
+	}
+	p.Mean = sum / float64(len(p.sorted))
+
+	variance := 0.0
+	for _, v := range p.sorted {
+		variance += (v - p.Mean) * (v - p.Mean)
+	}
+	p.StdDev = math.Sqrt(variance / float64(len(p.sorted)))
+	return p
+}
+
+func (p *Population) Len() int {
+	return len(p.sorted)
+}
+
+// PercentileRank returns the fraction of the population below value, values
+// equal to value count as half, so the result is in [0, 1].
+func (p *Population) PercentileRank(value float64) float64 {
+	if len(p.sorted) == 0 {
+		return 0
+	}
+	below := sort.SearchFloat64s(p.sorted, value)
+	notAbove := sort.Search(len(p.sorted), func(i int) bool { return p.sorted[i] > value })
+	return (float64(below) + float64(notAbove-below)/2) / float64(len(p.sorted))
+}
+
+// ZScore returns the z-score of value clipped to [-clip, clip] and mapped
+// linearly to [0, 1].
+func (p *Population) ZScore(value, clip float64) float64 {
+	if p.StdDev == 0 || clip <= 0 {
+		return 0.5
+	}
+	z := (value - p.Mean) / p.StdDev
+	z = math.Max(-clip, math.Min(clip, z))
+	return (z + clip) / (2 * clip)
+}
+
+// MinMax maps value linearly from [Min, Max] to [0, 1].
+func (p *Population) MinMax(value float64) float64 {
+	if p.Max == p.Min {
+		return 0
+	}
+	return math.Max(0, math.Min(1, (value-p.Min)/(p.Max-p.Min)))
+}
+
+// NormalizeWithPopulation is like PerformOperation, but also supports the
+// population based normalizations. population may be nil if flag is not a
+// population based normalization.
+func NormalizeWithPopulation(flag string, value, threshold float64, population *Population) (float64, error) {
+	if !IsPopulationNormalization(flag) {
+		return PerformOperation(flag, value, threshold)
+	}
+	if population == nil {
+		return 0, fmt.Errorf("normalization %s requires population statistics", flag)
+	}
+	switch flag {
+	case NormalizationPercentile:
+		return population.PercentileRank(value), nil
+	case NormalizationZScore:
+		return population.ZScore(value, threshold), nil
+	default:
+		return population.MinMax(value), nil
+	}
+}
@@ -0,0 +1,83 @@
+package score
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPopulation(t *testing.T) {
+	p := NewPopulation([]float64{4, 1, 3, 2, 5})
+
+	if p.Min != 1 || p.Max != 5 || p.Mean != 3 {
+		t.Errorf("Unexpected statistics min=%v max=%v mean=%v", p.Min, p.Max, p.Mean)
+	}
+	if math.Abs(p.StdDev-math.Sqrt(2)) > 1e-9 {
+		t.Errorf("Expected stddev %v, but got %v", math.Sqrt(2), p.StdDev)
+	}
+
+	percentiles := map[float64]float64{0: 0, 1: 0.1, 3: 0.5, 5: 0.9, 6: 1}
+	for v, expected := range percentiles {
+		if actual := p.PercentileRank(v); math.Abs(actual-expected) > 1e-9 {
+			t.Errorf("PercentileRank(%v): expected %v, but got %v", v, expected, actual)
+		}
+	}
+
+	if actual := p.MinMax(2); actual != 0.25 {
+		t.Errorf("MinMax(2): expected 0.25, but got %v", actual)
+	}
+	if actual := p.MinMax(10); actual != 1 {
+		t.Errorf("MinMax(10): expected 1, but got %v", actual)
+	}
+
+	if actual := p.ZScore(3, 2); actual != 0.5 {
+		t.Errorf("ZScore(3): expected 0.5, but got %v", actual)
+	}
+	if actual := p.ZScore(100, 2); actual != 1 {
+		t.Errorf("ZScore(100) should be clipped to 1, but got %v", actual)
+	}
+	if actual := p.ZScore(-100, 2); actual != 0 {
+		t.Errorf("ZScore(-100) should be clipped to 0, but got %v", actual)
+	}
+}
+
+func TestCalculateScoresWithPopulation(t *testing.T) {
+	for _, normalization := range []string{NormalizationPercentile, NormalizationZScore, NormalizationMinMax} {
+		profile, err := BuiltinProfile(normalization)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		links := []string{"a", "b", "c"}
+		dist := map[string]*DistScore{
+			"a": {DistImpact: 1, DistPageRank: 1},
+			"b": {DistImpact: 2, DistPageRank: 2},
+		}
+		lang := map[string]*LangEcoScore{
+			"c": {LangEcoImpact: 1, LangEcoPageRank: 1},
+		}
+		git := map[string]*GitMetadata{
+			"a": {ContributorCount: 10},
+			"b": {ContributorCount: 20},
+			"c": {ContributorCount: 30},
+		}
+
+		result, err := CalculateScores(links, git, dist, lang, profile, 1)
+		if err != nil {
+			t.Fatalf("%s: %v", normalization, err)
+		}
+		if len(result) != len(links) {
+			t.Fatalf("%s: expected %d scores, but got %d", normalization, len(links), len(result))
+		}
+		if result["b"].DistScore.DistScore <= result["a"].DistScore.DistScore {
+			t.Errorf("%s: expected b to have a higher dist score than a", normalization)
+		}
+		for link, s := range result {
+			if math.IsNaN(s.Score) {
+				t.Errorf("%s: score of %s is NaN", normalization, link)
+			}
+		}
+		if profile.Population(DimensionDist, MetricDistImpact) != nil || profile.Population(DimensionDist, DimensionDist) != nil {
+			t.Errorf("%s: expected the profile not to be fitted", normalization)
+		}
+	}
+}
@@ -0,0 +1,412 @@
+package score
+
+import (
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ProfileFormatVersion is the version of the profile file format understood
+// by this package. Profiles declaring another version are rejected.
+const ProfileFormatVersion = 1
+
+const (
+	DimensionGitMetadata = "gitMetadataScore"
+	DimensionDist        = "distScore"
+	DimensionLangEco     = "langEcoScore"
+)
+
+const (
+	MetricCreatedSince     = "created_since"
+	MetricUpdatedSince     = "updated_since"
+	MetricContributorCount = "contributor_count"
+	MetricCommitFrequency  = "commit_frequency"
+	MetricOrgCount         = "org_count"
+	MetricDistImpact       = "dist_impact"
+	MetricDistPageRank     = "dist_pagerank"
+	MetricLangEcoImpact    = "lang_eco_impact"
+	MetricLangEcoPageRank  = "lang_eco_pagerank"
+)
+
+// ActivityMetrics are the git activity metrics of every window, named after
+// their git_metrics column, e.g. commits_30d. They are optional: a profile
+// omitting one of them does not score it.
+var ActivityMetrics = activityMetrics()
+
+func activityMetrics() []string {
+	ret := make([]string, 0)
+	for _, window := range []string{"30d", "90d", "365d", "lifetime"} {
+		for _, metric := range []string{"commits", "authors", "orgs", "maintainers"} {
+			ret = append(ret, metric+"_"+window)
+		}
+	}
+	return ret
+}
+
+const (
+	MetricSemverTags            = "semver_tags"
+	MetricReleases365d          = "releases_365d"
+	MetricDaysSinceLastRelease  = "days_since_last_release"
+	MetricMedianReleaseInterval = "median_release_interval"
+)
+
+// ReleaseMetrics are the release metrics found from the git tags, named
+// after their git_metrics column. They are optional as ActivityMetrics.
+var ReleaseMetrics = []string{
+	MetricSemverTags,
+	MetricReleases365d,
+	MetricDaysSinceLastRelease,
+	MetricMedianReleaseInterval,
+}
+
+// ProfileMetrics lists the metrics a profile may configure for each dimension.
+var ProfileMetrics = map[string][]string{
+	DimensionGitMetadata: slices.Concat([]string{
+		MetricCreatedSince,
+		MetricUpdatedSince,
+		MetricContributorCount,
+		MetricCommitFrequency,
+		MetricOrgCount,
+	}, ActivityMetrics, ReleaseMetrics),
+	DimensionDist: {
+		MetricDistImpact,
+		MetricDistPageRank,
+	},
+	DimensionLangEco: {
+		MetricLangEcoImpact,
+		MetricLangEcoPageRank,
+	},
+}
+
+//go:embed profiles/*.yaml
+var builtinProfiles embed.FS
+
+// Profile is a scoring model: the metrics, weights, thresholds and the
+// normalization used by every dimension of the score.
+type Profile struct {
+	Name       string                `yaml:"name" json:"name"`
+	Version    int                   `yaml:"version" json:"version"`
+	Dimensions map[string]*Dimension `yaml:"dimensions" json:"dimensions"`
+
+	// Hash is the sha256 of the canonical JSON encoding of the profile,
+	// it is filled by ParseProfile and not part of the file.
+	Hash string `yaml:"-" json:"-"`
+
+	// populations of metrics and dimensions using population based
+	// normalizations, see Fit
+	populations map[string]*Population
+}
+
+type Dimension struct {
+	Normalization string             `yaml:"normalization" json:"normalization"`
+	Weight        float64            `yaml:"weight" json:"weight"`
+	Threshold     float64            `yaml:"threshold" json:"threshold"`
+	Metrics       map[string]*Metric `yaml:"metrics" json:"metrics"`
+}
+
+type Metric struct {
+	Weight    float64 `yaml:"weight" json:"weight"`
+	Threshold float64 `yaml:"threshold" json:"threshold"`
+}
+
+// Weight returns the weight of metric in dimension, 0 if not configured.
+func (p *Profile) Weight(dimension, metric string) float64 {
+	if m := p.metric(dimension, metric); m != nil {
+		return m.Weight
+	}
+	return 0
+}
+
+// Threshold returns the threshold of metric in dimension, 0 if not configured.
+func (p *Profile) Threshold(dimension, metric string) float64 {
+	if m := p.metric(dimension, metric); m != nil {
+		return m.Threshold
+	}
+	return 0
+}
+
+// Normalize normalizes value with the normalization and threshold
+// configured for metric in dimension.
+func (p *Profile) Normalize(dimension, metric string, value float64) (float64, error) {
+	return NormalizeWithPopulation(p.Dimensions[dimension].Normalization, value,
+		p.Threshold(dimension, metric), p.Population(dimension, metric))
+}
+
+// Contribution returns the weighted, normalized value of metric in dimension.
+func (p *Profile) Contribution(dimension, metric string, value float64) (float64, error) {
+	normalized, err := p.Normalize(dimension, metric, value)
+	return p.Weight(dimension, metric) * normalized, err
+}
+
+// Explain is like Contribution, but returns every intermediate value.
+func (p *Profile) Explain(dimension, metric string, value float64) (*MetricContribution, error) {
+	normalized, err := p.Normalize(dimension, metric, value)
+	if err != nil {
+		return nil, fmt.Errorf("%s.%s: %w", dimension, metric, err)
+	}
+	weight := p.Weight(dimension, metric)
+	return &MetricContribution{
+		Dimension:    dimension,
+		Metric:       metric,
+		Value:        value,
+		Normalized:   normalized,
+		Weight:       weight,
+		Contribution: weight * normalized,
+	}, nil
+}
+
+// ExplainMetrics explains every metric of dimension, in the order of
+// ProfileMetrics. values holds the raw value of each metric. Optional
+// metrics missing from the profile are skipped.
+func (p *Profile) ExplainMetrics(dimension string, values map[string]float64) ([]*MetricContribution, error) {
+	ret := make([]*MetricContribution, 0, len(ProfileMetrics[dimension]))
+	for _, metric := range ProfileMetrics[dimension] {
+		if !p.HasMetric(dimension, metric) {
+			continue
+		}
+		c, err := p.Explain(dimension, metric, values[metric])
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, c)
+	}
+	return ret, nil
+}
+
+// NormalizeDimension normalizes the aggregated score of a dimension.
+func (p *Profile) NormalizeDimension(dimension string, value float64) (float64, error) {
+	d := p.Dimensions[dimension]
+	return NormalizeWithPopulation(d.Normalization, value, d.Threshold, p.Population(dimension, dimension))
+}
+
+// ExplainDimension returns how the aggregated score of a dimension
+// contributes to the final score. The metric of the returned value is the
+// dimension name itself.
+func (p *Profile) ExplainDimension(dimension string, value float64) (*MetricContribution, error) {
+	normalized, err := p.NormalizeDimension(dimension, value)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", dimension, err)
+	}
+	weight := p.Dimensions[dimension].Weight
+	return &MetricContribution{
+		Dimension:    dimension,
+		Metric:       dimension,
+		Value:        value,
+		Normalized:   normalized,
+		Weight:       weight,
+		Contribution: weight * normalized * 100,
+	}, nil
+}
+
+// NeedsPopulation reports whether dimension uses a population based
+// normalization, so the profile must be fitted before scoring, see Fit.
+func (p *Profile) NeedsPopulation(dimension string) bool {
+	d, ok := p.Dimensions[dimension]
+	return ok && IsPopulationNormalization(d.Normalization)
+}
+
+// Fit returns a copy of p with the population of metric in dimension set,
+// use the dimension name as metric for the population of the aggregated
+// dimension score. p is left unchanged, so that it can be fitted to other
+// datasets.
+func (p *Profile) Fit(dimension, metric string, population *Population) *Profile {
+	fitted := *p
+	fitted.populations = maps.Clone(p.populations)
+	if fitted.populations == nil {
+		fitted.populations = make(map[string]*Population)
+	}
+	fitted.populations[dimension+"."+metric] = population
+	return &fitted
+}
+
+// Population returns the population set by Fit, nil if not set.
+func (p *Profile) Population(dimension, metric string) *Population {
+	return p.populations[dimension+"."+metric]
+}
+
+// HasMetric reports whether metric is configured in dimension. Only
+// optional metrics may be missing from a valid profile.
+func (p *Profile) HasMetric(dimension, metric string) bool {
+	return p.metric(dimension, metric) != nil
+}
+
+// IsOptionalMetric reports whether a profile may omit metric.
+func IsOptionalMetric(metric string) bool {
+	return containsString(ActivityMetrics, metric) || containsString(ReleaseMetrics, metric)
+}
+
+func (p *Profile) metric(dimension, metric string) *Metric {
+	d, ok := p.Dimensions[dimension]
+	if !ok || d == nil {
+		return nil
+	}
+	return d.Metrics[metric]
+}
+
+// Validate checks the profile is complete and every value is usable.
+func (p *Profile) Validate() error {
+	if p.Name == "" {
+		return fmt.Errorf("profile name is empty")
+	}
+	if p.Version != ProfileFormatVersion {
+		return fmt.Errorf("profile %s: unsupported version %d, expected %d", p.Name, p.Version, ProfileFormatVersion)
+	}
+	for name := range p.Dimensions {
+		if _, ok := ProfileMetrics[name]; !ok {
+			return fmt.Errorf("profile %s: unknown dimension %s", p.Name, name)
+		}
+	}
+	for name, metrics := range ProfileMetrics {
+		d, ok := p.Dimensions[name]
+		if !ok || d == nil {
+			return fmt.Errorf("profile %s: missing dimension %s", p.Name, name)
+		}
+		if !IsSupportedNormalization(d.Normalization) {
+			return fmt.Errorf("profile %s: dimension %s: unknown normalization %q", p.Name, name, d.Normalization)
+		}
+		if err := checkWeightAndThreshold(d.Normalization, d.Weight, d.Threshold); err != nil {
+			return fmt.Errorf("profile %s: dimension %s: %w", p.Name, name, err)
+		}
+		for metric := range d.Metrics {
+			if !containsString(metrics, metric) {
+				return fmt.Errorf("profile %s: dimension %s: unknown metric %s", p.Name, name, metric)
+			}
+		}
+		for _, metric := range metrics {
+			m, ok := d.Metrics[metric]
+			if (!ok || m == nil) && IsOptionalMetric(metric) {
+				continue
+			}
+			if !ok || m == nil {
+				return fmt.Errorf("profile %s: dimension %s: missing metric %s", p.Name, name, metric)
+			}
+			if err := checkWeightAndThreshold(d.Normalization, m.Weight, m.Threshold); err != nil {
+				return fmt.Errorf("profile %s: dimension %s: metric %s: %w", p.Name, name, metric, err)
+			}
+		}
+	}
+	return nil
+}
+
+func checkWeightAndThreshold(normalization string, weight, threshold float64) error {
+	if math.IsNaN(weight) || math.IsInf(weight, 0) {
+		return fmt.Errorf("invalid weight %v", weight)
+	}
+	if math.IsNaN(threshold) || math.IsInf(threshold, 0) {
+		return fmt.Errorf("invalid threshold %v", threshold)
+	}
+	if (normalization == NormalizationLog || normalization == NormalizationZScore) && threshold <= 0 {
+		return fmt.Errorf("threshold must be positive for %s normalization, got %v", normalization, threshold)
+	}
+	return nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Clone returns a deep copy of the profile.
+func (p *Profile) Clone() *Profile {
+	ret := &Profile{
+		Name:       p.Name,
+		Version:    p.Version,
+		Hash:       p.Hash,
+		Dimensions: make(map[string]*Dimension, len(p.Dimensions)),
+	}
+	for name, d := range p.Dimensions {
+		nd := *d
+		nd.Metrics = make(map[string]*Metric, len(d.Metrics))
+		for metric, m := range d.Metrics {
+			nm := *m
+			nd.Metrics[metric] = &nm
+		}
+		ret.Dimensions[name] = &nd
+	}
+	return ret
+}
+
+// ComputeHash returns the sha256 of the canonical JSON encoding of the
+// profile, so that formatting or key order of the file does not matter.
+func (p *Profile) ComputeHash() string {
+	// encoding/json sorts map keys, which makes the encoding canonical
+	data, _ := json.Marshal(p)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// ParseProfile parses a profile in yaml or json format (json is a subset of
+// yaml) and validates it.
+func ParseProfile(data []byte) (*Profile, error) {
+	var p Profile
+	if err := yaml.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("failed to parse profile: %w", err)
+	}
+	for name, d := range p.Dimensions {
+		if d != nil {
+			d.Normalization = strings.ToLower(d.Normalization)
+		} else {
+			delete(p.Dimensions, name)
+		}
+	}
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	p.Hash = p.ComputeHash()
+	return &p, nil
+}
+
+// LoadProfile loads a profile from a file.
+func LoadProfile(path string) (*Profile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseProfile(data)
+}
+
+// BuiltinProfile returns the profile bundled with the binary for a
+// normalization, e.g. "log" or "sigmoid".
+func BuiltinProfile(normalization string) (*Profile, error) {
+	data, err := builtinProfiles.ReadFile("profiles/" + normalization + ".yaml")
+	if err != nil {
+		return nil, fmt.Errorf("no builtin profile for normalization %q, available: %s",
+			normalization, strings.Join(BuiltinProfileNames(), ", "))
+	}
+	return ParseProfile(data)
+}
+
+// BuiltinProfileNames returns the normalizations having a bundled profile.
+func BuiltinProfileNames() []string {
+	entries, _ := builtinProfiles.ReadDir("profiles")
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		names = append(names, strings.TrimSuffix(e.Name(), filepath.Ext(e.Name())))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GetProfile loads the profile from path if it is not empty, otherwise it
+// returns the builtin profile of the normalization.
+func GetProfile(path, normalization string) (*Profile, error) {
+	if path != "" {
+		return LoadProfile(path)
+	}
+	return BuiltinProfile(normalization)
+}
@@ -0,0 +1,140 @@
+package score
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuiltinProfiles(t *testing.T) {
+	for _, name := range BuiltinProfileNames() {
+		profile, err := BuiltinProfile(name)
+		if err != nil {
+			t.Fatalf("builtin profile %s is invalid: %v", name, err)
+		}
+		if profile.Hash == "" {
+			t.Errorf("builtin profile %s has no hash", name)
+		}
+	}
+	if _, err := BuiltinProfile("unknown"); err == nil {
+		t.Errorf("expected error for unknown builtin profile")
+	}
+}
+
+func TestParseProfileValidation(t *testing.T) {
+	base, err := BuiltinProfile("log")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(p *Profile)
+		errMsg string
+	}{
+		{"empty name", func(p *Profile) { p.Name = "" }, "name is empty"},
+		{"bad version", func(p *Profile) { p.Version = 2 }, "unsupported version"},
+		{"missing dimension", func(p *Profile) { delete(p.Dimensions, DimensionDist) }, "missing dimension"},
+		{"unknown metric", func(p *Profile) {
+			p.Dimensions[DimensionDist].Metrics["foo"] = &Metric{Weight: 1, Threshold: 1}
+		}, "unknown metric"},
+		{"missing metric", func(p *Profile) {
+			delete(p.Dimensions[DimensionGitMetadata].Metrics, MetricOrgCount)
+		}, "missing metric"},
+		{"bad normalization", func(p *Profile) { p.Dimensions[DimensionLangEco].Normalization = "foo" }, "unknown normalization"},
+		{"non positive log threshold", func(p *Profile) {
+			p.Dimensions[DimensionDist].Metrics[MetricDistImpact].Threshold = 0
+		}, "threshold must be positive"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := base.Clone()
+			tt.modify(p)
+			err := p.Validate()
+			if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("expected error containing %q, got %v", tt.errMsg, err)
+			}
+		})
+	}
+}
+
+func TestProfileHashIgnoresFormatting(t *testing.T) {
+	yamlProfile := `
+name: test
+version: 1
+dimensions:
+  gitMetadataScore:
+    normalization: log
+    weight: 0.2
+    threshold: 5
+    metrics:
+      created_since: {weight: 1, threshold: 120}
+      updated_since: {weight: -1, threshold: 120}
+      contributor_count: {weight: 2, threshold: 40000}
+      commit_frequency: {weight: 1, threshold: 1000}
+      org_count: {weight: 1, threshold: 8400}
+  distScore:
+    normalization: log
+    weight: 0.5
+    threshold: 1.5
+    metrics:
+      dist_impact: {weight: 1, threshold: 22}
+      dist_pagerank: {weight: 1, threshold: 3}
+  langEcoScore:
+    normalization: LOG
+    weight: 0.3
+    threshold: 1.3
+    metrics:
+      lang_eco_pagerank: {weight: 1, threshold: 0.0002}
+      lang_eco_impact: {weight: 1, threshold: 1}
+`
+	p1, err := ParseProfile([]byte(yamlProfile))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jsonProfile, err := BuiltinProfile("log")
+	if err != nil {
+		t.Fatal(err)
+	}
+	jsonProfile.Name = "test"
+	if p1.Hash != jsonProfile.ComputeHash() {
+		t.Errorf("expected equal hash for equal profiles, got %s and %s", p1.Hash, jsonProfile.ComputeHash())
+	}
+
+	p1.Dimensions[DimensionDist].Weight = 0.6
+	if p1.Hash == p1.ComputeHash() {
+		t.Errorf("expected hash to change when the profile changes")
+	}
+}
+
+func TestOptionalActivityMetrics(t *testing.T) {
+	p, err := BuiltinProfile("log")
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := &GitMetadata{ContributorCount: 10, Activity: map[string]int{"commits_90d": 40}}
+
+	contributions, err := p.ExplainMetrics(DimensionGitMetadata, g.MetricValues())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range contributions {
+		if IsOptionalMetric(c.Metric) {
+			t.Errorf("unconfigured metric %s is scored", c.Metric)
+		}
+	}
+
+	p.Dimensions[DimensionGitMetadata].Metrics["commits_90d"] = &Metric{Weight: 1, Threshold: 100}
+	if err := p.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	contributions, err = p.ExplainMetrics(DimensionGitMetadata, g.MetricValues())
+	if err != nil {
+		t.Fatal(err)
+	}
+	last := contributions[len(contributions)-1]
+	if last.Metric != "commits_90d" || last.Value != 40 || last.Contribution <= 0 {
+		t.Errorf("unexpected contribution of commits_90d: %+v", last)
+	}
+}
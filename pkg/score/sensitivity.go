@@ -0,0 +1,268 @@
+package score
+
+import (
+	"fmt"
+	"sort"
+)
+
+const (
+	ParameterWeight    = "weight"
+	ParameterThreshold = "threshold"
+)
+
+// Perturbation scales one weight or threshold of a profile by 1 + Delta.
+// For a dimension level parameter, Metric equals Dimension.
+type Perturbation struct {
+	Dimension string
+	Metric    string
+	Parameter string
+	Delta     float64
+}
+
+func (p Perturbation) String() string {
+	name := p.Dimension
+	if p.Metric != p.Dimension {
+		name += "." + p.Metric
+	}
+	return fmt.Sprintf("%s.%s %+g%%", name, p.Parameter, p.Delta*100)
+}
+
+// Apply returns a copy of profile with the perturbation applied.
+func (p Perturbation) Apply(profile *Profile) *Profile {
+	ret := profile.Clone()
+	d := ret.Dimensions[p.Dimension]
+	var weight, threshold *float64
+	if p.Metric == p.Dimension {
+		weight, threshold = &d.Weight, &d.Threshold
+	} else {
+		m := d.Metrics[p.Metric]
+		weight, threshold = &m.Weight, &m.Threshold
+	}
+	switch p.Parameter {
+	case ParameterWeight:
+		*weight *= 1 + p.Delta
+	case ParameterThreshold:
+		*threshold *= 1 + p.Delta
+	}
+	return ret
+}
+
+// Perturbations returns every perturbation of the weights and thresholds of
+// profile by +delta and -delta. Thresholds ignored by the normalization of
+// their dimension are skipped.
+func Perturbations(profile *Profile, delta float64) []Perturbation {
+	ret := make([]Perturbation, 0)
+	dimensions := make([]string, 0, len(ProfileMetrics))
+	for dimension := range ProfileMetrics {
+		dimensions = append(dimensions, dimension)
+	}
+	sort.Strings(dimensions)
+
+	for _, dimension := range dimensions {
+		parameters := []string{ParameterWeight}
+		switch profile.Dimensions[dimension].Normalization {
+		case NormalizationPercentile, NormalizationMinMax:
+		default:
+			parameters = append(parameters, ParameterThreshold)
+		}
+		metrics := append([]string{dimension}, ProfileMetrics[dimension]...)
+		for _, metric := range metrics {
+			if metric != dimension && !profile.HasMetric(dimension, metric) {
+				continue
+			}
+			for _, parameter := range parameters {
+				for _, d := range []float64{delta, -delta} {
+					ret = append(ret, Perturbation{
+						Dimension: dimension,
+						Metric:    metric,
+						Parameter: parameter,
+						Delta:     d,
+					})
+				}
+			}
+		}
+	}
+	return ret
+}
+
+// Ranking returns the links ordered by descending score, ties are broken
+// by link so the ranking is deterministic.
+func Ranking(scores map[string]*LinkScore) []string {
+	ret := make([]string, 0, len(scores))
+	for link := range scores {
+		ret = append(ret, link)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		si, sj := scores[ret[i]].Score, scores[ret[j]].Score
+		if si != sj {
+			return si > sj
+		}
+		return ret[i] < ret[j]
+	})
+	return ret
+}
+
+// KendallTau returns the Kendall rank correlation between two rankings of
+// the same links, in O(n log n) by counting the discordant pairs with a
+// merge sort. Links missing from b are ignored.
+func KendallTau(a, b []string) float64 {
+	pos := make(map[string]int, len(b))
+	for i, link := range b {
+		pos[link] = i
+	}
+	seq := make([]int, 0, len(a))
+	for _, link := range a {
+		if p, ok := pos[link]; ok {
+			seq = append(seq, p)
+		}
+	}
+	n := len(seq)
+	if n < 2 {
+		return 1
+	}
+	discordant := countInversions(seq, make([]int, n))
+	pairs := float64(n) * float64(n-1) / 2
+	return 1 - 2*float64(discordant)/pairs
+}
+
+func countInversions(seq, buf []int) int64 {
+	if len(seq) < 2 {
+		return 0
+	}
+	mid := len(seq) / 2
+	cnt := countInversions(seq[:mid], buf[:mid]) + countInversions(seq[mid:], buf[mid:])
+	i, j, k := 0, mid, 0
+	for i < mid && j < len(seq) {
+		if seq[i] <= seq[j] {
+			buf[k] = seq[i]
+			i++
+		} else {
+			buf[k] = seq[j]
+			cnt += int64(mid - i)
+			j++
+		}
+		k++
+	}
+	k += copy(buf[k:], seq[i:mid])
+	copy(buf[k:], seq[j:])
+	copy(seq, buf[:len(seq)])
+	return cnt
+}
+
+// TopKOverlap returns the fraction of the first k links of a which are also
+// in the first k links of b.
+func TopKOverlap(a, b []string, k int) float64 {
+	k = min(k, len(a), len(b))
+	if k == 0 {
+		return 1
+	}
+	top := make(map[string]bool, k)
+	for _, link := range b[:k] {
+		top[link] = true
+	}
+	cnt := 0
+	for _, link := range a[:k] {
+		if top[link] {
+			cnt++
+		}
+	}
+	return float64(cnt) / float64(k)
+}
+
+// RankMove is the rank of a link before and after a perturbation, ranks
+// start from 1.
+type RankMove struct {
+	Link     string
+	BaseRank int
+	Rank     int
+}
+
+func (m RankMove) Distance() int {
+	if m.Rank > m.BaseRank {
+		return m.Rank - m.BaseRank
+	}
+	return m.BaseRank - m.Rank
+}
+
+// LargestRankMoves returns the n links moving the most between the two
+// rankings, among the links in the top k of either ranking.
+func LargestRankMoves(base, other []string, k, n int) []RankMove {
+	basePos := make(map[string]int, len(base))
+	for i, link := range base {
+		basePos[link] = i + 1
+	}
+	otherPos := make(map[string]int, len(other))
+	for i, link := range other {
+		otherPos[link] = i + 1
+	}
+
+	candidates := make(map[string]bool)
+	for _, link := range base[:min(k, len(base))] {
+		candidates[link] = true
+	}
+	for _, link := range other[:min(k, len(other))] {
+		candidates[link] = true
+	}
+
+	moves := make([]RankMove, 0, len(candidates))
+	for link := range candidates {
+		moves = append(moves, RankMove{Link: link, BaseRank: basePos[link], Rank: otherPos[link]})
+	}
+	sort.Slice(moves, func(i, j int) bool {
+		if moves[i].Distance() != moves[j].Distance() {
+			return moves[i].Distance() > moves[j].Distance()
+		}
+		return moves[i].BaseRank < moves[j].BaseRank
+	})
+	return moves[:min(n, len(moves))]
+}
+
+// SensitivityResult is the effect of one perturbation on the ranking.
+type SensitivityResult struct {
+	Perturbation Perturbation
+	KendallTau   float64
+	TopKOverlap  float64
+	Moves        []RankMove
+}
+
+// SensitivityOptions configures AnalyzeSensitivity.
+type SensitivityOptions struct {
+	// Delta is the relative perturbation, e.g. 0.1 for ±10%
+	Delta float64
+	// TopK is the size of the top of the ranking being compared
+	TopK int
+	// Moves is the number of largest rank moves reported per perturbation
+	Moves int
+}
+
+// AnalyzeSensitivity perturbs every weight and threshold of profile and
+// compares the resulting rankings with the baseline ranking. Everything is
+// computed in memory, nothing is written to the database.
+func AnalyzeSensitivity(links []string,
+	gitMetrics map[string]*GitMetadata,
+	distMetrics map[string]*DistScore,
+	langEcoMetrics map[string]*LangEcoScore,
+	profile *Profile, opts SensitivityOptions) ([]*SensitivityResult, error) {
+
+	baseScores, err := CalculateScores(links, gitMetrics, distMetrics, langEcoMetrics, profile.Clone(), 0)
+	if err != nil {
+		return nil, err
+	}
+	base := Ranking(baseScores)
+
+	results := make([]*SensitivityResult, 0)
+	for _, p := range Perturbations(profile, opts.Delta) {
+		scores, err := CalculateScores(links, gitMetrics, distMetrics, langEcoMetrics, p.Apply(profile), 0)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", p, err)
+		}
+		ranking := Ranking(scores)
+		results = append(results, &SensitivityResult{
+			Perturbation: p,
+			KendallTau:   KendallTau(base, ranking),
+			TopKOverlap:  TopKOverlap(base, ranking, opts.TopK),
+			Moves:        LargestRankMoves(base, ranking, opts.TopK, opts.Moves),
+		})
+	}
+	return results, nil
+}
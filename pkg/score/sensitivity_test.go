@@ -0,0 +1,101 @@
+package score
+
+import (
+	"math"
+	"testing"
+)
+
+func TestKendallTau(t *testing.T) {
+	a := []string{"a", "b", "c", "d"}
+
+	if tau := KendallTau(a, a); tau != 1 {
+		t.Errorf("Expected tau 1 for identical rankings, but got %v", tau)
+	}
+	if tau := KendallTau(a, []string{"d", "c", "b", "a"}); tau != -1 {
+		t.Errorf("Expected tau -1 for reversed rankings, but got %v", tau)
+	}
+	// one discordant pair among six
+	if tau := KendallTau(a, []string{"b", "a", "c", "d"}); math.Abs(tau-2.0/3) > 1e-9 {
+		t.Errorf("Expected tau 2/3, but got %v", tau)
+	}
+}
+
+func TestTopKOverlap(t *testing.T) {
+	a := []string{"a", "b", "c", "d"}
+	b := []string{"a", "c", "d", "b"}
+
+	if o := TopKOverlap(a, b, 2); o != 0.5 {
+		t.Errorf("Expected overlap 0.5, but got %v", o)
+	}
+	if o := TopKOverlap(a, b, 10); o != 1 {
+		t.Errorf("Expected overlap 1, but got %v", o)
+	}
+}
+
+func TestLargestRankMoves(t *testing.T) {
+	base := []string{"a", "b", "c", "d", "e"}
+	other := []string{"e", "a", "b", "c", "d"}
+
+	moves := LargestRankMoves(base, other, 2, 1)
+	if len(moves) != 1 || moves[0].Link != "e" || moves[0].BaseRank != 5 || moves[0].Rank != 1 {
+		t.Errorf("Unexpected moves %+v", moves)
+	}
+}
+
+func TestPerturbationApply(t *testing.T) {
+	profile, err := BuiltinProfile("log")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := Perturbation{Dimension: DimensionDist, Metric: MetricDistImpact, Parameter: ParameterThreshold, Delta: 0.1}
+	perturbed := p.Apply(profile)
+	expected := profile.Threshold(DimensionDist, MetricDistImpact) * 1.1
+	if perturbed.Threshold(DimensionDist, MetricDistImpact) != expected {
+		t.Errorf("Expected threshold %v, but got %v", expected, perturbed.Threshold(DimensionDist, MetricDistImpact))
+	}
+	if profile.Threshold(DimensionDist, MetricDistImpact) == expected {
+		t.Errorf("Apply should not modify the original profile")
+	}
+
+	p = Perturbation{Dimension: DimensionDist, Metric: DimensionDist, Parameter: ParameterWeight, Delta: -0.1}
+	perturbed = p.Apply(profile)
+	if perturbed.Dimensions[DimensionDist].Weight != profile.Dimensions[DimensionDist].Weight*0.9 {
+		t.Errorf("Dimension weight was not perturbed")
+	}
+
+	// 3 dimensions with 9 metrics, weight and threshold, ±delta
+	if n := len(Perturbations(profile, 0.1)); n != (3+9)*2*2 {
+		t.Errorf("Expected %d perturbations, but got %d", (3+9)*2*2, n)
+	}
+}
+
+func TestAnalyzeSensitivity(t *testing.T) {
+	profile, err := BuiltinProfile("log")
+	if err != nil {
+		t.Fatal(err)
+	}
+	links := []string{"a", "b", "c"}
+	dist := map[string]*DistScore{
+		"a": {DistImpact: 1, DistPageRank: 0.1},
+		"b": {DistImpact: 0.1, DistPageRank: 1},
+	}
+	git := map[string]*GitMetadata{
+		"c": {ContributorCount: 1000, Org_Count: 100},
+	}
+
+	results, err := AnalyzeSensitivity(links, git, dist, map[string]*LangEcoScore{}, profile, SensitivityOptions{
+		Delta: 0.1, TopK: 2, Moves: 2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != len(Perturbations(profile, 0.1)) {
+		t.Errorf("Expected a result for every perturbation")
+	}
+	for _, r := range results {
+		if r.KendallTau < -1 || r.KendallTau > 1 {
+			t.Errorf("%s: tau out of range: %v", r.Perturbation, r.KendallTau)
+		}
+	}
+}
@@ -8,7 +8,12 @@ import (
 
 type AllGitLinkRepository interface {
 	/** QUERY **/
+	// Query returns the links of every source resolved through their
+	// aliases, see GitLinkAliasRepository
 	Query() (iter.Seq[string], error)
+	// QuerySource returns the links of every source as they are spelled
+	// there
+	QuerySource() (iter.Seq[string], error)
 	QueryByLink(search string) (iter.Seq[string], error)
 	QueryCache() (iter.Seq[string], error)
 	MakeCache() error
@@ -72,3 +77,8 @@ func gitlinksQuery(ctx storage.AppDatabaseContext, query string, args ...interfa
 func (a *allGitLinkRepository) Query() (iter.Seq[string], error) {
 	return gitlinksQuery(a.ctx, "SELECT git_link FROM all_gitlinks")
 }
+
+// QuerySource implements AllGitLinkRepository.
+func (a *allGitLinkRepository) QuerySource() (iter.Seq[string], error) {
+	return gitlinksQuery(a.ctx, "SELECT git_link FROM source_gitlinks")
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"fmt"
 	"iter"
+	"slices"
 	"strings"
 	"time"
 
@@ -19,12 +20,27 @@ type DistDependencyRepository interface {
 	Query() (iter.Seq[*DistDependency], error) // Query all distribution information.
 	QueryByType(distType int) (iter.Seq[*DistDependency], error)
 	GetByLink(packageName string, distType int) (*DistDependency, error)
-	QueryDistCountByType(distType DistType) (int, error) // Get the total number of packages in a Distro.
+	GetByLinkAndTarget(packageName string, distType int, release, arch string) (*DistDependency, error)
+	QueryDistCountByType(distType DistType) (int, error) // Get the total number of source packages in a Distro.
+	// Get the number of source packages of a release and architecture of a Distro.
+	QueryDistCountByTarget(distType DistType, release, arch string) (int, error)
 
 	/** INSERT/UPDATE **/
 	// update_time will be updated automatically
 	InsertOrUpdate(packageInfo *DistDependency) error
-	InsertRelationships(distType DistType, relationships map[string][]string) error
+	// InsertRelationships stores the dependencies between the packages of a
+	// Distro, which must be stored already.
+	InsertRelationships(distType DistType, relationships []*DistRelationship) error
+}
+
+// DistRelationship is a dependency of the package From on the package To,
+// in the target of From.
+type DistRelationship struct {
+	From      string
+	To        string
+	Release   string
+	Component string
+	Arch      string
 }
 
 type distLinkRepository struct {
@@ -61,61 +77,112 @@ type DistDependency struct {
 	PageRank     *float64
 	UpdateTime   *time.Time
 	Downloads_3m *int
+	Release      *string
+	// Component is the comma separated components of the packages of the
+	// git link
+	Component *string
+	Arch      *string
 }
 
 func NewDistDependencyRepository(appDb storage.AppDatabaseContext) DistDependencyRepository {
 	return &distLinkRepository{ctx: appDb}
 }
 
+// distDependencyAggregate selects a row per git link and Distro, aggregated
+// across the targets the git link is packaged for. The rows are appended by
+// every collection, so only the latest row of each release and architecture
+// is aggregated: the metrics are the maximum over the targets, so a package
+// collected for several releases or architectures is not counted several
+// times, the id is the one of the latest row, and the targets are the comma
+// separated lists of the releases, components and architectures.
+const distDependencyAggregate = `SELECT max(d.id) AS id, d.git_link, d.type,
+	max(d.dep_impact) AS dep_impact, max(d.dep_count) AS dep_count, max(d.page_rank) AS page_rank,
+	max(d.update_time) AS update_time, max(d.downloads_3m) AS downloads_3m,
+	string_agg(DISTINCT d.release, ',' ORDER BY d.release) AS release,
+	coalesce(string_agg(DISTINCT c, ',' ORDER BY c), '') AS component,
+	string_agg(DISTINCT d.arch, ',' ORDER BY d.arch) AS arch
+FROM (
+	SELECT DISTINCT ON (git_link, type, release, arch) * FROM ` + DistDependencyTableName + `
+	ORDER BY git_link, type, release, arch, id DESC
+) d
+LEFT JOIN LATERAL unnest(string_to_array(d.component, ',')) AS c ON c <> '' `
+
 // Query implements DistributionDependencyRepository.
 func (r *distLinkRepository) Query() (iter.Seq[*DistDependency], error) {
-	return sqlutil.Query[DistDependency](r.ctx, `SELECT DISTINCT ON (git_link, "type") id, git_link, type, dep_impact, dep_count, page_rank, update_time, downloads_3m FROM distribution_dependencies ORDER BY git_link, "type", id DESC`)
+	return sqlutil.Query[DistDependency](r.ctx, distDependencyAggregate+`GROUP BY d.git_link, d.type`)
 }
 
-// QueryDistCountByType implements DistributionDependencyRepository.
-func (r *distLinkRepository) QueryDistCountByType(distType DistType) (int, error) {
-	var tableName string
+func distPackageTableName(distType DistType) (string, error) {
 	switch distType {
 	case Debian:
-		tableName = "debian_packages"
+		return "debian_packages", nil
 	case Arch:
-		tableName = "arch_packages"
+		return "arch_packages", nil
 	case Homebrew:
-		tableName = "homebrew_packages"
+		return "homebrew_packages", nil
 	case Nix:
-		tableName = "nix_packages"
+		return "nix_packages", nil
 	case Alpine:
-		tableName = "alpine_packages"
+		return "alpine_packages", nil
 	case Centos:
-		tableName = "centos_packages"
+		return "centos_packages", nil
 	case Aur:
-		tableName = "aur_packages"
+		return "aur_packages", nil
 	case Deepin:
-		tableName = "deepin_packages"
+		return "deepin_packages", nil
 	case Fedora:
-		tableName = "fedora_packages"
+		return "fedora_packages", nil
 	case Gentoo:
-		tableName = "gentoo_packages"
+		return "gentoo_packages", nil
 	case Ubuntu:
-		tableName = "ubuntu_packages"
+		return "ubuntu_packages", nil
 	case OpenEuler:
-		tableName = "openeuler_packages"
+		return "openeuler_packages", nil
 	case OpenKylin:
-		tableName = "openkylin_packages"
+		return "openkylin_packages", nil
 	default:
-		return 0, ErrInvalidInput
+		return "", ErrInvalidInput
+	}
+}
+
+// QueryDistCountByType implements DistributionDependencyRepository.
+// Source packages are counted, once if collected for several targets.
+func (r *distLinkRepository) QueryDistCountByType(distType DistType) (int, error) {
+	tableName, err := distPackageTableName(distType)
+	if err != nil {
+		return 0, err
+	}
+
+	var result int
+	row := r.ctx.QueryRow(`SELECT COUNT(DISTINCT source) FROM ` + tableName)
+	err = row.Scan(&result)
+	return result, err
+}
+
+// QueryDistCountByTarget implements DistributionDependencyRepository.
+func (r *distLinkRepository) QueryDistCountByTarget(distType DistType, release, arch string) (int, error) {
+	tableName, err := distPackageTableName(distType)
+	if err != nil {
+		return 0, err
 	}
 
 	var result int
-	row := r.ctx.QueryRow(`SELECT COUNT(*) FROM ` + tableName)
-	err := row.Scan(&result)
+	row := r.ctx.QueryRow(`SELECT COUNT(DISTINCT source) FROM `+tableName+` WHERE release = $1 AND arch = $2`, release, arch)
+	err = row.Scan(&result)
 	return result, err
 }
 
 // GetByLink implements DistributionDependencyRepository.
+// The targets of the git link are aggregated as in Query.
 func (r *distLinkRepository) GetByLink(packageName string, distType int) (*DistDependency, error) {
+	return sqlutil.QueryFirst[DistDependency](r.ctx, distDependencyAggregate+`WHERE d.git_link = $1 and d.type = $2 GROUP BY d.git_link, d.type`,
+		packageName, distType)
+}
+
+// GetByLinkAndTarget implements DistributionDependencyRepository.
+func (r *distLinkRepository) GetByLinkAndTarget(packageName string, distType int, release, arch string) (*DistDependency, error) {
 	return sqlutil.QueryCommonFirst[DistDependency](r.ctx, DistDependencyTableName,
-		`WHERE git_link = $1 and type = $2 ORDER BY id DESC`, packageName, distType)
+		`WHERE git_link = $1 and type = $2 and release = $3 and arch = $4 ORDER BY id DESC`, packageName, distType, release, arch)
 }
 
 // InsertOrUpdate implements DistributionDependencyRepository.
@@ -126,7 +193,14 @@ func (r *distLinkRepository) InsertOrUpdate(packageInfo *DistDependency) error {
 
 	packageInfo.UpdateTime = lo.ToPtr(time.Now())
 
-	oldInfo, err := r.GetByLink(*packageInfo.GitLink, int(*packageInfo.Type))
+	if packageInfo.Release == nil {
+		packageInfo.Release = lo.ToPtr("")
+	}
+	if packageInfo.Arch == nil {
+		packageInfo.Arch = lo.ToPtr("")
+	}
+
+	oldInfo, err := r.GetByLinkAndTarget(*packageInfo.GitLink, int(*packageInfo.Type), *packageInfo.Release, *packageInfo.Arch)
 	if err != nil {
 		return err
 	}
@@ -145,72 +219,34 @@ func (r *distLinkRepository) QueryByType(distType int) (iter.Seq[*DistDependency
 		"where type = $1", distType)
 }
 
-func (r *distLinkRepository) InsertRelationships(distType DistType, relationships map[string][]string) error {
-	var tableName string
-	switch distType {
-	case Debian:
-		tableName = "debian_relationships"
-	case Arch:
-		tableName = "arch_relationships"
-	case Homebrew:
-		tableName = "homebrew_relationships"
-	case Nix:
-		tableName = "nix_relationships"
-	case Alpine:
-		tableName = "alpine_relationships"
-	case Centos:
-		tableName = "centos_relationships"
-	case Aur:
-		tableName = "aur_relationships"
-	case Deepin:
-		tableName = "deepin_relationships"
-	case Fedora:
-		tableName = "fedora_relationships"
-	case Gentoo:
-		tableName = "gentoo_relationships"
-	case Ubuntu:
-		tableName = "ubuntu_relationships"
-	case OpenEuler:
-		tableName = "openeuler_relationships"
-	case OpenKylin:
-		tableName = "openkylin_relationships"
-	default:
-		return ErrInvalidInput
+func distRelationshipTableName(distType DistType) (string, error) {
+	tableName, err := distPackageTableName(distType)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(tableName, "_packages") + "_relationships", nil
+}
+
+// InsertRelationships implements DistributionDependencyRepository.
+func (r *distLinkRepository) InsertRelationships(distType DistType, relationships []*DistRelationship) error {
+	tableName, err := distRelationshipTableName(distType)
+	if err != nil {
+		return err
 	}
 	batchSize := 1000
-	valueStrings := make([]string, 0, batchSize)
-	valueArgs := make([]interface{}, 0, batchSize*2)
-	i := 0
-	for fromPackage, toPackages := range relationships {
-		for _, toPackage := range toPackages {
-			valueStrings = append(valueStrings, fmt.Sprintf("($%d, $%d)", i*2+1, i*2+2))
-			valueArgs = append(valueArgs, fromPackage, toPackage)
-			i++
-			if i >= batchSize {
-				stmt := fmt.Sprintf(`
-INSERT INTO %s (frompackage, topackage) VALUES %s
-ON CONFLICT (frompackage, topackage) DO UPDATE
-SET frompackage = EXCLUDED.frompackage;
-`, tableName, strings.Join(valueStrings, ","))
-				_, err := r.ctx.Exec(stmt, valueArgs...)
-				if err != nil {
-					return fmt.Errorf("failed to insert/update batch: %w", err)
-				}
-				valueStrings = make([]string, 0, batchSize)
-				valueArgs = make([]interface{}, 0, batchSize*2)
-				i = 0
-			}
+	for batch := range slices.Chunk(relationships, batchSize) {
+		valueStrings := make([]string, 0, len(batch))
+		valueArgs := make([]interface{}, 0, len(batch)*5)
+		for i, rel := range batch {
+			valueStrings = append(valueStrings, fmt.Sprintf("($%d, $%d, $%d, $%d, $%d)", i*5+1, i*5+2, i*5+3, i*5+4, i*5+5))
+			valueArgs = append(valueArgs, rel.From, rel.To, rel.Release, rel.Component, rel.Arch)
 		}
-	}
-	if len(valueStrings) > 0 {
 		stmt := fmt.Sprintf(`
-INSERT INTO %s (frompackage, topackage) VALUES %s
-ON CONFLICT (frompackage, topackage) DO UPDATE
-SET frompackage = EXCLUDED.frompackage;
+INSERT INTO %s (frompackage, topackage, release, component, arch) VALUES %s
+ON CONFLICT (frompackage, topackage, release, component, arch) DO NOTHING;
 `, tableName, strings.Join(valueStrings, ","))
-		_, err := r.ctx.Exec(stmt, valueArgs...)
-		if err != nil {
-			return fmt.Errorf("failed to insert/update remaining batch: %w", err)
+		if _, err := r.ctx.Exec(stmt, valueArgs...); err != nil {
+			return fmt.Errorf("failed to insert/update batch: %w", err)
 		}
 	}
 	return nil
@@ -19,6 +19,8 @@ type DistPackageRepository interface {
 
 	/** INSERT/UPDATE **/
 
+	// NOTE: Release, Component and Arch must be set, they are part of the
+	// primary key
 	InsertOrUpdate(packageInfo *DistPackage) error
 	// NOTE: git_link will be ignored
 	Insert(packageInfo *DistPackage) error
@@ -62,6 +64,11 @@ type DistPackage struct {
 	GitLink        *string
 	DependsCount   *int
 	LinkConfidence **float32
+	Release        *string `pk:"true"`
+	Component      *string `pk:"true"`
+	Arch           *string `pk:"true"`
+	// Source is the source package the package is built from
+	Source *string
 }
 
 type distPackageRepository struct {
@@ -87,8 +94,10 @@ func (d *distPackageRepository) BatchInsert(packageInfos []*DistPackage) error {
 }
 
 // Delete implements DistPackageRepository.
+// The package is deleted from every target.
 func (d *distPackageRepository) Delete(name string) error {
-	return sqlutil.Delete(d.ctx, string(d.prefix)+DistPackageTableNameAppendix, &DistPackage{Package: &name})
+	_, err := d.ctx.Exec("DELETE FROM "+string(d.prefix)+DistPackageTableNameAppendix+" WHERE package = $1", name)
+	return err
 }
 
 // DeleteAll implements DistPackageRepository.
@@ -104,7 +113,8 @@ func (d *distPackageRepository) GetByGitLink(gitLink string) (iter.Seq[*DistPack
 
 // GetByName implements DistPackageRepository.
 func (d *distPackageRepository) GetByName(name string) (*DistPackage, error) {
-	return sqlutil.QueryCommonFirst[DistPackage](d.ctx, string(d.prefix)+DistPackageTableNameAppendix, "WHERE package = $1", name)
+	// prefer a target already linked to a git repository
+	return sqlutil.QueryCommonFirst[DistPackage](d.ctx, string(d.prefix)+DistPackageTableNameAppendix, "WHERE package = $1 ORDER BY git_link IS NULL", name)
 }
 
 // Insert implements DistPackageRepository.
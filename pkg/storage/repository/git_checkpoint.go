@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/storage"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/sqlutil"
+)
+
+type GitCheckpointRepository interface {
+	/** QUERY **/
+	// returns nil if there is no checkpoint of link
+	QueryByLink(link string) (*GitCheckpoint, error)
+
+	/** INSERT/UPDATE **/
+	// NOTE: update_time will be updated automatically
+	InsertOrUpdate(data *GitCheckpoint) error
+	Delete(link string) error
+}
+
+type GitCheckpoint struct {
+	GitLink *string `pk:"true"`
+	// Data is an encoded git.Checkpoint
+	Data       *[]byte
+	UpdateTime **time.Time
+}
+
+const GitCheckpointTableName = "git_checkpoints"
+
+type gitCheckpointRepository struct {
+	ctx storage.AppDatabaseContext
+}
+
+var _ GitCheckpointRepository = (*gitCheckpointRepository)(nil)
+
+func NewGitCheckpointRepository(ctx storage.AppDatabaseContext) GitCheckpointRepository {
+	return &gitCheckpointRepository{ctx: ctx}
+}
+
+// QueryByLink implements GitCheckpointRepository.
+func (g *gitCheckpointRepository) QueryByLink(link string) (*GitCheckpoint, error) {
+	return sqlutil.QueryCommonFirst[GitCheckpoint](g.ctx, GitCheckpointTableName, "WHERE git_link = $1", link)
+}
+
+// InsertOrUpdate implements GitCheckpointRepository.
+func (g *gitCheckpointRepository) InsertOrUpdate(data *GitCheckpoint) error {
+	if data.GitLink == nil || data.Data == nil {
+		return ErrInvalidInput
+	}
+	data.UpdateTime = sqlutil.ToNullable(time.Now())
+	return sqlutil.Upsert(g.ctx, GitCheckpointTableName, data)
+}
+
+// Delete implements GitCheckpointRepository.
+func (g *gitCheckpointRepository) Delete(link string) error {
+	return sqlutil.Delete(g.ctx, GitCheckpointTableName, &GitCheckpoint{GitLink: &link})
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"iter"
+	"time"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/storage"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/sqlutil"
+	"github.com/lib/pq"
+)
+
+type GitHistoryRepository interface {
+	/** QUERY **/
+	// QuerySharingRoots returns the histories of the other links with one of
+	// the roots.
+	QuerySharingRoots(link string, roots []string) (iter.Seq[*GitHistory], error)
+
+	/** INSERT/UPDATE **/
+	// NOTE: update_time will be updated automatically
+	InsertOrUpdate(data *GitHistory) error
+}
+
+// GitHistory is a stored git.History.
+type GitHistory struct {
+	GitLink *string `pk:"true"`
+	Roots   *pq.StringArray
+	Sketch  *pq.StringArray
+	Commits *int
+	// CreatedSince is the committer time of the oldest commit
+	CreatedSince **time.Time
+	UpdateTime   **time.Time
+}
+
+const GitHistoryTableName = "git_histories"
+
+type gitHistoryRepository struct {
+	ctx storage.AppDatabaseContext
+}
+
+var _ GitHistoryRepository = (*gitHistoryRepository)(nil)
+
+func NewGitHistoryRepository(ctx storage.AppDatabaseContext) GitHistoryRepository {
+	return &gitHistoryRepository{ctx: ctx}
+}
+
+// QuerySharingRoots implements GitHistoryRepository.
+func (g *gitHistoryRepository) QuerySharingRoots(link string, roots []string) (iter.Seq[*GitHistory], error) {
+	return sqlutil.QueryCommon[GitHistory](g.ctx, GitHistoryTableName,
+		"WHERE roots && $1 AND git_link <> $2", pq.StringArray(roots), link)
+}
+
+// InsertOrUpdate implements GitHistoryRepository.
+func (g *gitHistoryRepository) InsertOrUpdate(data *GitHistory) error {
+	if data.GitLink == nil || data.Roots == nil || data.Sketch == nil || data.Commits == nil {
+		return ErrInvalidInput
+	}
+	data.UpdateTime = sqlutil.ToNullable(time.Now())
+	return sqlutil.Upsert(g.ctx, GitHistoryTableName, data)
+}
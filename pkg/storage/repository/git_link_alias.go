@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"iter"
+	"time"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/storage"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/sqlutil"
+)
+
+// GitLinkAliasRepository maps the spellings and former locations of the git
+// links to their canonical link, so that a repository is collected and
+// scored once. all_gitlinks resolves the links of every source through it.
+type GitLinkAliasRepository interface {
+	/** QUERY **/
+	// Resolve returns the canonical link of link, link itself if it is not
+	// an alias.
+	Resolve(link string) (string, error)
+	// QueryAll returns the canonical link of every alias.
+	QueryAll() (map[string]string, error)
+	Query() (iter.Seq[*GitLinkAlias], error)
+
+	/** INSERT/UPDATE **/
+	// Insert maps alias to link, the aliases of alias are mapped to link
+	// too. If link is an alias of alias, e.g. a repository renamed back,
+	// link is canonical again.
+	Insert(alias string, link string, reason GitLinkAliasReason) error
+}
+
+type GitLinkAliasReason string
+
+const (
+	// GitLinkAliasVariant is another spelling of the canonical link, e.g.
+	// with .git or over ssh
+	GitLinkAliasVariant GitLinkAliasReason = "variant"
+	// GitLinkAliasRedirect is a former location of the repository, e.g.
+	// before it was renamed or transferred
+	GitLinkAliasRedirect GitLinkAliasReason = "redirect"
+)
+
+type GitLinkAlias struct {
+	Alias      *string `pk:"true"`
+	GitLink    *string
+	Reason     *GitLinkAliasReason
+	UpdateTime *time.Time
+}
+
+const GitLinkAliasTableName = "git_link_aliases"
+
+type gitLinkAliasRepository struct {
+	ctx storage.AppDatabaseContext
+}
+
+var _ GitLinkAliasRepository = (*gitLinkAliasRepository)(nil)
+
+func NewGitLinkAliasRepository(ctx storage.AppDatabaseContext) GitLinkAliasRepository {
+	return &gitLinkAliasRepository{ctx: ctx}
+}
+
+// Resolve implements GitLinkAliasRepository.
+func (g *gitLinkAliasRepository) Resolve(link string) (string, error) {
+	alias, err := sqlutil.QueryCommonFirst[GitLinkAlias](g.ctx, GitLinkAliasTableName, "WHERE alias = $1", link)
+	if err != nil || alias == nil {
+		return link, err
+	}
+	return *alias.GitLink, nil
+}
+
+// Query implements GitLinkAliasRepository.
+func (g *gitLinkAliasRepository) Query() (iter.Seq[*GitLinkAlias], error) {
+	return sqlutil.QueryCommon[GitLinkAlias](g.ctx, GitLinkAliasTableName, "")
+}
+
+// QueryAll implements GitLinkAliasRepository.
+func (g *gitLinkAliasRepository) QueryAll() (map[string]string, error) {
+	aliases, err := g.Query()
+	if err != nil {
+		return nil, err
+	}
+	m := make(map[string]string)
+	for alias := range aliases {
+		m[*alias.Alias] = *alias.GitLink
+	}
+	return m, nil
+}
+
+// Insert implements GitLinkAliasRepository.
+func (g *gitLinkAliasRepository) Insert(alias string, link string, reason GitLinkAliasReason) error {
+	if alias == link {
+		return nil
+	}
+	canonical, err := g.Resolve(link)
+	if err != nil {
+		return err
+	}
+	if canonical == alias {
+		if _, err := g.ctx.Exec(`DELETE FROM `+GitLinkAliasTableName+` WHERE alias = $1`, link); err != nil {
+			return err
+		}
+		canonical = link
+	}
+	_, err = g.ctx.Exec(`UPDATE `+GitLinkAliasTableName+` SET git_link = $2, update_time = now()
+		WHERE git_link = $1`, alias, canonical)
+	if err != nil {
+		return err
+	}
+	_, err = g.ctx.Exec(`INSERT INTO `+GitLinkAliasTableName+` (alias, git_link, reason) VALUES ($1, $2, $3)
+		ON CONFLICT (alias) DO UPDATE SET git_link = $2, reason = $3, update_time = now()`, alias, canonical, reason)
+	return err
+}
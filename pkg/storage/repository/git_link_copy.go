@@ -0,0 +1,213 @@
+package repository
+
+import (
+	"iter"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/storage"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/sqlutil"
+)
+
+// GitLinkCopyRepository maps the mirrors and forks of a repository found by
+// the collector to the canonical repository. The mirrors, and the forks
+// confirmed by a labeler, are scored as the canonical repository; the other
+// forks are only recorded, as a hard fork shares most of the history of its
+// project. Unlike aliases, copies are still collected, as their history may
+// diverge.
+type GitLinkCopyRepository interface {
+	/** QUERY **/
+	// QueryByLink returns nil if link is not a copy.
+	QueryByLink(link string) (*GitLinkCopy, error)
+	// QueryByCanonical returns the copies of canonical.
+	QueryByCanonical(canonical string) (iter.Seq[*GitLinkCopy], error)
+	// QueryAll returns the canonical link of every copy.
+	QueryAll() (map[string]string, error)
+	// QueryScored returns the canonical link of every copy scored as it: the
+	// mirrors and the confirmed forks.
+	QueryScored() (map[string]string, error)
+	// QueryWithFilter returns a page of the copies of relation, or of every
+	// relation if it is empty, confirmed or not if confirmed is not nil,
+	// whose link or canonical link contains search.
+	QueryWithFilter(relation GitLinkCopyRelation, confirmed *bool, search string, skip, take int) (iter.Seq[*GitLinkCopy], int, error)
+
+	/** INSERT/UPDATE **/
+	// Insert marks link as a copy of canonical, or of the canonical link of
+	// canonical if it is a copy itself. The copies of link become copies of
+	// canonical too, and so do the former canonical link of link and its
+	// copies, so that no copy is left of a link which is a copy itself.
+	// The confirmation of a copy whose canonical link changes is dropped.
+	Insert(link string, canonical string, relation GitLinkCopyRelation) error
+	// Confirm sets whether a labeler confirmed that link is a copy of its
+	// canonical link.
+	Confirm(link string, confirmed bool) error
+	Delete(link string) error
+}
+
+type GitLinkCopyRelation string
+
+const (
+	// GitLinkMirror has the same history as its canonical repository
+	GitLinkMirror GitLinkCopyRelation = "mirror"
+	// GitLinkFork has most of the history of its canonical repository, or
+	// the other way round, it is scored as it once confirmed
+	GitLinkFork GitLinkCopyRelation = "fork"
+)
+
+type GitLinkCopy struct {
+	GitLink    *string `pk:"true"`
+	Canonical  *string
+	Relation   *GitLinkCopyRelation
+	Confirmed  *bool
+	UpdateTime *time.Time
+}
+
+const GitLinkCopyTableName = "git_link_copies"
+
+type gitLinkCopyRepository struct {
+	ctx storage.AppDatabaseContext
+}
+
+var _ GitLinkCopyRepository = (*gitLinkCopyRepository)(nil)
+
+func NewGitLinkCopyRepository(ctx storage.AppDatabaseContext) GitLinkCopyRepository {
+	return &gitLinkCopyRepository{ctx: ctx}
+}
+
+// QueryByLink implements GitLinkCopyRepository.
+func (g *gitLinkCopyRepository) QueryByLink(link string) (*GitLinkCopy, error) {
+	return sqlutil.QueryCommonFirst[GitLinkCopy](g.ctx, GitLinkCopyTableName, "WHERE git_link = $1", link)
+}
+
+// QueryByCanonical implements GitLinkCopyRepository.
+func (g *gitLinkCopyRepository) QueryByCanonical(canonical string) (iter.Seq[*GitLinkCopy], error) {
+	return sqlutil.QueryCommon[GitLinkCopy](g.ctx, GitLinkCopyTableName, "WHERE canonical = $1 ORDER BY git_link", canonical)
+}
+
+// QueryAll implements GitLinkCopyRepository.
+func (g *gitLinkCopyRepository) QueryAll() (map[string]string, error) {
+	copies, err := sqlutil.QueryCommon[GitLinkCopy](g.ctx, GitLinkCopyTableName, "")
+	if err != nil {
+		return nil, err
+	}
+	m := make(map[string]string)
+	for c := range copies {
+		m[*c.GitLink] = *c.Canonical
+	}
+	return m, nil
+}
+
+// QueryScored implements GitLinkCopyRepository.
+func (g *gitLinkCopyRepository) QueryScored() (map[string]string, error) {
+	copies, err := sqlutil.QueryCommon[GitLinkCopy](g.ctx, GitLinkCopyTableName, "WHERE relation = $1 OR confirmed", GitLinkMirror)
+	if err != nil {
+		return nil, err
+	}
+	m := make(map[string]string)
+	for c := range copies {
+		m[*c.GitLink] = *c.Canonical
+	}
+	return m, nil
+}
+
+// QueryWithFilter implements GitLinkCopyRepository.
+func (g *gitLinkCopyRepository) QueryWithFilter(relation GitLinkCopyRelation, confirmed *bool, search string, skip, take int) (iter.Seq[*GitLinkCopy], int, error) {
+	whereClauses := []string{}
+	args := []any{}
+	if relation != "" {
+		args = append(args, relation)
+		whereClauses = append(whereClauses, "relation = $"+strconv.Itoa(len(args)))
+	}
+	if confirmed != nil {
+		args = append(args, *confirmed)
+		whereClauses = append(whereClauses, "confirmed = $"+strconv.Itoa(len(args)))
+	}
+	if search != "" {
+		args = append(args, search)
+		whereClauses = append(whereClauses, "(git_link LIKE '%' || $"+strconv.Itoa(len(args))+" || '%' OR canonical LIKE '%' || $"+strconv.Itoa(len(args))+" || '%')")
+	}
+	where := ""
+	if len(whereClauses) > 0 {
+		where = "WHERE " + strings.Join(whereClauses, " AND ")
+	}
+
+	var cnt int
+	if err := g.ctx.QueryRow("SELECT COUNT(*) FROM "+GitLinkCopyTableName+" "+where, args...).Scan(&cnt); err != nil {
+		return nil, 0, err
+	}
+	args = append(args, take, skip)
+	res, err := sqlutil.QueryCommon[GitLinkCopy](g.ctx, GitLinkCopyTableName,
+		where+" ORDER BY canonical, git_link LIMIT $"+strconv.Itoa(len(args)-1)+" OFFSET $"+strconv.Itoa(len(args)), args...)
+	return res, cnt, err
+}
+
+// Insert implements GitLinkCopyRepository.
+func (g *gitLinkCopyRepository) Insert(link string, canonical string, relation GitLinkCopyRelation) error {
+	if link == canonical {
+		return nil
+	}
+	former, err := g.QueryByLink(link)
+	if err != nil {
+		return err
+	}
+	c, err := g.QueryByLink(canonical)
+	if err != nil {
+		return err
+	}
+	if c != nil {
+		if *c.Canonical == link {
+			// canonical was a copy of link, it is canonical now
+			if err := g.Delete(canonical); err != nil {
+				return err
+			}
+		} else {
+			canonical = *c.Canonical
+		}
+	}
+	if former != nil && *former.Canonical != canonical {
+		// the former canonical link is a copy of canonical through link, a
+		// mirror only if link mirrors both
+		formerRelation := relation
+		if *former.Relation != GitLinkMirror {
+			formerRelation = GitLinkFork
+		}
+		if err := g.insert(*former.Canonical, canonical, formerRelation); err != nil {
+			return err
+		}
+	}
+	return g.insert(link, canonical, relation)
+}
+
+// insert marks link and its copies as copies of canonical, which is not a
+// copy itself.
+func (g *gitLinkCopyRepository) insert(link string, canonical string, relation GitLinkCopyRelation) error {
+	_, err := g.ctx.Exec(`UPDATE `+GitLinkCopyTableName+` SET canonical = $2, confirmed = false, update_time = now()
+		WHERE canonical = $1`, link, canonical)
+	if err != nil {
+		return err
+	}
+	_, err = g.ctx.Exec(`INSERT INTO `+GitLinkCopyTableName+` (git_link, canonical, relation) VALUES ($1, $2, $3)
+		ON CONFLICT (git_link) DO UPDATE SET canonical = $2, relation = $3,
+			confirmed = `+GitLinkCopyTableName+`.confirmed AND `+GitLinkCopyTableName+`.canonical = $2, update_time = now()`,
+		link, canonical, relation)
+	return err
+}
+
+// Confirm implements GitLinkCopyRepository.
+func (g *gitLinkCopyRepository) Confirm(link string, confirmed bool) error {
+	res, err := g.ctx.Exec(`UPDATE `+GitLinkCopyTableName+` SET confirmed = $2, update_time = now()
+		WHERE git_link = $1`, link, confirmed)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return ErrInvalidInput
+	}
+	return nil
+}
+
+// Delete implements GitLinkCopyRepository.
+func (g *gitLinkCopyRepository) Delete(link string) error {
+	return sqlutil.Delete(g.ctx, GitLinkCopyTableName, &GitLinkCopy{GitLink: &link})
+}
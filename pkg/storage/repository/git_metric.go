@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"database/sql/driver"
+	"encoding/json"
 	"fmt"
 	"iter"
 	"strconv"
@@ -30,9 +32,18 @@ type GitMetricsRepository interface {
 	QueryGitFiles(linkQuery string, successFilter int, skip int, take int) (iter.Seq[*GitFile], int, error)
 	GetGitFileByLink(link string) (*GitFile, error)
 	GetGitFilesStatistics() (*GitFileStatisticsResult, error)
+	// QueryEvictionCandidates returns the mirrors taking storage which are
+	// not being collected, from the least critical, i.e. of the lowest score
+	// of the last round, and least recently collected one. If shallowLast,
+	// the shallow mirrors come after the others, so that the mirrors are
+	// pruned before any pruned one is evicted.
+	QueryEvictionCandidates(limit int, shallowLast bool) (iter.Seq[*GitFile], error)
 
 	// times will be updated automatically
 	InsertOrUpdateGitFile(data *GitFile, success bool) error
+	// UpdateGitFileStorage records that the mirror of link has been pruned to
+	// size bytes, or removed if size is 0.
+	UpdateGitFileStorage(link string, size int64, shallow bool) error
 	DeleteGitFile(link string) error
 }
 
@@ -48,6 +59,70 @@ type GitMetric struct {
 	Language         **pq.StringArray
 	CloneValid       **bool
 	UpdateTime       **time.Time
+	// activity of the windows of 30, 90 and 365 days before the collection
+	// and of the whole history: commits, distinct authors, distinct orgs and
+	// authors of at least 3 commits
+	Commits30d          **int `column:"commits_30d"`
+	Authors30d          **int `column:"authors_30d"`
+	Orgs30d             **int `column:"orgs_30d"`
+	Maintainers30d      **int `column:"maintainers_30d"`
+	Commits90d          **int `column:"commits_90d"`
+	Authors90d          **int `column:"authors_90d"`
+	Orgs90d             **int `column:"orgs_90d"`
+	Maintainers90d      **int `column:"maintainers_90d"`
+	Commits365d         **int `column:"commits_365d"`
+	Authors365d         **int `column:"authors_365d"`
+	Orgs365d            **int `column:"orgs_365d"`
+	Maintainers365d     **int `column:"maintainers_365d"`
+	CommitsLifetime     **int `column:"commits_lifetime"`
+	AuthorsLifetime     **int `column:"authors_lifetime"`
+	OrgsLifetime        **int `column:"orgs_lifetime"`
+	MaintainersLifetime **int `column:"maintainers_lifetime"`
+	// concentration of the commits of the last 12 months, and the last
+	// commit of the top 3 contributors of the whole history
+	BusFactor50              **int `column:"bus_factor_50"`
+	BusFactor80              **int `column:"bus_factor_80"`
+	CommitGini               **float64
+	TopOrgShare              **float64
+	TopMaintainersLastCommit **time.Time
+	// releases found from the tags looking like versions, the interval is
+	// in days
+	SemverTags            **int
+	Releases365d          **int `column:"releases_365d"`
+	LastRelease           **time.Time
+	MedianReleaseInterval **float64
+	// spdx expression of the licenses of the repository, the percentage of
+	// the source files under each expression and the families of the
+	// licenses found
+	LicenseExpression **string
+	LicenseCoverage   **LicenseCoverage
+	LicenseFamilies   **pq.StringArray
+}
+
+// LicenseCoverage is the percentage of the source files of a repository
+// under each license expression, stored as json.
+type LicenseCoverage map[string]float64
+
+// Value implements driver.Valuer.
+func (c LicenseCoverage) Value() (driver.Value, error) {
+	if c == nil {
+		return nil, nil
+	}
+	return json.Marshal(c)
+}
+
+// Scan implements sql.Scanner.
+func (c *LicenseCoverage) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*c = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, c)
+	case string:
+		return json.Unmarshal([]byte(v), c)
+	}
+	return fmt.Errorf("cannot scan %T into LicenseCoverage", src)
 }
 
 type GitFile struct {
@@ -59,7 +134,19 @@ type GitFile struct {
 	FailedTimes **int
 	LastSuccess **time.Time
 	TakeTimeMs  **int64
+	// TakeStorage is the size in bytes of the mirror, 0 once evicted
 	TakeStorage **int64
+	// Shallow is set if the history of the mirror has been pruned
+	Shallow *bool
+	// EvictTime is the time the mirror was last evicted or pruned, it is
+	// reset by a successful collection
+	EvictTime **time.Time
+	// CloneFilter is the filter of a partial mirror, e.g. blob:none, it is
+	// kept after an eviction
+	CloneFilter **string
+	// FullStorage is the size in bytes of the last full mirror, before it
+	// was pruned or cloned again without blobs, null if never measured
+	FullStorage **int64
 }
 
 type GitFileStatisticsResult struct {
@@ -67,6 +154,12 @@ type GitFileStatisticsResult struct {
 	Success      *int
 	Fail         *int
 	NeverSuccess *int
+	// Storage is the size in bytes of all the mirrors
+	Storage *int64
+	Mirrors *int
+	Shallow *int
+	Evicted *int
+	Partial *int
 }
 
 const GitMetricTableName = "git_metrics"
@@ -131,10 +224,12 @@ func (g *gitmetricsRepository) DeleteGitFile(link string) error {
 // InsertOrUpdateGitFile implements GitMetricsRepository.
 func (g *gitmetricsRepository) InsertOrUpdateGitFile(data *GitFile, success bool) error {
 	if success {
-		_, err := g.ctx.Exec(`INSERT INTO `+GitFilesTableName+` (git_link, file_path, success, message, update_time, failed_times, last_success, take_time_ms)
-		VALUES ($1, $2, true, $3, $4, 0, $4, $5)
-		ON CONFLICT (git_link) DO UPDATE SET file_path = $2, success = true, message = $3, update_time = $4, failed_times = 0, last_success = $4, take_time_ms = $5`,
-			data.GitLink, data.FilePath, data.Message, data.UpdateTime, data.TakeTimeMs)
+		_, err := g.ctx.Exec(`INSERT INTO `+GitFilesTableName+` (git_link, file_path, success, message, update_time, failed_times, last_success, take_time_ms, take_storage, shallow, clone_filter, full_storage)
+		VALUES ($1, $2, true, $3, $4, 0, $4, $5, $6, coalesce($7, false), $8, $9)
+		ON CONFLICT (git_link) DO UPDATE SET file_path = $2, success = true, message = $3, update_time = $4, failed_times = 0, last_success = $4, take_time_ms = $5,
+			take_storage = $6, shallow = coalesce($7, false), evict_time = NULL, clone_filter = $8,
+			full_storage = coalesce($9, `+GitFilesTableName+`.full_storage)`,
+			data.GitLink, data.FilePath, data.Message, data.UpdateTime, data.TakeTimeMs, data.TakeStorage, data.Shallow, data.CloneFilter, data.FullStorage)
 		return err
 
 	} else {
@@ -152,12 +247,44 @@ func (g *gitmetricsRepository) GetGitFilesStatistics() (*GitFileStatisticsResult
 			COUNT(*) AS total,
 			COUNT(*) FILTER (WHERE success = TRUE) AS success,
 			COUNT(*) FILTER (WHERE success = FALSE) AS fail,
-			COUNT(*) FILTER (WHERE last_success is null) AS never_success
+			COUNT(*) FILTER (WHERE last_success is null) AS never_success,
+			COALESCE(SUM(take_storage), 0) AS storage,
+			COUNT(*) FILTER (WHERE take_storage > 0) AS mirrors,
+			COUNT(*) FILTER (WHERE take_storage > 0 AND shallow) AS shallow,
+			COUNT(*) FILTER (WHERE take_storage = 0 AND evict_time is not null) AS evicted,
+			COUNT(*) FILTER (WHERE take_storage > 0 AND clone_filter is not null) AS partial
 		FROM git_files
 
 	`)
 }
 
+// QueryEvictionCandidates implements GitMetricsRepository.
+func (g *gitmetricsRepository) QueryEvictionCandidates(limit int, shallowLast bool) (iter.Seq[*GitFile], error) {
+	order := `criticality, last_success nulls first, git_link`
+	if shallowLast {
+		order = `shallow, ` + order
+	}
+	return sqlutil.QueryCommon[GitFile](g.ctx, `(
+		select gf.*, coalesce(s.score, 0) as criticality
+		from `+GitFilesTableName+` gf
+		left join lateral (
+			select score from scores
+			where scores.git_link = gf.git_link and scores.round = (select max(round) from scores)
+			order by scores.id desc limit 1
+		) as s on true
+		where gf.take_storage > 0 and not exists (
+			select 1 from `+GitTaskTableName+` gt
+			where gt.git_link = gf.git_link and gt.leased_until >= now())
+	) as candidates`, `order by `+order+` limit $1`, limit)
+}
+
+// UpdateGitFileStorage implements GitMetricsRepository.
+func (g *gitmetricsRepository) UpdateGitFileStorage(link string, size int64, shallow bool) error {
+	_, err := g.ctx.Exec(`UPDATE `+GitFilesTableName+` SET take_storage = $2, shallow = $3, evict_time = now()
+		WHERE git_link = $1`, link, size, shallow)
+	return err
+}
+
 // QueryGitFiles implements GitMetricsRepository.
 func (g *gitmetricsRepository) QueryGitFiles(linkQuery string, successFilter, skip int, take int) (iter.Seq[*GitFile], int, error) {
 	var whereSentences = make([]string, 0)
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"iter"
+	"time"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/storage"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/sqlutil"
+)
+
+// GitPackageRepository stores the packages declared by the manifests and lock
+// files at the head of a repository, and the packages they depend on.
+type GitPackageRepository interface {
+	/** QUERY **/
+	QueryPackages() (iter.Seq[*GitPackage], error)
+	QueryDependencies() (iter.Seq[*GitPackageDependency], error)
+
+	/** INSERT/UPDATE **/
+	// Replace replaces the packages and dependencies of link.
+	Replace(link string, packages []*GitPackage, dependencies []*GitPackageDependency) error
+}
+
+type GitPackage struct {
+	GitLink    *string `pk:"true"`
+	Ecosystem  *string `pk:"true"`
+	Name       *string `pk:"true"`
+	Version    **string
+	UpdateTime *time.Time
+}
+
+type GitPackageDependency struct {
+	GitLink    *string `pk:"true"`
+	Ecosystem  *string `pk:"true"`
+	Name       *string `pk:"true"`
+	UpdateTime *time.Time
+}
+
+const (
+	GitPackageTableName           = "git_packages"
+	GitPackageDependencyTableName = "git_package_dependencies"
+)
+
+type gitPackageRepository struct {
+	ctx storage.AppDatabaseContext
+}
+
+var _ GitPackageRepository = (*gitPackageRepository)(nil)
+
+func NewGitPackageRepository(ctx storage.AppDatabaseContext) GitPackageRepository {
+	return &gitPackageRepository{ctx: ctx}
+}
+
+// QueryPackages implements GitPackageRepository.
+func (g *gitPackageRepository) QueryPackages() (iter.Seq[*GitPackage], error) {
+	return sqlutil.QueryCommon[GitPackage](g.ctx, GitPackageTableName, "")
+}
+
+// QueryDependencies implements GitPackageRepository.
+func (g *gitPackageRepository) QueryDependencies() (iter.Seq[*GitPackageDependency], error) {
+	return sqlutil.QueryCommon[GitPackageDependency](g.ctx, GitPackageDependencyTableName, "")
+}
+
+// Replace implements GitPackageRepository.
+func (g *gitPackageRepository) Replace(link string, packages []*GitPackage, dependencies []*GitPackageDependency) error {
+	if link == "" {
+		return ErrInvalidInput
+	}
+	for _, p := range packages {
+		if p.GitLink == nil || *p.GitLink != link || p.Ecosystem == nil || p.Name == nil {
+			return ErrInvalidInput
+		}
+	}
+	for _, d := range dependencies {
+		if d.GitLink == nil || *d.GitLink != link || d.Ecosystem == nil || d.Name == nil {
+			return ErrInvalidInput
+		}
+	}
+
+	for _, table := range []string{GitPackageTableName, GitPackageDependencyTableName} {
+		if _, err := g.ctx.Exec(`DELETE FROM `+table+` WHERE git_link = $1`, link); err != nil {
+			return err
+		}
+	}
+	if len(packages) > 0 {
+		if err := sqlutil.BatchInsert(g.ctx, GitPackageTableName, packages); err != nil {
+			return err
+		}
+	}
+	if len(dependencies) > 0 {
+		return sqlutil.BatchInsert(g.ctx, GitPackageDependencyTableName, dependencies)
+	}
+	return nil
+}
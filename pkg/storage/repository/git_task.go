@@ -0,0 +1,229 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"iter"
+	"time"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/storage"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/sqlutil"
+)
+
+// GitTaskRepository is the queue of the git links to collect, shared by the
+// collectors of every host. A task is leased by one collector at a time:
+// the lease expires if the collector does not renew it, e.g. if it crashed,
+// and the task is leased again by another one.
+type GitTaskRepository interface {
+	/** QUERY **/
+	// QueryPending returns the tasks Lease would lease next, in the order
+	// they are leased
+	QueryPending(limit int, maxAttempts int) (iter.Seq[*GitTask], error)
+	// CountPending counts the tasks Lease may lease.
+	CountPending(maxAttempts int) (int, error)
+
+	/** INSERT/UPDATE **/
+	// Enqueue adds at most limit git links due for collection which are not
+	// queued yet, those of the highest score of the last round first, then
+	// by their nice. It returns the number of tasks added.
+	Enqueue(limit int) (int64, error)
+	// EnqueueManual adds link before every other task not leased.
+	EnqueueManual(link string) error
+	// Lease leases the next available task to owner for lease, it returns nil
+	// if there is none. The tasks leased maxAttempts times are skipped.
+	Lease(owner string, lease time.Duration, maxAttempts int) (*GitTask, error)
+	// Renew extends every lease of owner not expired yet.
+	Renew(owner string, lease time.Duration) (int64, error)
+	// Complete removes the task of link leased by owner.
+	Complete(owner string, link string) error
+	// Release ends the lease of the task of link by owner without counting
+	// it as an attempt, e.g. when the host throttled the clone. The task is
+	// leased again after until, reason is recorded as its last error.
+	Release(owner string, link string, until time.Time, reason string) error
+	// Hold leases the task of link to owner for lease unless it is leased,
+	// queuing it if it is not queued, so that no collector collects it while
+	// its mirror is worked on. It reports whether the task is held, and
+	// whether Hold queued it, in which case it is to be removed by Complete
+	// rather than kept by Unhold.
+	Hold(owner string, link string, lease time.Duration) (held bool, queued bool, err error)
+	// Unhold ends the lease of the task of link by owner, keeping it queued.
+	Unhold(owner string, link string) error
+	// Park delays the tasks leased maxAttempts times whose lease has
+	// expired, their attempts are reset.
+	Park(maxAttempts int, delay time.Duration) (int64, error)
+}
+
+type GitTask struct {
+	GitLink *string `pk:"true"`
+	// Priority is the score of the link of the last round, tasks of higher
+	// priority are leased first
+	Priority *float64
+	// Manual tasks are leased before the others
+	Manual *bool
+	// Attempts is the number of times the task has been leased
+	Attempts    *int
+	LeaseOwner  **string
+	LeasedUntil **time.Time
+	AvailableAt *time.Time
+	LastError   **string
+	EnqueueTime *time.Time
+	UpdateTime  *time.Time
+}
+
+const GitTaskTableName = "git_tasks"
+
+// order of the tasks leased
+const gitTaskOrder = `manual desc, priority desc, enqueue_time, git_link`
+
+type gitTaskRepository struct {
+	ctx storage.AppDatabaseContext
+}
+
+var _ GitTaskRepository = (*gitTaskRepository)(nil)
+
+func NewGitTaskRepository(ctx storage.AppDatabaseContext) GitTaskRepository {
+	return &gitTaskRepository{ctx: ctx}
+}
+
+// QueryPending implements GitTaskRepository.
+func (g *gitTaskRepository) QueryPending(limit int, maxAttempts int) (iter.Seq[*GitTask], error) {
+	return sqlutil.QueryCommon[GitTask](g.ctx, GitTaskTableName,
+		`where available_at <= now() and attempts < $2
+			and (leased_until is null or leased_until < now())
+		order by `+gitTaskOrder+` limit $1`, limit, maxAttempts)
+}
+
+// CountPending implements GitTaskRepository.
+func (g *gitTaskRepository) CountPending(maxAttempts int) (int, error) {
+	row := g.ctx.QueryRow(`select count(*) from `+GitTaskTableName+`
+		where available_at <= now() and attempts < $1
+			and (leased_until is null or leased_until < now())`, maxAttempts)
+	var count int
+	err := row.Scan(&count)
+	return count, err
+}
+
+// Enqueue implements GitTaskRepository.
+func (g *gitTaskRepository) Enqueue(limit int) (int64, error) {
+	res, err := g.ctx.Exec(`insert into `+GitTaskTableName+` (git_link, priority)
+	select ranked.git_link, coalesce(s.score, 0)
+	from (`+rankedGitTaskQuery+`) as ranked
+	left join lateral (
+		select score from scores
+		where scores.git_link = ranked.git_link and scores.round = (select max(round) from scores)
+		order by scores.id desc limit 1
+	) as s on true
+	where not exists (select 1 from `+GitTaskTableName+` gt where gt.git_link = ranked.git_link)
+	order by s.score desc nulls last, ranked.nice
+	limit $1
+	on conflict (git_link) do nothing`, limit)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// EnqueueManual implements GitTaskRepository.
+func (g *gitTaskRepository) EnqueueManual(link string) error {
+	_, err := g.ctx.Exec(`insert into `+GitTaskTableName+` (git_link, manual)
+	values ($1, true)
+	on conflict (git_link) do update set manual = true, available_at = now(), update_time = now()`, link)
+	return err
+}
+
+// Lease implements GitTaskRepository.
+func (g *gitTaskRepository) Lease(owner string, lease time.Duration, maxAttempts int) (*GitTask, error) {
+	// skip locked lets the collectors lease concurrently without leasing
+	// the same task twice
+	return sqlutil.QueryFirst[GitTask](g.ctx, `update `+GitTaskTableName+` as gt set
+		lease_owner = $1,
+		leased_until = now() + $2 * interval '1 millisecond',
+		attempts = gt.attempts + 1,
+		update_time = now()
+	where gt.git_link = (
+		select git_link from `+GitTaskTableName+`
+		where available_at <= now() and attempts < $3
+			and (leased_until is null or leased_until < now())
+		order by `+gitTaskOrder+`
+		limit 1
+		for update skip locked)
+	returning gt.*`, owner, lease.Milliseconds(), maxAttempts)
+}
+
+// Renew implements GitTaskRepository.
+func (g *gitTaskRepository) Renew(owner string, lease time.Duration) (int64, error) {
+	res, err := g.ctx.Exec(`update `+GitTaskTableName+` set
+		leased_until = now() + $2 * interval '1 millisecond',
+		update_time = now()
+	where lease_owner = $1 and leased_until >= now()`, owner, lease.Milliseconds())
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// Complete implements GitTaskRepository.
+func (g *gitTaskRepository) Complete(owner string, link string) error {
+	_, err := g.ctx.Exec(`delete from `+GitTaskTableName+` where git_link = $1 and lease_owner = $2`, link, owner)
+	return err
+}
+
+// Hold implements GitTaskRepository.
+func (g *gitTaskRepository) Hold(owner string, link string, lease time.Duration) (bool, bool, error) {
+	// xmax is 0 for the row inserted, not for the row updated
+	row := g.ctx.QueryRow(`insert into `+GitTaskTableName+` as gt (git_link, lease_owner, leased_until)
+	values ($1, $2, now() + $3 * interval '1 millisecond')
+	on conflict (git_link) do update set
+		lease_owner = $2,
+		leased_until = now() + $3 * interval '1 millisecond',
+		update_time = now()
+	where gt.leased_until is null or gt.leased_until < now()
+	returning (xmax = 0)`, link, owner, lease.Milliseconds())
+	var queued bool
+	if err := row.Scan(&queued); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, false, nil
+		}
+		return false, false, err
+	}
+	return true, queued, nil
+}
+
+// Unhold implements GitTaskRepository.
+func (g *gitTaskRepository) Unhold(owner string, link string) error {
+	_, err := g.ctx.Exec(`update `+GitTaskTableName+` set
+		lease_owner = null,
+		leased_until = null,
+		update_time = now()
+	where git_link = $1 and lease_owner = $2`, link, owner)
+	return err
+}
+
+// Release implements GitTaskRepository.
+func (g *gitTaskRepository) Release(owner string, link string, until time.Time, reason string) error {
+	_, err := g.ctx.Exec(`update `+GitTaskTableName+` set
+		attempts = greatest(attempts - 1, 0),
+		lease_owner = null,
+		leased_until = null,
+		available_at = $3,
+		last_error = $4,
+		update_time = now()
+	where git_link = $1 and lease_owner = $2`, link, owner, until, reason)
+	return err
+}
+
+// Park implements GitTaskRepository.
+func (g *gitTaskRepository) Park(maxAttempts int, delay time.Duration) (int64, error) {
+	res, err := g.ctx.Exec(`update `+GitTaskTableName+` set
+		attempts = 0,
+		lease_owner = null,
+		leased_until = null,
+		available_at = now() + $2 * interval '1 millisecond',
+		last_error = 'lease expired ' || attempts || ' times, last owner ' || coalesce(lease_owner, ''),
+		update_time = now()
+	where attempts >= $1 and (leased_until is null or leased_until < now())`, maxAttempts, delay.Milliseconds())
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
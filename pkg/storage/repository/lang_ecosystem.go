@@ -33,6 +33,14 @@ const (
 	NuGet
 	Cargo
 	Others
+	// the dependents of the following ecosystems are counted from the
+	// packages of the repositories collected, see GitPackageRepository
+	Packagist
+	RubyGems
+	Hex
+	Conan
+	Conda
+	SwiftPM
 )
 
 type langEcoLinkRepository struct {
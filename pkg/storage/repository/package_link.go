@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"database/sql"
+	"iter"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/storage"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/sqlutil"
+	"github.com/lib/pq"
+)
+
+// PackageLinkRepository stores the index of the repositories of the packages
+// of language ecosystems, from the manifests of the repositories collected,
+// and whether it agrees with their registries.
+type PackageLinkRepository interface {
+	/** QUERY **/
+	QueryByName(ecosystem string, name string) (*PackageLink, error)
+	// QueryWithFilter returns a page of the links of status, or of every
+	// status if it is empty, of ecosystem if it is not empty, and whose
+	// name contains search.
+	QueryWithFilter(status PackageLinkStatus, ecosystem string, search string, skip, take int) (iter.Seq[*PackageLink], int, error)
+
+	/** INSERT/UPDATE **/
+	// InsertOrUpdate updates the links of a package, keeping its label.
+	InsertOrUpdate(data *PackageLink) error
+	// Replace stores links, the whole index, and deletes the packages not in
+	// it unless they are labeled, in one transaction.
+	Replace(links []*PackageLink) error
+	// UpdateLabel sets the repository chosen by a labeler, none if link is
+	// nil. The label is for review only, see PackageLink.LabelLink.
+	UpdateLabel(ecosystem string, name string, link *string) error
+}
+
+type PackageLinkStatus string
+
+const (
+	// PackageLinkAgree means the repository declared by the registry
+	// declares the package
+	PackageLinkAgree PackageLinkStatus = "agree"
+	// PackageLinkDisagree means the repository declared by the registry does
+	// not declare the package while others do, or declares other packages
+	// only, e.g. the registry field is stale or the package is a typo-squat
+	PackageLinkDisagree PackageLinkStatus = "disagree"
+	// PackageLinkUnregistered means the registry declares no repository, or
+	// the registry of the ecosystem is not enumerated: only the npm and PyPI
+	// enumerators record the repositories of their packages. The Go module
+	// proxy has no enumerator and the crates.io and NuGet enumerators only
+	// list links, so every Go module, crate and NuGet package is unregistered
+	// even if its registry declares a repository
+	PackageLinkUnregistered PackageLinkStatus = "unregistered"
+)
+
+type PackageLink struct {
+	Ecosystem     *string `pk:"true"`
+	Name          *string `pk:"true"`
+	ManifestLinks *pq.StringArray
+	RegistryLink  **string
+	Status        *PackageLinkStatus
+	// LabelLink is the repository chosen by a labeler. It is for review
+	// only: it keeps the package in the index, but no package is resolved
+	// to its repository with it
+	LabelLink  **string
+	UpdateTime *time.Time
+}
+
+const PackageLinkTableName = "package_links"
+
+type packageLinkRepository struct {
+	ctx storage.AppDatabaseContext
+}
+
+var _ PackageLinkRepository = (*packageLinkRepository)(nil)
+
+func NewPackageLinkRepository(ctx storage.AppDatabaseContext) PackageLinkRepository {
+	return &packageLinkRepository{ctx: ctx}
+}
+
+// QueryByName implements PackageLinkRepository.
+func (p *packageLinkRepository) QueryByName(ecosystem string, name string) (*PackageLink, error) {
+	return sqlutil.QueryCommonFirst[PackageLink](p.ctx, PackageLinkTableName, "WHERE ecosystem = $1 AND name = $2", ecosystem, name)
+}
+
+// QueryWithFilter implements PackageLinkRepository.
+func (p *packageLinkRepository) QueryWithFilter(status PackageLinkStatus, ecosystem string, search string, skip, take int) (iter.Seq[*PackageLink], int, error) {
+	whereClauses := []string{}
+	args := []any{}
+	if status != "" {
+		args = append(args, status)
+		whereClauses = append(whereClauses, "status = $"+strconv.Itoa(len(args)))
+	}
+	if ecosystem != "" {
+		args = append(args, ecosystem)
+		whereClauses = append(whereClauses, "ecosystem = $"+strconv.Itoa(len(args)))
+	}
+	if search != "" {
+		args = append(args, search)
+		whereClauses = append(whereClauses, "name LIKE '%' || $"+strconv.Itoa(len(args))+" || '%'")
+	}
+	where := ""
+	if len(whereClauses) > 0 {
+		where = "WHERE " + strings.Join(whereClauses, " AND ")
+	}
+
+	var cnt int
+	if err := p.ctx.QueryRow("SELECT COUNT(*) FROM "+PackageLinkTableName+" "+where, args...).Scan(&cnt); err != nil {
+		return nil, 0, err
+	}
+	args = append(args, take, skip)
+	res, err := sqlutil.QueryCommon[PackageLink](p.ctx, PackageLinkTableName,
+		where+" ORDER BY ecosystem, name LIMIT $"+strconv.Itoa(len(args)-1)+" OFFSET $"+strconv.Itoa(len(args)), args...)
+	return res, cnt, err
+}
+
+// InsertOrUpdate implements PackageLinkRepository.
+func (p *packageLinkRepository) InsertOrUpdate(data *PackageLink) error {
+	return insertOrUpdatePackageLink(p.ctx.Exec, data)
+}
+
+// Replace implements PackageLinkRepository.
+func (p *packageLinkRepository) Replace(links []*PackageLink) error {
+	db, err := p.ctx.GetDatabaseConnection()
+	if err != nil {
+		return err
+	}
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	ecosystems := make(pq.StringArray, 0, len(links))
+	names := make(pq.StringArray, 0, len(links))
+	for _, link := range links {
+		if err := insertOrUpdatePackageLink(tx.Exec, link); err != nil {
+			tx.Rollback()
+			return err
+		}
+		ecosystems = append(ecosystems, *link.Ecosystem)
+		names = append(names, *link.Name)
+	}
+	_, err = tx.Exec(`DELETE FROM `+PackageLinkTableName+` WHERE label_link IS NULL
+		AND (ecosystem, name) NOT IN (SELECT * FROM unnest($1::varchar[], $2::varchar[]))`, ecosystems, names)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func insertOrUpdatePackageLink(exec func(query string, args ...any) (sql.Result, error), data *PackageLink) error {
+	if data.Ecosystem == nil || data.Name == nil || data.ManifestLinks == nil || data.Status == nil {
+		return ErrInvalidInput
+	}
+	var registryLink *string
+	if data.RegistryLink != nil {
+		registryLink = *data.RegistryLink
+	}
+	_, err := exec(`INSERT INTO `+PackageLinkTableName+` (ecosystem, name, manifest_links, registry_link, status)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (ecosystem, name) DO UPDATE SET manifest_links = $3, registry_link = $4, status = $5, update_time = now()`,
+		*data.Ecosystem, *data.Name, *data.ManifestLinks, registryLink, *data.Status)
+	return err
+}
+
+// UpdateLabel implements PackageLinkRepository.
+func (p *packageLinkRepository) UpdateLabel(ecosystem string, name string, link *string) error {
+	res, err := p.ctx.Exec(`UPDATE `+PackageLinkTableName+` SET label_link = $3, update_time = now()
+		WHERE ecosystem = $1 AND name = $2`, ecosystem, name, link)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return ErrInvalidInput
+	}
+	return nil
+}
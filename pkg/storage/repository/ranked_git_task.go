@@ -27,9 +27,11 @@ func NewRankedGitTaskRepository(ctx storage.AppDatabaseContext) RankedGitTaskRep
 	return &rankedGitTaskRepository{ctx: ctx}
 }
 
-// query implements rankedgittaskrepository.
-func (r *rankedGitTaskRepository) Query(limit int) (iter.Seq[*RankedGitTask], error) {
-	return sqlutil.Query[RankedGitTask](r.ctx, `
+// rankedGitTaskQuery selects the git links due for collection, by nice: the
+// links never cloned, the failed ones after a backoff, then the ones not
+// collected for 30 days. The aliases of a canonical link are never collected,
+// even if they were cloned before their alias was known.
+const rankedGitTaskQuery = `
 select git_link, nice
 from (
     select git_link, 0 as nice from (
@@ -39,9 +41,15 @@ from (
 ) union all (
     select git_link, 1 + EXP(EXTRACT(HOUR from (update_time - now()))) as nice from git_files 
     where success = false and update_time < now() - least(pow(2, failed_times), 60) * interval '1 day'
+        and git_link not in (select alias from git_link_aliases)
 ) union all (
     select git_link, 2 + EXP(EXTRACT(DAY from (update_time - now()))) as nice from git_files 
 		where update_time < now() - interval '30 days'
-) ORDER BY nice LIMIT $1
+		and git_link not in (select alias from git_link_aliases)
+)`
+
+// query implements rankedgittaskrepository.
+func (r *rankedGitTaskRepository) Query(limit int) (iter.Seq[*RankedGitTask], error) {
+	return sqlutil.Query[RankedGitTask](r.ctx, rankedGitTaskQuery+` ORDER BY nice LIMIT $1
 	`, limit)
 }
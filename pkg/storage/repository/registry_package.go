@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"iter"
+	"time"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/storage"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/sqlutil"
+)
+
+// RegistryPackageRepository stores the repository of a package as declared
+// by the registry of its ecosystem, e.g. the repository field of npm.
+type RegistryPackageRepository interface {
+	/** QUERY **/
+	Query() (iter.Seq[*RegistryPackage], error)
+
+	/** INSERT/UPDATE **/
+	BatchInsertOrUpdate(data []*RegistryPackage) error
+}
+
+type RegistryPackage struct {
+	// Ecosystem is that of the parsers, as GitPackage.Ecosystem
+	Ecosystem  *string `pk:"true"`
+	Name       *string `pk:"true"`
+	GitLink    *string
+	UpdateTime *time.Time
+}
+
+const RegistryPackageTableName = "registry_packages"
+
+type registryPackageRepository struct {
+	ctx storage.AppDatabaseContext
+}
+
+var _ RegistryPackageRepository = (*registryPackageRepository)(nil)
+
+func NewRegistryPackageRepository(ctx storage.AppDatabaseContext) RegistryPackageRepository {
+	return &registryPackageRepository{ctx: ctx}
+}
+
+// Query implements RegistryPackageRepository.
+func (r *registryPackageRepository) Query() (iter.Seq[*RegistryPackage], error) {
+	return sqlutil.QueryCommon[RegistryPackage](r.ctx, RegistryPackageTableName, "")
+}
+
+// BatchInsertOrUpdate implements RegistryPackageRepository.
+func (r *registryPackageRepository) BatchInsertOrUpdate(data []*RegistryPackage) error {
+	for _, d := range data {
+		if d.Ecosystem == nil || d.Name == nil || d.GitLink == nil || *d.GitLink == "" {
+			return ErrInvalidInput
+		}
+	}
+	for _, d := range data {
+		_, err := r.ctx.Exec(`INSERT INTO `+RegistryPackageTableName+` (ecosystem, name, git_link) VALUES ($1, $2, $3)
+			ON CONFLICT (ecosystem, name) DO UPDATE SET git_link = $3, update_time = now()`, *d.Ecosystem, *d.Name, *d.GitLink)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
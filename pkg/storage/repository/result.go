@@ -19,18 +19,25 @@ type ResultRepository interface {
 	QueryGitDetailsByScoreID(scoreID int) (iter.Seq[*ResultGitDetail], error)
 	QueryLangDetailsByScoreID(scoreID int) (iter.Seq[*ResultLangDetail], error)
 	QueryDistDetailsByScoreID(scoreID int) (iter.Seq[*ResultDistDetail], error)
+	QueryBreakdownByScoreID(scoreID int) (iter.Seq[*ScoreBreakdown], error)
 	QueryRankingCache(skip int, take int) (iter.Seq[*RankingResult], error)
+	// QueryRankingCacheByLicenseFamily only returns the rankings whose git
+	// metrics have a license of the family, see git.LicenseFamily
+	QueryRankingCacheByLicenseFamily(family string, skip int, take int) (iter.Seq[*RankingResult], error)
 	MakeRankingCache() error
 }
 
 type Result struct {
-	GitLink    *string
-	ScoreID    **int
-	DistScore  **float64
-	LangScore  **float64
-	GitScore   **float64
-	Score      **float64
-	UpdateTime **time.Time
+	GitLink     *string
+	ScoreID     **int
+	DistScore   **float64
+	LangScore   **float64
+	GitScore    **float64
+	Score       **float64
+	UpdateTime  **time.Time
+	Round       **int
+	ProfileName **string
+	ProfileHash **string
 }
 
 type RankingResult struct {
@@ -53,6 +60,19 @@ type ResultGitDetail struct {
 	OrgCount         **int
 	ContributorCount **int
 	UpdateTime       **time.Time
+	// see GitMetric
+	BusFactor50              **int `column:"bus_factor_50"`
+	BusFactor80              **int `column:"bus_factor_80"`
+	CommitGini               **float64
+	TopOrgShare              **float64
+	TopMaintainersLastCommit **time.Time
+	SemverTags               **int
+	Releases365d             **int `column:"releases_365d"`
+	LastRelease              **time.Time
+	MedianReleaseInterval    **float64
+	LicenseExpression        **string
+	LicenseCoverage          **LicenseCoverage
+	LicenseFamilies          **pq.StringArray
 }
 
 type ResultLangDetail struct {
@@ -80,6 +100,18 @@ func (r *resultRepository) QueryRankingCache(skip int, take int) (iter.Seq[*Rank
 	return rows, err
 }
 
+// QueryRankingCacheByLicenseFamily implements ResultRepository.
+func (r *resultRepository) QueryRankingCacheByLicenseFamily(family string, skip int, take int) (iter.Seq[*RankingResult], error) {
+	rows, err := sqlutil.Query[RankingResult](r.ctx, `select rc.* from rankings_cache rc
+	where exists (
+		select 1 from scores_git sg
+		join git_metrics gm on sg.git_metrics_id = gm.id
+		where sg.score_id = rc.score_id and gm.license_families @> array[$1])
+	order by rc.ranking
+	limit $2 offset $3`, family, take, skip)
+	return rows, err
+}
+
 func (r *resultRepository) MakeRankingCache() error {
 	_, err := r.ctx.Exec(`DROP TABLE IF EXISTS rankings_cache_tmp;
 	CREATE TABLE rankings_cache_tmp AS
@@ -145,7 +177,19 @@ func (r *resultRepository) QueryGitDetailsByScoreID(scoreID int) (iter.Seq[*Resu
 		gm.updated_since as updated_since,
 		gm.org_count as org_count,
 		gm.contributor_count as contributor_count,
-		gm.update_time as update_time
+		gm.update_time as update_time,
+		gm.bus_factor_50 as bus_factor_50,
+		gm.bus_factor_80 as bus_factor_80,
+		gm.commit_gini as commit_gini,
+		gm.top_org_share as top_org_share,
+		gm.top_maintainers_last_commit as top_maintainers_last_commit,
+		gm.semver_tags as semver_tags,
+		gm.releases_365d as releases_365d,
+		gm.last_release as last_release,
+		gm.median_release_interval as median_release_interval,
+		gm.license_expression as license_expression,
+		gm.license_coverage as license_coverage,
+		gm.license_families as license_families
 	from scores_git sg
 	left join git_metrics gm on sg.git_metrics_id = gm.id
 	where sg.score_id = $1`, scoreID)
@@ -163,6 +207,12 @@ func (r *resultRepository) QueryLangDetailsByScoreID(scoreID int) (iter.Seq[*Res
 	where sl.score_id = $1`, scoreID)
 }
 
+// QueryBreakdownByScoreID implements ResultRepository.
+func (r *resultRepository) QueryBreakdownByScoreID(scoreID int) (iter.Seq[*ScoreBreakdown], error) {
+	return sqlutil.QueryCommon[ScoreBreakdown](r.ctx, ScoreBreakdownTableName,
+		`where score_id = $1 order by id`, scoreID)
+}
+
 // QueryWithCountByLink implements ResultRepository.
 func (r *resultRepository) QueryByLink(search string, skip int, take int) (iter.Seq[*Result], error) {
 	rows, err := sqlutil.Query[Result](r.ctx, `select * from (
@@ -192,7 +242,10 @@ func (r *resultRepository) GetByScoreID(scoreID int) (*Result, error) {
 		s.lang_score as lang_score,
 		s.git_score as git_score,
 		s.score as score,
-		s.update_time as update_time
+		s.update_time as update_time,
+		s.round as round,
+		s.profile_name as profile_name,
+		s.profile_hash as profile_hash
 	from all_gitlinks_cache ag
 	left join scores s on ag.git_link = s.git_link
 	where s.id = $1
@@ -48,12 +48,33 @@ type Score struct {
 	Score            *float64
 	UpdateTime       *time.Time
 	Round            *int
+	// ProfileName and ProfileHash identify the scoring profile that
+	// produced this score
+	ProfileName **string
+	ProfileHash **string
+	// Breakdown records the contribution of every metric to this score
+	Breakdown []*ScoreBreakdown `ignore:"true"`
+}
+
+// ScoreBreakdown is the normalized value, weight and contribution of a
+// metric of a score. For the contribution of a whole dimension to the final
+// score, Metric equals Dimension.
+type ScoreBreakdown struct {
+	ID           *int64 `generated:"true"`
+	ScoreID      *int64
+	Dimension    *string
+	Metric       *string
+	Value        *float64
+	Normalized   *float64
+	Weight       *float64
+	Contribution *float64
 }
 
 const ScoreTableName = "scores"
 const ScoreDistTableName = "scores_dist"
 const ScoreLangTableName = "scores_lang"
 const ScoreGitTableName = "scores_git"
+const ScoreBreakdownTableName = "score_breakdowns"
 
 var _ ScoreRepository = (*scoreRepository)(nil)
 
@@ -196,6 +217,16 @@ func (s *scoreRepository) InsertOrUpdate(score *Score) error {
 		}
 	}
 
+	// Insert Breakdown
+	if len(score.Breakdown) != 0 {
+		for _, b := range score.Breakdown {
+			b.ScoreID = &id
+		}
+		if err := sqlutil.BatchInsert(s.ctx, ScoreBreakdownTableName, score.Breakdown); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
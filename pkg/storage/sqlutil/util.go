@@ -551,6 +551,7 @@ func Upsert[T any](ctx storage.AppDatabaseContext, into string, data *T) error {
 	if err != nil {
 		return err
 	}
+	defer rows.Close()
 	if rows.Next() {
 		scanGeneratedColumns(data, rows)
 	}
@@ -8,6 +8,8 @@ import (
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/url"
 )
 
 type Metrics struct {
@@ -48,8 +50,11 @@ func checkDistroValid(gitlink *sql.DB, repo string) [][]string {
 		}
 		if !strings.HasPrefix(link, "http://") && !strings.HasPrefix(link, "https://") && !strings.HasPrefix(link, "git://") {
 			invalidLinks = append(invalidLinks, []string{link, "invalid protocol"})
-		} else if strings.Contains(link, "/tree/") {
+		} else if canonical, err := url.Canonicalize(link); err != nil {
 			invalidLinks = append(invalidLinks, []string{link, "invalid link"})
+		} else if canonical != link {
+			// mapped to the canonical link by scripts/gitlink-aliases
+			invalidLinks = append(invalidLinks, []string{link, "not canonical: " + canonical})
 		}
 	}
 	return invalidLinks
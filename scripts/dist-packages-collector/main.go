@@ -1,6 +1,7 @@
 package main
 
 import (
+	"log"
 	"sync"
 
 	"github.com/HUSTSecLab/OpenSift/pkg/collector/alpine"
@@ -26,12 +27,44 @@ var (
 	workerCount = pflag.Int("worker", 1, "number of workers")
 	batchSize   = pflag.Int("batch", 1000, "batch size")
 	downloadDir = pflag.String("downloadDir", "./download", "download directory")
+	flagSource  = pflag.StringSlice("source", nil, "package index urls, file:// urls or local mirror directories to collect from instead of the default mirrors, requires --type")
+	flagTarget  = pflag.StringSlice("target", nil, "release/component/arch targets to collect instead of the default targets, e.g. testing/main/arm64, requires --type")
 )
 
+// indexCollector is a collector reading package index files, which can be
+// pointed to other sources and targets.
+type indexCollector interface {
+	SetSources(sources []string)
+	SetTargets(targets []string) error
+	Collect(outputPath string)
+}
+
+var indexCollectors = map[string]func() indexCollector{
+	"archlinux": func() indexCollector { return archlinux.NewArchLinuxCollector() },
+	"debian":    func() indexCollector { return debian.NewDebianCollector() },
+	"deepin":    func() indexCollector { return deepin.NewDeepinCollector() },
+	"ubuntu":    func() indexCollector { return ubuntu.NewUbuntuCollector() },
+	"fedora":    func() indexCollector { return fedora.NewFedoraCollector() },
+	"centos":    func() indexCollector { return centos.NewCentosCollector() },
+	"alpine":    func() indexCollector { return alpine.NewAlpineCollector() },
+	"aur":       func() indexCollector { return aur.NewAurCollector() },
+	"openeuler": func() indexCollector { return openeuler.NewOpenEulerCollector() },
+	"openkylin": func() indexCollector { return openkylin.NewOpenKylinCollector() },
+}
+
 func main() {
 	config.RegistCommonFlags(pflag.CommandLine)
 	config.ParseFlags(pflag.CommandLine)
 
+	if len(*flagSource) > 0 || len(*flagTarget) > 0 {
+		switch *flagType {
+		case "":
+			log.Fatalf("--source and --target require --type")
+		case "nix", "homebrew", "gentoo":
+			log.Fatalf("--source and --target are not supported for %s", *flagType)
+		}
+	}
+
 	if *flagType == "" {
 		var wg sync.WaitGroup
 		wg.Add(11)
@@ -92,32 +125,23 @@ func main() {
 		wg.Wait()
 	} else {
 		switch *flagType {
-		case "archlinux":
-			archlinux.NewArchLinuxCollector().Collect(*flagGenDot)
-		case "debian":
-			debian.NewDebianCollector().Collect(*flagGenDot)
-		case "deepin":
-			deepin.NewDeepinCollector().Collect(*flagGenDot)
-		case "ubuntu":
-			ubuntu.NewUbuntuCollector().Collect(*flagGenDot)
 		case "nix":
 			nix.NewNixCollector().Collect(*workerCount, *batchSize, *flagGenDot)
 		case "homebrew":
 			homebrew.NewHomebrewCollector().Collect(*flagGenDot, *downloadDir)
 		case "gentoo":
 			gentoo.NewGentooCollector().Collect(*flagGenDot, *downloadDir)
-		case "fedora":
-			fedora.NewFedoraCollector().Collect(*flagGenDot)
-		case "centos":
-			centos.NewCentosCollector().Collect(*flagGenDot)
-		case "alpine":
-			alpine.NewAlpineCollector().Collect(*flagGenDot)
-		case "aur":
-			aur.NewAurCollector().Collect(*flagGenDot)
-		case "openeuler":
-			openeuler.NewOpenEulerCollector().Collect(*flagGenDot)
-		case "openkylin":
-			openkylin.NewOpenKylinCollector().Collect(*flagGenDot)
+		default:
+			newCollector, ok := indexCollectors[*flagType]
+			if !ok {
+				log.Fatalf("unknown distribution type %s", *flagType)
+			}
+			c := newCollector()
+			c.SetSources(*flagSource)
+			if err := c.SetTargets(*flagTarget); err != nil {
+				log.Fatalf("%v", err)
+			}
+			c.Collect(*flagGenDot)
 		}
 	}
 }
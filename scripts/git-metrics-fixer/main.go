@@ -93,6 +93,10 @@ func main() {
 				UpdatedSince:     sqlutil.ToNullable(repo.UpdatedSince),
 				OrgCount:         sqlutil.ToNullable(repo.OrgCount),
 			}
+			repo.SetActivityMetrics(gitMetric)
+			repo.SetConcentrationMetrics(gitMetric)
+			repo.SetReleaseMetrics(gitMetric)
+			repo.SetLicenseMetrics(gitMetric)
 
 			mu.Lock()
 			InsertGitMeticAndFetch(ac, gitMetric)
@@ -68,8 +68,11 @@ func FetchPkg2Git(db *sql.DB, frompkg string, topkg string, repo string) (string
 	var fromgit sql.NullString
 	var togit sql.NullString
 
-	queryFrom := fmt.Sprintf("SELECT git_link FROM %s WHERE package = $1", repo + "_packages")
-	queryTo := fmt.Sprintf("SELECT git_link FROM %s WHERE package = $1", repo + "_packages")
+	// relationships of collectors aggregating binary packages by source are
+	// between the binary packages, see Collecter.UpdateRelationships, so a
+	// package is matched by its name or by its source
+	queryFrom := fmt.Sprintf("SELECT git_link FROM %s WHERE package = $1 OR source = $1 ORDER BY git_link IS NULL LIMIT 1", repo + "_packages")
+	queryTo := fmt.Sprintf("SELECT git_link FROM %s WHERE package = $1 OR source = $1 ORDER BY git_link IS NULL LIMIT 1", repo + "_packages")
 
 	err := db.QueryRow(queryFrom, frompkg).Scan(&fromgit)
 	if err != nil {
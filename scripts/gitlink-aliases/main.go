@@ -0,0 +1,66 @@
+// This program maps the git links of every source that are not canonical,
+// e.g. git@github.com:Owner/Repo.git, to their canonical link, so that the
+// repositories linked by several spellings are collected and scored once.
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/config"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/url"
+	"github.com/HUSTSecLab/OpenSift/pkg/logger"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/repository"
+	"github.com/spf13/pflag"
+)
+
+var flagDryRun = pflag.Bool("dry-run", false, "Only log the aliases, do not insert them")
+
+func main() {
+	pflag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "This program maps the git links that are not canonical to their canonical link.\n")
+		fmt.Fprintf(os.Stderr, "Usage of %s:\n", os.Args[0])
+		pflag.PrintDefaults()
+	}
+
+	config.RegistCommonFlags(pflag.CommandLine)
+	config.ParseFlags(pflag.CommandLine)
+	ac := storage.GetDefaultAppDatabaseContext()
+
+	ar := repository.NewGitLinkAliasRepository(ac)
+	aliases, err := ar.QueryAll()
+	if err != nil {
+		logger.Panicf("Querying aliases failed: %v", err)
+	}
+	links, err := repository.NewAllGitLinkRepository(ac).QuerySource()
+	if err != nil {
+		logger.Panicf("Querying git links failed: %v", err)
+	}
+
+	var inserted, invalid int
+	for link := range links {
+		// redirects are kept, their link is canonical already
+		if _, ok := aliases[link]; ok {
+			continue
+		}
+		canonical, err := url.Canonicalize(link)
+		if err != nil {
+			invalid++
+			logger.Warnf("Skipping %s: %v", link, err)
+			continue
+		}
+		if canonical == link {
+			continue
+		}
+		inserted++
+		logger.Infof("%s -> %s", link, canonical)
+		if *flagDryRun {
+			continue
+		}
+		if err := ar.Insert(link, canonical, repository.GitLinkAliasVariant); err != nil {
+			logger.Errorf("Inserting alias %s failed: %v", link, err)
+		}
+	}
+	logger.Infof("%d aliases inserted, %d invalid links skipped", inserted, invalid)
+}
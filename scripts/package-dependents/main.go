@@ -0,0 +1,31 @@
+// This program counts the dependents of the packages declared by the
+// repositories collected, for the language ecosystems which deps.dev does not
+// cover, and inserts them into lang_ecosystems for scoring.
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/config"
+	"github.com/HUSTSecLab/OpenSift/pkg/depsdev"
+	"github.com/HUSTSecLab/OpenSift/pkg/logger"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage"
+	"github.com/spf13/pflag"
+)
+
+func main() {
+	pflag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "This program counts the dependents of the packages of the ecosystems not covered by deps.dev.\n")
+		fmt.Fprintf(os.Stderr, "Usage of %s:\n", os.Args[0])
+		pflag.PrintDefaults()
+	}
+
+	config.RegistCommonFlags(pflag.CommandLine)
+	config.ParseFlags(pflag.CommandLine)
+
+	if err := depsdev.UpdatePackageDependents(storage.GetDefaultAppDatabaseContext()); err != nil {
+		logger.Panicf("Updating package dependents failed: %v", err)
+	}
+	logger.Info("Package dependents updated")
+}
@@ -0,0 +1,58 @@
+// This program indexes the repositories declaring every package in their
+// manifests, cross-validates the index with the repositories declared by the
+// registries, and stores it into package_links, where the packages whose
+// registry disagrees are reviewed by the labeling UI.
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/config"
+	"github.com/HUSTSecLab/OpenSift/pkg/depsdev"
+	"github.com/HUSTSecLab/OpenSift/pkg/logger"
+	"github.com/HUSTSecLab/OpenSift/pkg/score"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/repository"
+	"github.com/spf13/pflag"
+)
+
+var flagDryRun = pflag.Bool("dry-run", false, "only count the packages of every status, without storing them")
+
+func main() {
+	pflag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "This program indexes the repositories of the packages declared by the repositories collected.\n")
+		fmt.Fprintf(os.Stderr, "Usage of %s:\n", os.Args[0])
+		pflag.PrintDefaults()
+	}
+
+	config.RegistCommonFlags(pflag.CommandLine)
+	config.ParseFlags(pflag.CommandLine)
+
+	ac := storage.GetDefaultAppDatabaseContext()
+	aliases, err := repository.NewGitLinkAliasRepository(ac).QueryAll()
+	if err != nil {
+		logger.Panicf("Fetching git link aliases failed: %v", err)
+	}
+	copies := score.FetchGitLinkCopies(ac)
+	// a link is scored as the canonical repository of its alias, if it is
+	// a copy
+	resolve := func(link string) string {
+		if alias, ok := aliases[link]; ok {
+			link = alias
+		}
+		if canonical, ok := copies[link]; ok {
+			link = canonical
+		}
+		return link
+	}
+
+	counts, err := depsdev.UpdatePackageLinks(ac, resolve, *flagDryRun)
+	if err != nil {
+		logger.Panicf("Updating package links failed: %v", err)
+	}
+	logger.Infof("Package links updated: %d agree, %d disagree, %d unregistered",
+		counts[repository.PackageLinkAgree],
+		counts[repository.PackageLinkDisagree],
+		counts[repository.PackageLinkUnregistered])
+}
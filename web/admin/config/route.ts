@@ -66,6 +66,11 @@ const routes: IBestAFSRoute[] = [
         name: "发行版本链接",
         path: "/label/gitlink",
         component: "label/gitlink",
+      },
+      {
+        name: "语言生态包链接",
+        path: "/label/packages",
+        component: "label/packages",
       }
     ]
   },
@@ -0,0 +1,95 @@
+import { putAdminLabelPackagesGitlink } from '@/services/csapi/label';
+import { ProForm, ProFormText } from '@ant-design/pro-components';
+import { App, AutoComplete, Button, Result } from 'antd';
+import { useEffect } from 'react';
+
+type Props = {
+  data?: API.PackageLinkDTO;
+  onCancel?: () => void;
+  onRefresh?: () => void;
+  onNext?: () => void;
+  onPrev?: () => void;
+}
+
+export default function PackageLinkForm({ data, onCancel, onRefresh, onNext, onPrev }: Props) {
+  const [form] = ProForm.useForm<API.PackageLinkDTO>();
+  useEffect(() => {
+    if (data) {
+      form.setFieldsValue(data);
+    }
+  }, [data, form]);
+
+  if (!data) {
+    return <Result status="404" title="没有数据" subTitle="请先从表格中选择一个包。" />;
+  }
+
+  const { message, modal } = App.useApp();
+
+  const candidates = Array.from(new Set([
+    ...(data.manifestLinks || []),
+    ...(data.registryLink ? [data.registryLink] : []),
+  ]));
+
+  const onSubmit = async (values: API.PackageLinkDTO) => {
+    if (!data.ecosystem || !data.package) return;
+    await putAdminLabelPackagesGitlink({
+      ecosystem: data.ecosystem,
+      packageName: data.package,
+      link: values.labelLink,
+    });
+    message.success("更新成功");
+    onRefresh?.();
+  };
+
+  const confirmWrapper = (f?: () => void) => async () => {
+    if (form.getFieldsValue().labelLink !== data?.labelLink) {
+      const confirm = await modal.confirm({
+        title: '确认',
+        content: '您有未保存的更改，是否继续切换？',
+        okText: '放弃更改并继续',
+        cancelText: '取消',
+      });
+      if (confirm) {
+        f?.();
+      }
+    } else {
+      f?.();
+    }
+  };
+
+  return (<ProForm<API.PackageLinkDTO>
+    form={form}
+    onFinish={onSubmit}
+    submitter={{
+      render: (props, doms) => [
+        <Button key="prev" onClick={confirmWrapper(onPrev)}>
+          上一项
+        </Button>,
+        ...doms,
+        <Button key="submitNext" color='green' variant='solid' onClick={async () => {
+          await onSubmit(form.getFieldsValue());
+          onNext?.();
+        }}>
+          提交并下一项
+        </Button>,
+        <Button key="next" onClick={confirmWrapper(onNext)}>
+          下一项
+        </Button>,
+        <Button key="cancel" onClick={onCancel} >
+          取消
+        </Button>
+      ],
+    }}
+    layout="vertical"
+    grid={true}
+  >
+    <ProFormText name="ecosystem" label="生态" width="md" readonly />
+    <ProFormText name="package" label="包名" width="md" readonly />
+    <ProFormText name="registryLink" label="注册中心声明的仓库" width="md" readonly />
+
+    <ProForm.Item name="labelLink" label="Git Link" tooltip="可从清单文件或注册中心声明的仓库中选择，或直接输入，为空则清除标注。">
+      <AutoComplete allowClear style={{ width: 328 }}
+        options={candidates.map((link) => ({ label: link, value: link }))} />
+    </ProForm.Item>
+  </ProForm>)
+}
@@ -0,0 +1,177 @@
+import { getAdminLabelPackages } from "@/services/csapi/label";
+import { ProTable, ProColumns, ProFormInstance } from "@ant-design/pro-components";
+import { useRequest } from "ahooks";
+import { Button, Tag } from "antd";
+import React, { useImperativeHandle, useRef } from "react";
+
+type Props = {
+  selected?: API.PackageLinkDTO;
+  onSelected?: (data?: API.PackageLinkDTO) => void;
+}
+
+type Action = {
+  onNext?: () => void | Promise<void>;
+  onPrev?: () => void | Promise<void>;
+  refresh?: () => void | Promise<void>;
+}
+
+export type PackageLinkTableAction = Action;
+
+const keyOf = (record?: API.PackageLinkDTO) => `${record?.ecosystem}/${record?.package}`;
+
+const statusEnum = {
+  agree: { text: "一致", status: "Success" },
+  disagree: { text: "不一致", status: "Error" },
+  unregistered: { text: "未登记", status: "Default" },
+};
+
+export default React.forwardRef<Action, Props>(({ selected, onSelected }: Props, ref) => {
+  const form = useRef<ProFormInstance>();
+  const { data, params, runAsync, loading, refreshAsync } = useRequest<{
+    success: boolean;
+    total?: number;
+    data?: API.PackageLinkDTO[];
+  }, {
+    status?: string;
+    ecosystem?: string;
+    search?: string;
+    pageSize?: number;
+    current?: number;
+  }[]>(async (params) => {
+    const d = await getAdminLabelPackages({
+      status: params?.status,
+      ecosystem: params?.ecosystem,
+      search: params?.search,
+      skip: ((params?.current || 1) - 1) * (params?.pageSize || 20),
+      take: params?.pageSize || 20,
+    })
+    return {
+      data: d.items,
+      success: true,
+      total: d.total,
+    }
+  }, {
+    defaultParams: [{
+      status: 'disagree',
+      search: '',
+      pageSize: 20,
+      current: 1,
+    }],
+  });
+
+  useImperativeHandle(ref, () => ({
+    refresh: async () => { await refreshAsync() },
+    onNext: async () => {
+      // select next item
+      if (!selected) return;
+      const index = data?.data?.findIndex(item => keyOf(item) === keyOf(selected));
+      if (index === undefined || index === -1) return;
+      if (index + 1 < (data?.data?.length || 0)) {
+        onSelected?.(data?.data?.[index + 1]);
+      } else {
+        try {
+          const d = await runAsync({
+            ...params?.[params.length - 1],
+            current: (params?.[params.length - 1]?.current || 1) + 1,
+          });
+          onSelected?.(d?.data?.[0]);
+        } catch (e) {
+          console.error("Reload failed", e);
+        }
+      }
+    },
+    onPrev: async () => {
+      // select previous item
+      if (!selected) return;
+      const index = data?.data?.findIndex(item => keyOf(item) === keyOf(selected));
+      if (index === undefined || index === -1) return;
+      if (index - 1 >= 0) {
+        onSelected?.(data?.data?.[index - 1]);
+      } else {
+        if (params?.[params.length - 1]?.current === 1) return;
+        try {
+          const d = await runAsync({
+            ...params?.[params.length - 1],
+            current: Math.max((params?.[params.length - 1]?.current || 1) - 1, 1),
+          });
+          onSelected?.(d?.data?.[d?.data?.length - 1]);
+        } catch (e) {
+          console.error("Reload failed", e);
+        }
+      }
+    },
+  }));
+
+  const columns: ProColumns<API.PackageLinkDTO>[] = [
+    { title: '包名', hideInTable: true, dataIndex: 'search', valueType: 'text' },
+
+    { title: '生态', dataIndex: 'ecosystem', key: 'ecosystem', width: '10%' },
+    { title: '包名', hideInSearch: true, dataIndex: 'package', key: 'package' },
+    { title: '状态', dataIndex: 'status', key: 'status', valueType: 'select', valueEnum: statusEnum, initialValue: 'disagree', width: '10%' },
+    {
+      title: '清单文件声明的仓库',
+      hideInSearch: true,
+      dataIndex: 'manifestLinks',
+      key: 'manifest_links',
+      width: '25%',
+      render: (_, record) => record.manifestLinks?.map((link) => <Tag key={link}>{link}</Tag>),
+    },
+    { title: '注册中心声明的仓库', hideInSearch: true, dataIndex: 'registryLink', key: 'registry_link', width: '20%' },
+    { title: '标注', hideInSearch: true, dataIndex: 'labelLink', key: 'label_link', width: '20%' },
+    {
+      title: '操作',
+      hideInSearch: true,
+      key: 'action',
+      render: (_, record) => (
+        <Button type="link">编辑</Button>
+      ),
+    },
+  ]
+
+  return <ProTable<API.PackageLinkDTO, {
+    status?: string;
+    ecosystem?: string;
+    search?: string;
+  }>
+    columns={columns}
+    formRef={form}
+    scroll={{ x: 1000 }}
+    rowKey={keyOf}
+    rowSelection={{
+      onChange: (_, rows) => {
+        onSelected?.(rows[0]);
+      },
+      type: 'radio',
+      selectedRowKeys: selected ? [keyOf(selected)] : [],
+    }}
+    onRow={(record) => ({
+      onClick: () => {
+        onSelected?.(record);
+      },
+    })}
+    dataSource={data?.data || []}
+    pagination={{
+      showSizeChanger: true,
+      showQuickJumper: true,
+      pageSizeOptions: ['20', '50', '100'],
+      pageSize: params?.[params.length - 1]?.pageSize || 20,
+      current: params?.[params.length - 1]?.current || 1,
+      total: data?.total || 0,
+      onChange: (page, pageSize) => {
+        runAsync({
+          ...params?.[params.length - 1],
+          current: page,
+          pageSize,
+        });
+      },
+    }}
+    onSubmit={(values) => {
+      runAsync({
+        ...values,
+        current: 1,
+      });
+    }}
+    loading={loading}
+  >
+  </ ProTable>
+})
@@ -0,0 +1,61 @@
+import { PageContainer } from "@ant-design/pro-components";
+import { Card, Segmented, Splitter } from "antd";
+import PackageLinkForm from "./components/PackageLinkForm";
+import PackageLinkTable, { PackageLinkTableAction } from "./components/PackageLinkTable";
+import { useRef, useState } from "react";
+
+export default function () {
+  const [layout, setLayout] = useState<"horizontal" | "vertical">("horizontal");
+
+  const divRef = useRef<HTMLDivElement>(null);
+  const tableRef = useRef<PackageLinkTableAction>(null);
+
+  const [selected, setSelected] = useState<API.PackageLinkDTO | undefined>(undefined);
+
+  const scrollIntoSelected = () => {
+    if (divRef.current && selected) {
+      setTimeout(() => {
+        divRef.current?.querySelector(`.ant-table-row-selected`)?.scrollIntoView({
+          behavior: "smooth",
+          block: "nearest",
+          inline: "nearest",
+        });
+      }, 100);
+    }
+  }
+
+  return <PageContainer breadcrumb={undefined} extra={[
+    <Segmented key="layout" options={[
+      { label: "水平", value: "horizontal" },
+      { label: "垂直", value: "vertical" },
+    ]} value={layout} onChange={setLayout} />
+  ]}>
+    <Splitter layout={layout} style={{
+      height: "calc(100vh - 168px)",
+      gap: "12px",
+    }}>
+      <Splitter.Panel collapsible>
+        <div className="overflow-auto" ref={divRef} >
+          <PackageLinkTable selected={selected} onSelected={setSelected} ref={tableRef} />
+        </div>
+      </Splitter.Panel>
+      <Splitter.Panel collapsible>
+        <div className="overflow-auto" >
+          <Card>
+            <PackageLinkForm data={selected} onNext={async () => {
+              await tableRef.current?.onNext?.();
+              scrollIntoSelected();
+            }} onPrev={async () => {
+              await tableRef.current?.onPrev?.();
+              scrollIntoSelected();
+            }} onCancel={() => {
+              setSelected(undefined);
+            }} onRefresh={() => {
+              tableRef.current?.refresh?.();
+            }} />
+          </Card>
+        </div>
+      </Splitter.Panel>
+    </Splitter>
+  </PageContainer>
+}
@@ -62,3 +62,33 @@ export async function putAdminLabelDistributionsGitlink(
     ...(options || {}),
   });
 }
+
+/** 查询语言生态包链接列表 根据状态、生态、包名分页查询由清单文件索引的语言生态包仓库链接，及其与包注册中心的比对结果 GET /admin/label/packages */
+export async function getAdminLabelPackages(
+  // 叠加生成的Param类型 (非body参数swagger默认没有生成对象)
+  params: API.getAdminLabelPackagesParams,
+  options?: { [key: string]: any },
+) {
+  return request<API.PageDTOModelPackageLinkDTO>('/admin/label/packages', {
+    method: 'GET',
+    params: {
+      ...params,
+    },
+    ...(options || {}),
+  });
+}
+
+/** 标注语言生态包的 Git 链接 设置人工确认的语言生态包 Git 仓库链接，链接为空则清除标注 PUT /admin/label/packages/gitlink */
+export async function putAdminLabelPackagesGitlink(
+  body: API.UpdatePackageGitLinkReq,
+  options?: { [key: string]: any },
+) {
+  return request<any>('/admin/label/packages/gitlink', {
+    method: 'PUT',
+    headers: {
+      'Content-Type': 'application/json',
+    },
+    data: body,
+    ...(options || {}),
+  });
+}
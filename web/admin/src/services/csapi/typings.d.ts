@@ -32,6 +32,19 @@ declare namespace API {
     confidence?: number;
   };
 
+  type getAdminLabelPackagesParams = {
+    /** 比对状态（agree, disagree, unregistered），为空则查询全部 */
+    status?: string;
+    /** 生态名称 */
+    ecosystem?: string;
+    /** 包名过滤 */
+    search?: string;
+    /** 跳过数量 */
+    skip?: number;
+    /** 返回数量 */
+    take?: number;
+  };
+
   type getAdminSessionGithubCallbackParams = {
     /** GitHub OAuth Code */
     code: string;
@@ -156,6 +169,15 @@ declare namespace API {
     type: string;
   };
 
+  type PackageLinkDTO = {
+    ecosystem?: string;
+    labelLink?: string;
+    manifestLinks?: string[];
+    package?: string;
+    registryLink?: string;
+    status?: string;
+  };
+
   type PageDTOModelDistributionPackageDTO = {
     count?: number;
     items?: DistributionPackageDTO[];
@@ -170,6 +192,13 @@ declare namespace API {
     total?: number;
   };
 
+  type PageDTOModelPackageLinkDTO = {
+    count?: number;
+    items?: PackageLinkDTO[];
+    start?: number;
+    total?: number;
+  };
+
   type PageDTOModelRankingResultDTO = {
     count?: number;
     items?: RankingResultDTO[];
@@ -335,6 +364,12 @@ declare namespace API {
     packageName: string;
   };
 
+  type UpdatePackageGitLinkReq = {
+    ecosystem: string;
+    link?: string;
+    packageName: string;
+  };
+
   type UpdateWorkflowStatusReq = {
     running: boolean;
   };